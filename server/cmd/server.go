@@ -1,12 +1,15 @@
 package main
 
 import (
+	"context"
 	"github.com/lmittmann/tint"
 	"log/slog"
+	"os"
+	"os/signal"
 	"server/internal/monitor"
 	"server/internal/server"
 	"server/internal/vars"
-	"sync"
+	"syscall"
 	"time"
 )
 
@@ -21,16 +24,35 @@ func main() {
 		}),
 	))
 
-	wg := sync.WaitGroup{}
-	wg.Add(1)
+	// Server is shut down on SIGTERM/SIGINT, or from the console using /shutdown
+	ctx, stop := signal.NotifyContext(context.Background(), syscall.SIGTERM, os.Interrupt)
+	defer stop()
+	monitor.OnShutdown(stop)
 
+	// Log server outlives the booking server, so that the shutdown can be observed
+	logCtx, stopLogs := context.WithCancel(context.Background())
+	logDone := make(chan struct{})
 	go func() {
-		go monitor.Serve(vars.GetStaticEnv().ServerLogPort)
-		wg.Done()
+		defer close(logDone)
+		monitor.Serve(logCtx, vars.GetStaticEnv().ServerLogPort)
 	}()
 
-	wg.Wait()
-
 	slog.Info("Starting server!")
-	server.Serve(vars.GetStaticEnv().ServerPort)
+	s, err := server.New()
+	if err != nil {
+		slog.Error("Unable to create server", "err", err)
+		stopLogs()
+		<-logDone
+		return
+	}
+
+	monitor.AttachManager(s.Manager())
+	monitor.AttachStore(s.Store())
+	if err := s.Serve(ctx); err != nil {
+		slog.Error("Server stopped unexpectedly", "err", err)
+	}
+
+	slog.Info("Server has shut down, closing log server")
+	stopLogs()
+	<-logDone
 }
@@ -0,0 +1,190 @@
+package bookings
+
+import (
+	"fmt"
+	"log/slog"
+	"slices"
+	"time"
+)
+
+// maxAuditEntries is the number of audit entries a Manager without an AuditJournal keeps in memory, the oldest entries
+// are dropped beyond it.
+const maxAuditEntries = 10000
+
+// AuditState is the state of a booking or facility before or after a change recorded in an AuditEntry.
+type AuditState struct {
+	Start    time.Time     `json:"start,omitempty"`    // Of the booking, zero for facility changes
+	End      time.Time     `json:"end,omitempty"`      // Of the booking, zero for facility changes
+	Capacity int           `json:"capacity,omitempty"` // Of the facility, 0 for booking changes
+	Info     *FacilityInfo `json:"info,omitempty"`     // Of the facility, nil for booking changes
+}
+
+// AuditEntry records a change made to a single booking or facility of a Manager, and who requested the change.
+// Changes to several bookings at once (e.g. of a series) are recorded as one entry per booking.
+type AuditEntry struct {
+	Seq       uint64       `json:"seq"`
+	Time      time.Time    `json:"time"`
+	Actor     string       `json:"actor,omitempty"`      // Principal of the caller, empty if anonymous
+	Admin     bool         `json:"admin,omitempty"`      // Whether the caller is an admin, e.g. the console or server itself
+	Address   string       `json:"address,omitempty"`    // Address of the client, empty if not requested by a client
+	RequestId string       `json:"request_id,omitempty"` // Message Id of the request, empty if not requested by a client
+	Type      MutationType `json:"type"`
+	Facility  FacilityName `json:"facility,omitempty"`
+	BookingId uint64       `json:"booking_id,omitempty"` // Booking changed, 0 for facility changes
+	Before    *AuditState  `json:"before,omitempty"`     // nil if the booking or facility did not exist before
+	After     *AuditState  `json:"after,omitempty"`      // nil if the booking or facility no longer exists
+}
+
+// AuditFilter selects audit entries, zero fields match every entry.
+type AuditFilter struct {
+	Facility  FacilityName
+	BookingId uint64
+	From      time.Time // Entries made at or after From
+	To        time.Time // Entries made before To
+	AfterSeq  uint64    // Entries following the entry with this sequence, to page through entries
+}
+
+func (a AuditFilter) matches(e *AuditEntry) bool {
+	switch {
+	case e.Seq <= a.AfterSeq:
+		return false
+	case a.Facility != "" && e.Facility != a.Facility:
+		return false
+	case a.BookingId != 0 && e.BookingId != a.BookingId:
+		return false
+	case !a.From.IsZero() && e.Time.Before(a.From):
+		return false
+	case !a.To.IsZero() && !e.Time.Before(a.To):
+		return false
+	}
+	return true
+}
+
+func (t MutationType) String() string {
+	switch t {
+	case MutationFacilityCreate:
+		return "FacilityCreate"
+	case MutationFacilityDelete:
+		return "FacilityDelete"
+	case MutationFacilityUpdate:
+		return "FacilityUpdate"
+	case MutationBookingMake:
+		return "BookingMake"
+	case MutationBookingUpdate:
+		return "BookingUpdate"
+	case MutationBookingDelete:
+		return "BookingDelete"
+	case MutationBookingsMake:
+		return "BookingsMake"
+	case MutationSeriesMake:
+		return "SeriesMake"
+	case MutationSeriesUpdate:
+		return "SeriesUpdate"
+	case MutationSeriesDelete:
+		return "SeriesDelete"
+	case MutationWaitlistJoin:
+		return "WaitlistJoin"
+	case MutationWaitlistLeave:
+		return "WaitlistLeave"
+	case MutationReset:
+		return "Reset"
+	}
+	return fmt.Sprintf("MutationType(0x%02X)", uint8(t))
+}
+
+// bookingState returns the state of b, nil if b is nil.
+func bookingState(b *Booking) *AuditState {
+	if b == nil {
+		return nil
+	}
+	return &AuditState{Start: b.Start, End: b.End}
+}
+
+// facilityState returns the state of a facility with the given capacity and information.
+func facilityState(capacity int, info FacilityInfo) *AuditState {
+	info.Hours = slices.Clone(info.Hours)
+	return &AuditState{Capacity: capacity, Info: &info}
+}
+
+// audit appends an entry for a change requested by c to the audit log. Must be called with the write lock held, after
+// the change has been recorded.
+func (m *Manager) audit(c Caller, t MutationType, n FacilityName, bookingId uint64, before *AuditState, after *AuditState) {
+	m.auditSeq++
+	e := AuditEntry{
+		Seq:       m.auditSeq,
+		Time:      time.Now(),
+		Actor:     c.Principal,
+		Admin:     c.Admin,
+		Address:   c.Address,
+		RequestId: c.RequestId,
+		Type:      t,
+		Facility:  n,
+		BookingId: bookingId,
+		Before:    before,
+		After:     after,
+	}
+
+	// The change has already been made, so it is kept even if its entry cannot be recorded
+	if m.auditJournal != nil {
+		if err := m.auditJournal.AppendAudit(e); err != nil {
+			slog.Error("Unable to record audit entry", "Entry", e, "err", err)
+		}
+		return
+	}
+
+	if len(m.auditLog) >= maxAuditEntries {
+		m.auditLog = slices.Delete(m.auditLog, 0, len(m.auditLog)-maxAuditEntries+1)
+	}
+	m.auditLog = append(m.auditLog, e)
+}
+
+// auditOccurrences appends an entry for each occurrence of a series changed by c, given the occurrences before and
+// after the change. Must be called with the write lock held, after the change has been recorded.
+func (m *Manager) auditOccurrences(c Caller, t MutationType, n FacilityName, before []Booking, after []Booking) {
+	for i := range before {
+		var updated *Booking
+		if j := slices.IndexFunc(after, func(b Booking) bool { return b.Id == before[i].Id }); j >= 0 {
+			updated = &after[j]
+		}
+		m.audit(c, t, n, before[i].Id, bookingState(&before[i]), bookingState(updated))
+	}
+	for i := range after {
+		if !slices.ContainsFunc(before, func(b Booking) bool { return b.Id == after[i].Id }) {
+			m.audit(c, t, n, after[i].Id, nil, bookingState(&after[i]))
+		}
+	}
+}
+
+// QueryAudit returns up to limit entries of the audit log selected by filter, oldest first. A limit of 0 returns every
+// selected entry. Without an AuditJournal, only the latest entries since the server started are kept.
+func (m *Manager) QueryAudit(filter AuditFilter, limit int) ([]AuditEntry, error) {
+	var res []AuditEntry
+	collect := func(e *AuditEntry) bool {
+		if limit > 0 && len(res) == limit {
+			return false
+		}
+		if filter.matches(e) {
+			res = append(res, *e)
+		}
+		return true
+	}
+
+	m.RLock()
+	j := m.auditJournal
+	if j == nil {
+		defer m.RUnlock()
+		for i := range m.auditLog {
+			if !collect(&m.auditLog[i]) {
+				break
+			}
+		}
+		return res, nil
+	}
+	m.RUnlock()
+
+	// Entries are read without the lock held, as reading the whole journal may take a while
+	err := j.ReadAudit(func(e AuditEntry) bool {
+		return collect(&e)
+	})
+	return res, err
+}
@@ -0,0 +1,105 @@
+package bookings
+
+import (
+	"testing"
+	"time"
+)
+
+func TestManager_QueryAudit(t *testing.T) {
+	manager := NewManager()
+	facilityName := FacilityName("TestManager_QueryAudit")
+	alice := Caller{Principal: "alice", Address: "127.0.0.1:4051", RequestId: "00ff"}
+
+	if err := manager.NewFacilityAs(alice, facilityName, FacilityWithCapacity(2)); err != nil {
+		t.Fatal(err)
+	}
+	start := time.Now().Truncate(time.Hour).Add(time.Duration(24) * time.Hour)
+	id, err := manager.NewBookingWithUnusedIdAs(alice, facilityName, Booking{Owner: "alice", Start: start, End: start.Add(time.Hour)}, MaxLegacyBookingId)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := manager.ShiftBookingFromIdAs(alice, id, time.Hour); err != nil {
+		t.Fatal(err)
+	}
+	if err := manager.DeleteBookingFromId(id); err != nil {
+		t.Fatal(err)
+	}
+
+	// Changes to the booking are recorded with their state before and after, and who requested them
+	entries := queryAudit(t, manager, AuditFilter{BookingId: id}, 0)
+	if len(entries) != 3 {
+		t.Fatalf("Expected 3 entries for booking %d, got %v", id, entries)
+	}
+	made, shifted, deleted := entries[0], entries[1], entries[2]
+	if made.Type != MutationBookingMake || made.Before != nil || !made.After.Start.Equal(start) {
+		t.Errorf("Unexpected entry for booking made: %+v", made)
+	}
+	if made.Actor != alice.Principal || made.Address != alice.Address || made.RequestId != alice.RequestId {
+		t.Errorf("Expected entry to be requested by %+v, got %+v", alice, made)
+	}
+	if shifted.Type != MutationBookingUpdate || !shifted.Before.Start.Equal(start) || !shifted.After.Start.Equal(start.Add(time.Hour)) {
+		t.Errorf("Unexpected entry for booking shifted: %+v", shifted)
+	}
+	if deleted.Type != MutationBookingDelete || deleted.After != nil || !deleted.Admin || deleted.Actor != "" {
+		t.Errorf("Unexpected entry for booking deleted by the system: %+v", deleted)
+	}
+
+	// Entries are selected by facility and time, and paged through by sequence
+	if entries := queryAudit(t, manager, AuditFilter{Facility: facilityName}, 0); len(entries) != 4 || entries[0].After.Capacity != 2 {
+		t.Errorf("Expected 4 entries for the facility, starting with its creation, got %v", entries)
+	}
+	if entries := queryAudit(t, manager, AuditFilter{Facility: facilityName, AfterSeq: made.Seq}, 1); len(entries) != 1 || entries[0].Seq != shifted.Seq {
+		t.Errorf("Expected the entry following %d, got %v", made.Seq, entries)
+	}
+	if entries := queryAudit(t, manager, AuditFilter{From: time.Now().Add(time.Minute)}, 0); len(entries) != 0 {
+		t.Errorf("Expected no entries in the future, got %v", entries)
+	}
+	if entries := queryAudit(t, manager, AuditFilter{Facility: "Missing"}, 0); len(entries) != 0 {
+		t.Errorf("Expected no entries for a missing facility, got %v", entries)
+	}
+
+	// Changes to the information of the facility are recorded along with its capacity
+	if err := manager.UpdateFacilityAs(alice, facilityName, 2, FacilityInfo{Timezone: "Asia/Singapore"}); err != nil {
+		t.Fatal(err)
+	}
+	entries = queryAudit(t, manager, AuditFilter{Facility: facilityName, AfterSeq: deleted.Seq}, 0)
+	if len(entries) != 1 || entries[0].Before.Info == nil || entries[0].Before.Info.Timezone != "" || entries[0].After.Info == nil || entries[0].After.Info.Timezone != "Asia/Singapore" {
+		t.Errorf("Expected facility update to record its timezone, got %+v", entries)
+	}
+}
+
+func TestManager_QueryAudit_series(t *testing.T) {
+	manager := NewManager()
+	facilityName := FacilityName("TestManager_QueryAudit_series")
+	if err := manager.NewFacility(facilityName); err != nil {
+		t.Fatal(err)
+	}
+
+	start := time.Now().Truncate(time.Hour).Add(time.Duration(24) * time.Hour)
+	ids, err := manager.NewSeries(facilityName, Series{Id: 1, Frequency: FrequencyDaily, Start: start, End: start.Add(time.Hour), Count: 3})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := manager.DeleteSeriesFromId(1); err != nil {
+		t.Fatal(err)
+	}
+
+	// Each occurrence is recorded separately
+	for _, id := range ids {
+		entries := queryAudit(t, manager, AuditFilter{BookingId: id}, 0)
+		if len(entries) != 2 || entries[0].Type != MutationSeriesMake || entries[1].Type != MutationSeriesDelete {
+			t.Errorf("Expected occurrence %d to be made and deleted, got %v", id, entries)
+		}
+	}
+}
+
+// queryAudit queries the audit log of manager, failing t if it cannot be read.
+func queryAudit(t *testing.T, manager *Manager, filter AuditFilter, limit int) []AuditEntry {
+	t.Helper()
+
+	entries, err := manager.QueryAudit(filter, limit)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return entries
+}
@@ -0,0 +1,120 @@
+package bookings
+
+import (
+	"cmp"
+	"errors"
+	"fmt"
+	"log/slog"
+	"slices"
+)
+
+// BookingItem is one of the bookings made together by Manager.NewBookings.
+type BookingItem struct {
+	Facility FacilityName
+	Booking  Booking
+}
+
+// ConflictError is returned by Manager.NewBookings when one of the bookings cannot be made.
+type ConflictError struct {
+	Index int // Index of the booking that cannot be made
+	Err   error
+}
+
+func (e *ConflictError) Error() string {
+	return fmt.Sprintf("booking %d cannot be made: %v", e.Index, e.Err)
+}
+
+func (e *ConflictError) Unwrap() error {
+	return e.Err
+}
+
+// NewBookings makes every booking in items, assigning each booking an Id no larger than maxId that is not in use by any
+// booking. Either all bookings are made, or none are if any of them cannot be made, in which case a *ConflictError
+// identifies the booking. Returns the assigned Ids in the order of items.
+func (m *Manager) NewBookings(items []BookingItem, maxId uint64) ([]uint64, error) {
+	return m.NewBookingsAs(SystemCaller, items, maxId)
+}
+
+// NewBookingsAs is NewBookings on behalf of c.
+func (m *Manager) NewBookingsAs(c Caller, items []BookingItem, maxId uint64) ([]uint64, error) {
+	m.Lock()
+	defer m.Unlock()
+
+	if len(items) == 0 {
+		return nil, errors.New("at least one booking must be made")
+	}
+
+	var facilities []*Facility
+	ids := make([]uint64, len(items))
+	for i, item := range items {
+		f, exists := m.Facilities[item.Facility]
+		if !exists {
+			slog.Error("Attempted to book a Facility that does not exists!", "FacilityName", item.Facility)
+			return nil, &ConflictError{Index: i, Err: errors.New("facility does not exists")}
+		}
+		if !slices.Contains(facilities, f) {
+			facilities = append(facilities, f)
+		}
+
+		var err error
+		if ids[i], err = m.unusedBookingId(maxId, ids[:i]); err != nil {
+			return nil, err
+		}
+	}
+
+	unlock := lockFacilities(facilities)
+	defer unlock()
+
+	batch := make([]Mutation, len(items))
+	for i, item := range items {
+		b := item.Booking
+		b.Id = ids[i]
+		f := m.Facilities[item.Facility]
+
+		if err := f.book(b); err != nil {
+			for _, made := range batch[:i] {
+				m.Facilities[made.Facility].removeBooking(made.BookingId)
+			}
+			slog.Error("Unable to make bookings", "Index", i, "FacilityName", item.Facility, "Booking", b, "err", err)
+			m.monitor.Update(item.Facility, fmt.Sprintf("Error attempting to make booking at %s with %v together with other bookings.", item.Facility, b))
+			return nil, &ConflictError{Index: i, Err: err}
+		}
+		batch[i] = Mutation{Type: MutationBookingMake, Facility: item.Facility, Booking: &b, BookingId: b.Id}
+	}
+
+	if err := m.record(
+		Mutation{Type: MutationBookingsMake, Batch: batch},
+		func() {
+			for _, made := range batch {
+				m.Facilities[made.Facility].removeBooking(made.BookingId)
+			}
+		},
+	); err != nil {
+		return nil, err
+	}
+
+	for _, made := range batch {
+		m.bookingIndex[made.BookingId] = made.Facility
+		m.audit(c, MutationBookingsMake, made.Facility, made.BookingId, nil, bookingState(made.Booking))
+		slog.Info("Made successful booking", "FacilityName", made.Facility, "Booking", *made.Booking)
+		m.monitor.Update(made.Facility, fmt.Sprintf("Successfully made booking at %s with %v together with %d other bookings", made.Facility, *made.Booking, len(batch)-1))
+	}
+	return ids, nil
+}
+
+// lockFacilities locks every facility in fs in the order of their names, so that facilities that are locked together
+// are always locked in the same order. Returns a function that unlocks them again.
+func lockFacilities(fs []*Facility) func() {
+	sorted := slices.SortedFunc(slices.Values(fs), func(a, b *Facility) int {
+		return cmp.Compare(a.Name, b.Name)
+	})
+	for _, f := range sorted {
+		f.Lock()
+	}
+
+	return func() {
+		for _, f := range slices.Backward(sorted) {
+			f.Unlock()
+		}
+	}
+}
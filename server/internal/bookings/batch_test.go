@@ -0,0 +1,88 @@
+package bookings
+
+import (
+	"errors"
+	"math"
+	"testing"
+	"time"
+)
+
+func TestManager_NewBookings_atomic(t *testing.T) {
+	manager := NewManager()
+	for _, n := range []FacilityName{"Hall", "Projector"} {
+		if err := manager.NewFacility(n); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	start := time.Now().Truncate(time.Hour).Add(time.Duration(24) * time.Hour)
+	if err := manager.NewBooking("Projector", Booking{Id: 1, Start: start, End: start.Add(time.Hour)}); err != nil {
+		t.Fatal(err)
+	}
+
+	cases := []struct {
+		name  string
+		items []BookingItem
+		index int
+	}{
+		{
+			name: "clashingWithExisting",
+			items: []BookingItem{
+				{Facility: "Hall", Booking: Booking{Start: start, End: start.Add(time.Hour)}},
+				{Facility: "Projector", Booking: Booking{Start: start, End: start.Add(time.Hour)}},
+			},
+			index: 1,
+		},
+		{
+			name: "clashingWithEachOther",
+			items: []BookingItem{
+				{Facility: "Hall", Booking: Booking{Start: start, End: start.Add(time.Hour)}},
+				{Facility: "Projector", Booking: Booking{Start: start.Add(time.Hour), End: start.Add(time.Duration(2) * time.Hour)}},
+				{Facility: "Hall", Booking: Booking{Start: start.Add(time.Duration(30) * time.Minute), End: start.Add(time.Hour)}},
+			},
+			index: 2,
+		},
+		{
+			name: "missingFacility",
+			items: []BookingItem{
+				{Facility: "Hall", Booking: Booking{Start: start, End: start.Add(time.Hour)}},
+				{Facility: "Kitchen", Booking: Booking{Start: start, End: start.Add(time.Hour)}},
+			},
+			index: 1,
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			_, err := manager.NewBookings(c.items, math.MaxUint64)
+			var conflict *ConflictError
+			if !errors.As(err, &conflict) || conflict.Index != c.index {
+				t.Fatalf("E: conflict with booking %d, R: %v", c.index, err)
+			}
+
+			// None of the bookings have been made
+			records := manager.GetDeepCopyOfRecords()
+			if len(records["Hall"].Bookings) != 0 || len(records["Projector"].Bookings) != 1 {
+				t.Errorf("Expected no bookings to be made, got %v and %v", records["Hall"].Bookings, records["Projector"].Bookings)
+			}
+		})
+	}
+
+	ids, err := manager.NewBookings([]BookingItem{
+		{Facility: "Projector", Booking: Booking{Start: start.Add(time.Hour), End: start.Add(time.Duration(2) * time.Hour)}},
+		{Facility: "Hall", Booking: Booking{Start: start.Add(time.Hour), End: start.Add(time.Duration(2) * time.Hour)}},
+	}, MaxLegacyBookingId)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(ids) != 2 || ids[0] > MaxLegacyBookingId || ids[1] > MaxLegacyBookingId {
+		t.Fatalf("Expected 2 legacy Ids, got %v", ids)
+	}
+
+	// Bookings made together are changed like any other booking
+	for _, id := range ids {
+		if err := manager.DeleteBookingFromId(id); err != nil {
+			t.Error(err)
+		}
+	}
+}
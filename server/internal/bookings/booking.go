@@ -7,20 +7,35 @@ import (
 	"time"
 )
 
+// MaxLegacyBookingId is the largest booking Id that fits the payloads of methods carrying 16 bit booking Ids.
+const MaxLegacyBookingId = 0xFFFF
+
 type Booking struct {
-	Id    uint16
-	Start time.Time
-	End   time.Time
+	Id       uint64
+	SeriesId uint16 `json:",omitempty"` // Series the booking is an occurrence of, 0 if none
+	Owner    string `json:",omitempty"` // Principal that made the booking, empty if anyone may change it
+	Start    time.Time
+	End      time.Time
+
+	// HeldUntil is when a tentative booking is released unless it has been confirmed, zero for a confirmed booking.
+	HeldUntil time.Time
 }
 
 type BookingOption func(*Booking)
 
+// BookingWithRandomId draws a random Id; Manager assigns Ids that are not in use when making bookings.
 func BookingWithRandomId() BookingOption {
 
-	randomUint16 := uint16(rand.Uint32() & 0xFFFF) // Extract lower 16 bits
+	randomUint64 := rand.Uint64()
+
+	return func(b *Booking) {
+		b.Id = randomUint64
+	}
+}
 
+func BookingWithOwner(owner string) BookingOption {
 	return func(b *Booking) {
-		b.Id = randomUint16
+		b.Owner = owner
 	}
 }
 
@@ -56,16 +71,27 @@ func (b *Booking) validate() error {
 	return nil
 }
 
+// IsHeld reports whether b is a tentative booking that has not been confirmed yet.
+func (b *Booking) IsHeld() bool {
+	return !b.HeldUntil.IsZero()
+}
+
 func (b *Booking) Overlaps(other *Booking) bool {
 	return b.Start.Before(other.End) && other.Start.Before(b.End)
 }
 
+// GetIdAsBytes returns the Id as a 16 bit legacy Id, which b.Id must not exceed.
 func (b *Booking) GetIdAsBytes() []byte {
 	bin := make([]byte, 2)
-	binary.BigEndian.PutUint16(bin, b.Id)
+	binary.BigEndian.PutUint16(bin, uint16(b.Id))
 	return bin
 }
 
+// GetWideIdAsBytes returns the Id as 8 bytes.
+func (b *Booking) GetWideIdAsBytes() []byte {
+	return binary.BigEndian.AppendUint64(nil, b.Id)
+}
+
 // DeepCopy returns a new pointer to a Booking that is a deep copy of b.
 func (b *Booking) DeepCopy() *Booking {
 	if b == nil {
@@ -0,0 +1,84 @@
+package bookings
+
+import (
+	"cmp"
+	"errors"
+	"fmt"
+	"log/slog"
+	"slices"
+	"time"
+)
+
+// BookingCursor is the position of a booking in a listing, so that the following bookings can be listed next. The
+// zero BookingCursor is before the first booking.
+type BookingCursor struct {
+	Start time.Time
+	Id    uint64
+}
+
+// Cursor returns the position of b in a listing, to list the bookings following b.
+func (b *Booking) Cursor() BookingCursor {
+	return BookingCursor{Start: b.Start, Id: b.Id}
+}
+
+// compare orders bookings by start, then by Id.
+func (c BookingCursor) compare(other BookingCursor) int {
+	if n := c.Start.Compare(other.Start); n != 0 {
+		return n
+	}
+	return cmp.Compare(c.Id, other.Id)
+}
+
+// bookingsBetween returns copies of the bookings of f that overlap start to end and follow after, ordered by start
+// then Id.
+func (f *Facility) bookingsBetween(start time.Time, end time.Time, after BookingCursor) []Booking {
+	f.Lock()
+	defer f.Unlock()
+	f.clean()
+
+	var res []Booking
+	for _, b := range f.Bookings {
+		if !b.Start.Before(end) {
+			break // Bookings are ordered by start
+		}
+		if !b.End.After(start) || (after != BookingCursor{} && b.Cursor().compare(after) <= 0) {
+			continue
+		}
+		res = append(res, *b)
+	}
+
+	slices.SortFunc(res, func(a, b Booking) int {
+		return a.Cursor().compare(b.Cursor())
+	})
+	return res
+}
+
+// ListBookingsAs returns up to limit bookings of the facility that overlap start to end, ordered by start then Id, on
+// behalf of c. A limit of 0 returns every booking. The bookings following the last one returned are listed by setting
+// after to its Cursor. Owners are left out of bookings that c may not change.
+func (m *Manager) ListBookingsAs(c Caller, n FacilityName, start time.Time, end time.Time, after BookingCursor, limit int) ([]Booking, error) {
+	m.RLock()
+	defer m.RUnlock()
+
+	if !start.Before(end) {
+		return nil, errors.New("listing must end after it starts")
+	}
+
+	f, exists := m.Facilities[n]
+	if !exists {
+		slog.Error("Facility does not exist!", "FacilityName", n)
+		return nil, errors.New("facility does not exist")
+	}
+	m.monitor.Update(n, fmt.Sprintf("Executing listing of bookings of %s from %v to %v", n, start, end))
+
+	res := f.bookingsBetween(start, end, after)
+	if limit > 0 && len(res) > limit {
+		res = res[:limit]
+	}
+	for i := range res {
+		if !c.mayChange(res[i].Owner) {
+			res[i].Owner = ""
+		}
+	}
+	return res, nil
+}
@@ -0,0 +1,67 @@
+package bookings
+
+import (
+	"slices"
+	"testing"
+	"time"
+)
+
+func TestManager_ListBookingsAs(t *testing.T) {
+	manager := NewManager()
+	facilityName := FacilityName("TestManager_ListBookingsAs")
+	if err := manager.NewFacility(facilityName, FacilityWithCapacity(2)); err != nil {
+		t.Fatal(err)
+	}
+
+	start := time.Now().Truncate(time.Hour).Add(time.Duration(24) * time.Hour)
+	for _, b := range []Booking{
+		{Id: 4, Owner: "alice", Start: start, End: start.Add(time.Hour)},
+		{Id: 2, Owner: "bob", Start: start, End: start.Add(time.Hour)},
+		{Id: 3, Start: start.Add(time.Hour), End: start.Add(time.Duration(2) * time.Hour)},
+		{Id: 1, Start: start.Add(time.Duration(5) * time.Hour), End: start.Add(time.Duration(6) * time.Hour)},
+	} {
+		if err := manager.NewBooking(facilityName, b); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	ids := func(bookings []Booking) []uint64 {
+		var res []uint64
+		for _, b := range bookings {
+			res = append(res, b.Id)
+		}
+		return res
+	}
+	alice := Caller{Principal: "alice"}
+
+	// Bookings overlapping the window are ordered by start, then by Id
+	res, err := manager.ListBookingsAs(alice, facilityName, start.Add(time.Duration(30)*time.Minute), start.Add(time.Duration(5)*time.Hour), BookingCursor{}, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !slices.Equal(ids(res), []uint64{2, 4, 3}) {
+		t.Errorf("E: %v, R: %v", []uint64{2, 4, 3}, ids(res))
+	}
+
+	// Owners are only listed for bookings the caller may change
+	if res[0].Owner != "" || res[1].Owner != "alice" {
+		t.Errorf("Expected only the caller's own owner to be listed, got %q and %q", res[0].Owner, res[1].Owner)
+	}
+	if admin, _ := manager.ListBookingsAs(SystemCaller, facilityName, start, start.Add(time.Hour), BookingCursor{}, 1); admin[0].Owner != "bob" {
+		t.Errorf("Expected admins to see every owner, got %q", admin[0].Owner)
+	}
+
+	// Pages follow the cursor of the last booking of the previous page
+	first, _ := manager.ListBookingsAs(alice, facilityName, start, start.Add(time.Duration(24)*time.Hour), BookingCursor{}, 2)
+	next, _ := manager.ListBookingsAs(alice, facilityName, start, start.Add(time.Duration(24)*time.Hour), first[len(first)-1].Cursor(), 2)
+	if !slices.Equal(ids(first), []uint64{2, 4}) || !slices.Equal(ids(next), []uint64{3, 1}) {
+		t.Errorf("Unexpected pages %v and %v", ids(first), ids(next))
+	}
+
+	if _, err := manager.ListBookingsAs(alice, facilityName, start, start, BookingCursor{}, 0); err == nil {
+		t.Error("Expected empty window to be rejected")
+	}
+	if _, err := manager.ListBookingsAs(alice, "Unknown", start, start.Add(time.Hour), BookingCursor{}, 0); err == nil {
+		t.Error("Expected unknown facility to be rejected")
+	}
+}
@@ -0,0 +1,32 @@
+package bookings
+
+import (
+	"errors"
+	"time"
+)
+
+// ErrForbidden is returned when a caller changes a booking or series owned by someone else.
+var ErrForbidden = errors.New("caller is not the owner of the booking")
+
+// Caller identifies who requests a change, so that bookings can only be changed by their owner, and so that the change
+// can be audited.
+type Caller struct {
+	Principal string // Identity supplied by the client, empty for an anonymous caller
+	Admin     bool   // Admins may change any booking
+	Address   string // Address of the client, empty if not requested by a client
+	RequestId string // Message Id of the request, empty if not requested by a client
+
+	// IdempotencyKey is supplied by the client, so that a request made again with the same key (e.g. after the client
+	// restarted) is answered with the outcome of the original request instead of being executed again. Keys are unique
+	// per principal and require one, and are remembered for IdempotencyTTL. Empty executes every request.
+	IdempotencyKey string
+	IdempotencyTTL time.Duration
+}
+
+// SystemCaller makes changes that are not requested on behalf of a client, e.g. from the console.
+var SystemCaller = Caller{Admin: true}
+
+// mayChange reports whether c may change something owned by owner. Anything without an owner may be changed by anyone.
+func (c Caller) mayChange(owner string) bool {
+	return owner == "" || c.Admin || c.Principal == owner
+}
@@ -0,0 +1,93 @@
+package bookings
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestManager_ShiftBookingFromIdAs_owner(t *testing.T) {
+	manager := NewManager()
+	facilityName := FacilityName("TestManager_ShiftBookingFromIdAs_owner")
+	if err := manager.NewFacility(facilityName); err != nil {
+		t.Fatal(err)
+	}
+
+	start := time.Now().Add(time.Hour)
+	owned := Booking{Id: 1, Owner: "alice", Start: start, End: start.Add(time.Hour)}
+	unowned := Booking{Id: 2, Start: start.Add(time.Duration(2) * time.Hour), End: start.Add(time.Duration(3) * time.Hour)}
+	for _, b := range []Booking{owned, unowned} {
+		if err := manager.NewBooking(facilityName, b); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	alice := Caller{Principal: "alice"}
+	bob := Caller{Principal: "bob"}
+	admin := Caller{Principal: "admin", Admin: true}
+
+	// Only the owner and admins may change an owned booking
+	for _, c := range []Caller{bob, {}} {
+		if err := manager.ShiftBookingFromIdAs(c, owned.Id, time.Minute); !errors.Is(err, ErrForbidden) {
+			t.Errorf("E: %v, R: %v", ErrForbidden, err)
+		}
+		if err := manager.DeleteBookingFromIdAs(c, owned.Id); !errors.Is(err, ErrForbidden) {
+			t.Errorf("E: %v, R: %v", ErrForbidden, err)
+		}
+	}
+	for _, c := range []Caller{alice, admin, SystemCaller} {
+		if err := manager.ShiftBookingFromIdAs(c, owned.Id, time.Minute); err != nil {
+			t.Error(err)
+		}
+	}
+
+	// Anyone may change a booking without an owner
+	if err := manager.ShiftBookingFromIdAs(bob, unowned.Id, time.Minute); err != nil {
+		t.Error(err)
+	}
+	if err := manager.DeleteBookingFromIdAs(bob, unowned.Id); err != nil {
+		t.Error(err)
+	}
+
+	// The owner is kept when the booking is shifted
+	if b := manager.GetDeepCopyOfRecords()[facilityName].BookingMap[owned.Id]; b == nil || b.Owner != owned.Owner {
+		t.Errorf("Expected owner %q to be kept, got %v", owned.Owner, b)
+	}
+	if err := manager.DeleteBookingFromIdAs(alice, owned.Id); err != nil {
+		t.Error(err)
+	}
+}
+
+func TestManager_ShiftSeriesFromIdAs_owner(t *testing.T) {
+	manager := NewManager()
+	facilityName := FacilityName("TestManager_ShiftSeriesFromIdAs_owner")
+	if err := manager.NewFacility(facilityName); err != nil {
+		t.Fatal(err)
+	}
+
+	s := newTestSeries(t, SeriesWithCount(3), SeriesWithOwner("alice"))
+	ids, err := manager.NewSeries(facilityName, s)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Occurrences are owned by the owner of the series
+	bob := Caller{Principal: "bob"}
+	if err := manager.DeleteBookingFromIdAs(bob, ids[0]); !errors.Is(err, ErrForbidden) {
+		t.Errorf("E: %v, R: %v", ErrForbidden, err)
+	}
+	if err := manager.ShiftSeriesFromIdAs(bob, s.Id, time.Hour); !errors.Is(err, ErrForbidden) {
+		t.Errorf("E: %v, R: %v", ErrForbidden, err)
+	}
+	if err := manager.DeleteSeriesFromIdAs(bob, s.Id); !errors.Is(err, ErrForbidden) {
+		t.Errorf("E: %v, R: %v", ErrForbidden, err)
+	}
+
+	alice := Caller{Principal: "alice"}
+	if err := manager.ShiftSeriesFromIdAs(alice, s.Id, time.Hour); err != nil {
+		t.Error(err)
+	}
+	if err := manager.DeleteSeriesFromIdAs(alice, s.Id); err != nil {
+		t.Error(err)
+	}
+}
@@ -0,0 +1,106 @@
+package bookings
+
+import (
+	"errors"
+	"fmt"
+	"log/slog"
+	"slices"
+	"time"
+)
+
+// FreeIntervals returns the periods from start to end during which the facility is open and has capacity left,
+// earliest first. Adjacent periods are merged, so that no two returned periods touch each other.
+func (f *Facility) FreeIntervals(start time.Time, end time.Time) []Interval {
+	f.Lock()
+	defer f.Unlock()
+	f.clean()
+
+	// Whether the facility is free only changes when a booking starts or ends, or when the opening hours change
+	changes := []time.Time{start, end}
+	for _, b := range f.Bookings {
+		for _, t := range []time.Time{b.Start, b.End} {
+			if t.After(start) && t.Before(end) {
+				changes = append(changes, t)
+			}
+		}
+	}
+	loc := f.Info.location()
+	changes = append(changes, f.Info.hourChanges(loc, start, end)...)
+	slices.SortFunc(changes, time.Time.Compare)
+	changes = slices.CompactFunc(changes, time.Time.Equal)
+
+	var res []Interval
+	for i := 1; i < len(changes); i++ {
+		from, to := changes[i-1], changes[i]
+		if !f.Info.isOpen(loc, from, to) || maxConcurrent(f.Bookings, from, to) >= f.Capacity {
+			continue
+		}
+		if len(res) > 0 && res[len(res)-1].End.Equal(from) {
+			res[len(res)-1].End = to
+			continue
+		}
+		res = append(res, Interval{Start: from, End: to})
+	}
+	return res
+}
+
+// intersectIntervals returns the periods that are in both a and b, which must be sorted and not overlap each other.
+func intersectIntervals(a []Interval, b []Interval) []Interval {
+	var res []Interval
+	for i, j := 0, 0; i < len(a) && j < len(b); {
+		start, end := a[i].Start, a[i].End
+		if b[j].Start.After(start) {
+			start = b[j].Start
+		}
+		if b[j].End.Before(end) {
+			end = b[j].End
+		}
+		if start.Before(end) {
+			res = append(res, Interval{Start: start, End: end})
+		}
+
+		// Move on from whichever period ends first, as it cannot overlap anything else
+		if a[i].End.Before(b[j].End) {
+			i++
+		} else {
+			j++
+		}
+	}
+	return res
+}
+
+// CommonFreeTime returns the periods from start to end, lasting at least minDuration, during which every facility in
+// names is free (see Facility.FreeIntervals), earliest first.
+func (m *Manager) CommonFreeTime(names []FacilityName, start time.Time, end time.Time, minDuration time.Duration) ([]Interval, error) {
+	m.RLock()
+	defer m.RUnlock()
+
+	switch {
+	case len(names) == 0:
+		return nil, errors.New("at least one facility must be searched")
+	case !start.Before(end):
+		return nil, errors.New("search must end after it starts")
+	case minDuration < 0:
+		return nil, errors.New("minimum duration must not be negative")
+	}
+
+	facilities := make([]*Facility, len(names))
+	for i, n := range names {
+		f, exists := m.Facilities[n]
+		if !exists {
+			slog.Error("Facility does not exist!", "FacilityName", n)
+			return nil, fmt.Errorf("facility %s does not exist", n)
+		}
+		facilities[i] = f
+	}
+
+	res := []Interval{{Start: start, End: end}}
+	for i, f := range facilities {
+		m.monitor.Update(names[i], fmt.Sprintf("Executing search for free time common to %s and %d other facilities", names[i], len(names)-1))
+		res = intersectIntervals(res, f.FreeIntervals(start, end))
+	}
+
+	return slices.DeleteFunc(res, func(i Interval) bool {
+		return i.End.Sub(i.Start) < minDuration
+	}), nil
+}
@@ -0,0 +1,85 @@
+package bookings
+
+import (
+	"testing"
+	"time"
+)
+
+func TestFacility_FreeIntervals(t *testing.T) {
+	now := time.Now()
+	tmr := time.Date(now.Year(), now.Month(), now.Day()+1, 0, 0, 0, 0, time.Local)
+
+	hours := make([]OpeningHours, 7)
+	for i := range hours {
+		hours[i] = OpeningHours{Open: time.Duration(9) * time.Hour, Close: time.Duration(17) * time.Hour}
+	}
+	f := NewFacility(FacilityName("Testing"), FacilityWithCapacity(2), FacilityWithInfo(FacilityInfo{Hours: hours}))
+	for _, b := range []Booking{
+		{Id: 1, Start: tmr.Add(time.Duration(10) * time.Hour), End: tmr.Add(time.Duration(12) * time.Hour)},
+		{Id: 2, Start: tmr.Add(time.Duration(11) * time.Hour), End: tmr.Add(time.Duration(13) * time.Hour)},
+	} {
+		if err := f.Book(b); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	// The facility is full from 11 to 12 and closed outside of 9 to 17
+	r := f.FreeIntervals(tmr, tmr.Add(time.Duration(24)*time.Hour))
+	e := []Interval{
+		{Start: tmr.Add(time.Duration(9) * time.Hour), End: tmr.Add(time.Duration(11) * time.Hour)},
+		{Start: tmr.Add(time.Duration(12) * time.Hour), End: tmr.Add(time.Duration(17) * time.Hour)},
+	}
+	if !equalIntervals(r, e) {
+		t.Errorf("E: %v, R: %v", e, r)
+	}
+}
+
+func TestManager_CommonFreeTime(t *testing.T) {
+	manager := NewManager()
+	for _, n := range []FacilityName{"Hall", "Projector"} {
+		if err := manager.NewFacility(n); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	now := time.Now()
+	tmr := time.Date(now.Year(), now.Month(), now.Day()+1, 0, 0, 0, 0, time.Local)
+	if err := manager.NewBooking("Hall", Booking{Id: 1, Start: tmr.Add(time.Duration(10) * time.Hour), End: tmr.Add(time.Duration(11) * time.Hour)}); err != nil {
+		t.Fatal(err)
+	}
+	if err := manager.NewBooking("Projector", Booking{Id: 2, Start: tmr.Add(time.Duration(12) * time.Hour), End: tmr.Add(time.Duration(15) * time.Hour)}); err != nil {
+		t.Fatal(err)
+	}
+
+	// The free time from 11 to 12 is shorter than the minimum duration
+	r, err := manager.CommonFreeTime([]FacilityName{"Hall", "Projector"}, tmr.Add(time.Duration(9)*time.Hour), tmr.Add(time.Duration(17)*time.Hour), time.Duration(90)*time.Minute)
+	if err != nil {
+		t.Fatal(err)
+	}
+	e := []Interval{
+		{Start: tmr.Add(time.Duration(15) * time.Hour), End: tmr.Add(time.Duration(17) * time.Hour)},
+	}
+	if !equalIntervals(r, e) {
+		t.Errorf("E: %v, R: %v", e, r)
+	}
+
+	r, err = manager.CommonFreeTime([]FacilityName{"Hall", "Projector"}, tmr.Add(time.Duration(9)*time.Hour), tmr.Add(time.Duration(17)*time.Hour), time.Hour)
+	if err != nil {
+		t.Fatal(err)
+	}
+	e = []Interval{
+		{Start: tmr.Add(time.Duration(9) * time.Hour), End: tmr.Add(time.Duration(10) * time.Hour)},
+		{Start: tmr.Add(time.Duration(11) * time.Hour), End: tmr.Add(time.Duration(12) * time.Hour)},
+		{Start: tmr.Add(time.Duration(15) * time.Hour), End: tmr.Add(time.Duration(17) * time.Hour)},
+	}
+	if !equalIntervals(r, e) {
+		t.Errorf("E: %v, R: %v", e, r)
+	}
+
+	if _, err := manager.CommonFreeTime([]FacilityName{"Hall", "Missing"}, tmr, tmr.Add(time.Hour), 0); err == nil {
+		t.Error("Expected search of a missing facility to be rejected")
+	}
+	if _, err := manager.CommonFreeTime(nil, tmr, tmr.Add(time.Hour), 0); err == nil {
+		t.Error("Expected search of no facilities to be rejected")
+	}
+}
@@ -2,6 +2,7 @@ package bookings
 
 import (
 	"errors"
+	"fmt"
 	"log/slog"
 	"slices"
 	"sync"
@@ -13,16 +14,39 @@ type FacilityName string
 type Facility struct {
 	sync.RWMutex
 	Name       FacilityName
+	Capacity   int // Number of bookings allowed at the same time, e.g. the number of identical rooms
+	Info       FacilityInfo
 	Bookings   []*Booking
-	BookingMap map[uint16]*Booking
+	BookingMap map[uint64]*Booking
+	Series     map[uint16]*Series
+	Waitlist   []*Booking // Bookings waiting for a clash to clear, in the order they joined
 }
 
-func NewFacility(name FacilityName) *Facility {
-	return &Facility{
+type FacilityOption func(*Facility)
+
+// FacilityWithCapacity sets the number of bookings allowed at the same time, which defaults to 1.
+func FacilityWithCapacity(capacity int) FacilityOption {
+	return func(f *Facility) {
+		f.Capacity = capacity
+	}
+}
+
+// ClosedSlot marks a slot during which the facility is closed in the result of QueryCapacity. Only QueryCapacity
+// distinguishes closed slots from booked slots; QueryAvailability sets the same bit for both.
+const ClosedSlot = 0xFF
+
+func NewFacility(name FacilityName, opts ...FacilityOption) *Facility {
+	f := &Facility{
 		Name:       name,
+		Capacity:   1,
 		Bookings:   []*Booking{},
-		BookingMap: make(map[uint16]*Booking),
+		BookingMap: make(map[uint64]*Booking),
+		Series:     make(map[uint16]*Series),
+	}
+	for _, o := range opts {
+		o(f)
 	}
+	return f
 }
 
 // clean clears up outdated bookings from the system
@@ -30,28 +54,70 @@ func (f *Facility) clean() {
 
 	currentTime := time.Now()
 
-	// Remove bookings that have already finished
+	// Remove bookings that have already finished, so that their Ids can be reused
 	f.Bookings = slices.DeleteFunc(f.Bookings, func(b *Booking) bool {
-		return b.End.Before(currentTime)
+		if b.End.Before(currentTime) {
+			delete(f.BookingMap, b.Id)
+			return true
+		}
+		return false
+	})
+
+	// Remove waitlisted bookings that can no longer be booked in time
+	f.Waitlist = slices.DeleteFunc(f.Waitlist, func(w *Booking) bool {
+		return w.Start.Before(currentTime)
 	})
+
+	// Remove series that no longer have any occurrences
+	for id := range f.Series {
+		if !slices.ContainsFunc(f.Bookings, func(b *Booking) bool { return b.SeriesId == id }) {
+			delete(f.Series, id)
+		}
+	}
 }
 
-// insertBooking attempts to insert a booking in sorted order
-func (f *Facility) insertBooking(newBooking *Booking) bool {
-	// Find the correct insertion index
-	index, found := slices.BinarySearchFunc(f.Bookings, newBooking, func(a, b *Booking) int {
-		if a.Start.Before(b.Start) {
-			return -1
+// maxConcurrent returns the largest number of bookings overlapping at any instant between start and end.
+// bookings must be sorted by start time.
+func maxConcurrent(bookings []*Booking, start time.Time, end time.Time) int {
+	window := &Booking{Start: start, End: end}
+
+	var overlapping []*Booking
+	for _, b := range bookings {
+		if !b.Start.Before(end) {
+			break
+		}
+		if b.Overlaps(window) {
+			overlapping = append(overlapping, b)
+		}
+	}
+
+	// The number of overlapping bookings only increases at the start of a booking (or of the window)
+	res := 0
+	for i, candidate := range overlapping {
+		at := candidate.Start
+		if at.Before(start) {
+			at = start
 		}
-		if a.Start.After(b.Start) {
-			return 1
+		count := 0
+		for _, b := range overlapping[:i+1] {
+			if b.End.After(at) {
+				count++
+			}
 		}
-		return 0
+		res = max(res, count)
+	}
+	return res
+}
+
+// insertBooking attempts to insert a booking in sorted order, as long as the facility's capacity is not exceeded
+func (f *Facility) insertBooking(newBooking *Booking) bool {
+	// Find the correct insertion index
+	index, _ := slices.BinarySearchFunc(f.Bookings, newBooking, func(a, b *Booking) int {
+		return a.Start.Compare(b.Start)
 	})
 
-	// Ensure no overlap
-	if found || (index > 0 && f.Bookings[index-1].Overlaps(newBooking)) ||
-		(index < len(f.Bookings) && f.Bookings[index].Overlaps(newBooking)) {
+	// Ensure capacity is not exceeded by overlaps
+	if maxConcurrent(f.Bookings, newBooking.Start, newBooking.End) >= f.Capacity {
 		return false // Conflict detected
 	}
 
@@ -64,44 +130,118 @@ func (f *Facility) insertBooking(newBooking *Booking) bool {
 // QueryAvailability searches for availability of the facility for the next number of nDays (including today)
 // Returns:
 // - []byte, where each bit represents the availability of the facility corresponding to the hour.
+//
+// Closed hours cannot be told apart from booked hours, as both are unavailable; use QueryCapacity to mark closed hours
+// distinctly.
 func (f *Facility) QueryAvailability(nDays int) []byte {
+	return f.QueryAvailabilityAt(nDays, time.Hour)
+}
+
+// QueryAvailabilityAt searches for availability of the facility for the next number of nDays (including today), in
+// slots of the given resolution (see QueryAvailabilityFrom).
+func (f *Facility) QueryAvailabilityAt(nDays int, resolution time.Duration) []byte {
+	return f.QueryAvailabilityFrom(today(), nDays, resolution)
+}
+
+// QueryAvailabilityFrom searches for availability of the facility for nDays from first, in slots of the given
+// resolution, which must divide a day.
+// Returns:
+// - []byte, where each bit represents the availability of the facility corresponding to the slot; a slot is set if it
+// is fully booked or closed at any point (i.e. partially booked, for an open facility with a capacity of 1).
+//
+// The bitmap has no room to tell closed slots apart from booked slots; QueryCapacityFrom marks them distinctly as
+// ClosedSlot.
+func (f *Facility) QueryAvailabilityFrom(first time.Time, nDays int, resolution time.Duration) []byte {
+	remaining := f.QueryCapacityFrom(first, nDays, resolution)
+	schedule := make([]byte, (len(remaining)+7)/8)
+
+	// Set bits for each fully booked slot in the schedule
+	for slot, r := range remaining {
+		if r == 0 || r == ClosedSlot {
+			byteIndex := slot / 8                // which byte is responsible for this timing
+			bitIndex := 7 - (slot % 8)           // of the 8 bits, which bit is responsible for the slot
+			schedule[byteIndex] |= 1 << bitIndex // set corresponding slot to be 1
+		}
+	}
+
+	return schedule
+}
+
+// QueryCapacity searches for the remaining capacity of the facility for the next number of nDays (including today),
+// in slots of the given resolution (see QueryCapacityFrom).
+func (f *Facility) QueryCapacity(nDays int, resolution time.Duration) []byte {
+	return f.QueryCapacityFrom(today(), nDays, resolution)
+}
+
+// QueryCapacityFrom searches for the remaining capacity of the facility for nDays from the day of first (in the
+// timezone of first), in slots of the given resolution, which must divide a day.
+//
+// Every day has the same number of slots, each covering its time of day on the wall clock, so that slots line up
+// across days when clocks change for daylight saving: on a day of 23 hours, the slots skipped when clocks go forward
+// are ClosedSlot; on a day of 25 hours, the slots repeated when clocks go back also cover the repeated time.
+// Returns:
+// - []byte, where each byte is the smallest number of additional bookings the facility allows at any point in the
+// corresponding slot, capped at 254, or ClosedSlot if the facility is closed at any point in the slot.
+func (f *Facility) QueryCapacityFrom(first time.Time, nDays int, resolution time.Duration) []byte {
 	f.Lock()
 	defer f.Unlock()
 	f.clean()
 
-	schedule := make([]byte, nDays*3)
+	loc := f.Info.location()
+	slotsPerDay := int(24 * time.Hour / resolution)
+	year, month, day := first.Date()
 
-	currentTime := time.Now()
-	firstDate := time.Date(currentTime.Year(), currentTime.Month(), currentTime.Day(), 0, 0, 0, 0, time.Local)
-
-	for _, b := range f.Bookings {
-		if b.End.Before(firstDate) {
-			continue
+	remaining := make([]byte, nDays*slotsPerDay)
+	for d := 0; d < nDays; d++ {
+		// Each slot ends where the next one starts on the wall clock, the last one at the following midnight
+		slotStart := func(slot int) time.Time {
+			offset := time.Duration(slot) * resolution
+			return time.Date(year, month, day+d, 0, 0, int(offset/time.Second), int(offset%time.Second), first.Location())
 		}
+		for slot := 0; slot < slotsPerDay; slot++ {
+			start, end := slotStart(slot), slotStart(slot+1)
+			if !start.Before(end) || !f.Info.isOpen(loc, start, end) {
+				remaining[d*slotsPerDay+slot] = ClosedSlot
+				continue
+			}
+			remaining[d*slotsPerDay+slot] = byte(min(f.Capacity-maxConcurrent(f.Bookings, start, end), ClosedSlot-1))
+		}
+	}
 
-		firstHour := int(b.Start.Sub(firstDate).Hours())
-		lastHour := int(b.End.Sub(firstDate).Hours())
+	return remaining
+}
 
-		if firstHour < 0 {
-			firstHour = 0 // floor
-		}
-		if lastHour >= nDays*24 {
-			lastHour = nDays*24 - 1 // Booking ends after the queried period
-		}
+// today returns midnight at the start of today in the server's timezone.
+func today() time.Time {
+	currentTime := time.Now()
+	return time.Date(currentTime.Year(), currentTime.Month(), currentTime.Day(), 0, 0, 0, 0, time.Local)
+}
 
-		// Set bits for each hour in the schedule
-		for hour := firstHour; hour < lastHour; hour++ {
-			byteIndex := hour / 8                // of the 3 bytes per, which byte is responsible for this timing
-			bitIndex := 7 - (hour % 8)           // of the 8 bits, which bit is responsible for the hour
-			schedule[byteIndex] |= 1 << bitIndex // set corresponding hour to be 1
-		}
+// Update replaces the capacity and information of the facility. Existing bookings are kept even if they fall outside
+// of the new opening hours, but the capacity must not be reduced below the number of bookings at the same time.
+func (f *Facility) Update(capacity int, info FacilityInfo) error {
+	f.Lock()
+	defer f.Unlock()
+	f.clean()
 
+	if capacity < 1 {
+		return errors.New("facility capacity must be at least 1")
+	}
+	if err := info.validate(); err != nil {
+		return err
+	}
+	for _, b := range f.Bookings {
+		if n := maxConcurrent(f.Bookings, b.Start, b.End); n > capacity {
+			return fmt.Errorf("facility has %d bookings at the same time, exceeding a capacity of %d", n, capacity)
+		}
 	}
 
-	return schedule
+	f.Capacity = capacity
+	f.Info = info
+	return nil
 }
 
-func (f *Facility) HasId(id uint16) bool {
+func (f *Facility) HasId(id uint64) bool {
 	f.RLock()
 	defer f.RUnlock()
 
@@ -112,8 +252,16 @@ func (f *Facility) HasId(id uint16) bool {
 func (f *Facility) Book(b Booking) error {
 	f.Lock()
 	defer f.Unlock()
+	return f.book(b)
+}
+
+// book inserts b unless the facility is closed or b clashes. f must be locked.
+func (f *Facility) book(b Booking) error {
 	f.clean()
 
+	if err := f.checkOpen(&b); err != nil {
+		return err
+	}
 	if !f.insertBooking(&b) {
 		slog.Error("Unable to insert booking due to clashes")
 		return errors.New("unable to insert booking due to clashes")
@@ -122,7 +270,34 @@ func (f *Facility) Book(b Booking) error {
 	return nil
 }
 
-func (f *Facility) UpdateBooking(id uint16, deltaHours int) error {
+func (f *Facility) UpdateBooking(id uint64, deltaHours int) error {
+	return f.ShiftBooking(id, time.Duration(deltaHours)*time.Hour)
+}
+
+// ShiftBooking moves the booking with the given id by delta, keeping its duration.
+func (f *Facility) ShiftBooking(id uint64, delta time.Duration) error {
+	return f.rescheduleBooking(id, func(b *Booking) {
+		b.Start = b.Start.Add(delta)
+		b.End = b.End.Add(delta)
+	})
+}
+
+// ResizeBooking changes the start and end of the booking with the given id independently, e.g. to extend or shorten
+// it. A zero start or end keeps the current start or end of the booking.
+func (f *Facility) ResizeBooking(id uint64, start time.Time, end time.Time) error {
+	return f.rescheduleBooking(id, func(b *Booking) {
+		if !start.IsZero() {
+			b.Start = start
+		}
+		if !end.IsZero() {
+			b.End = end
+		}
+	})
+}
+
+// rescheduleBooking applies change to a copy of the booking with the given id, and replaces the booking with the copy
+// if it is valid and does not clash with other bookings. The booking is kept unchanged otherwise.
+func (f *Facility) rescheduleBooking(id uint64, change func(b *Booking)) error {
 	f.Lock()
 	defer f.Unlock()
 	f.clean()
@@ -142,13 +317,18 @@ func (f *Facility) UpdateBooking(id uint16, deltaHours int) error {
 		return errors.New("booking with specified ID not found")
 	}
 
-	// Remove existing booking
-	f.Bookings = append(f.Bookings[:index], f.Bookings[index+1:]...)
-
 	// Updating booking timing
 	newBooking := booking // create a copy of the existing booking
-	newBooking.Start = newBooking.Start.Add(time.Duration(deltaHours) * time.Hour)
-	newBooking.End = newBooking.End.Add(time.Duration(deltaHours) * time.Hour)
+	change(&newBooking)
+	if !newBooking.Start.Before(newBooking.End) {
+		return errors.New("booking must end after it starts")
+	}
+	if err := f.checkOpen(&newBooking); err != nil {
+		return err
+	}
+
+	// Remove existing booking, so that it does not clash with its updated self
+	f.Bookings = append(f.Bookings[:index], f.Bookings[index+1:]...)
 
 	// Attempt to insert the updated booking
 	if ok := f.insertBooking(&newBooking); !ok {
@@ -162,9 +342,14 @@ func (f *Facility) UpdateBooking(id uint16, deltaHours int) error {
 	return nil
 }
 
-func (f *Facility) DeleteBooking(id uint16) bool {
+func (f *Facility) DeleteBooking(id uint64) bool {
 	f.Lock()
 	defer f.Unlock()
+	return f.removeBooking(id)
+}
+
+// removeBooking removes the booking with the given id, returns false if there is none. f must be locked.
+func (f *Facility) removeBooking(id uint64) bool {
 	f.clean()
 
 	deleted := false
@@ -182,6 +367,35 @@ func (f *Facility) DeleteBooking(id uint16) bool {
 	return deleted
 }
 
+// getBooking returns a copy of the booking with the given id.
+func (f *Facility) getBooking(id uint64) (Booking, bool) {
+	f.RLock()
+	defer f.RUnlock()
+
+	b, exists := f.BookingMap[id]
+	if !exists {
+		return Booking{}, false
+	}
+	return *b, true
+}
+
+// restoreBooking puts b into the facility, replacing any booking with the same Id. Unlike Book, b is not checked for
+// clashes, as it is only used to restore bookings that were previously accepted.
+func (f *Facility) restoreBooking(b Booking) {
+	f.Lock()
+	defer f.Unlock()
+
+	f.Bookings = slices.DeleteFunc(f.Bookings, func(existing *Booking) bool {
+		return existing.Id == b.Id
+	})
+
+	index, _ := slices.BinarySearchFunc(f.Bookings, &b, func(a, b *Booking) int {
+		return a.Start.Compare(b.Start)
+	})
+	f.Bookings = slices.Insert(f.Bookings, index, &b)
+	f.BookingMap[b.Id] = &b
+}
+
 // DeepCopy creates a deep copy of a Facility instance.
 func (f *Facility) DeepCopy() *Facility {
 	// Acquire the read lock to safely read from f. (Do this if the Facility may be modified concurrently.)
@@ -191,8 +405,11 @@ func (f *Facility) DeepCopy() *Facility {
 	// Create a new Facility. The embedded RWMutex is not copied; a new zero-value mutex is used.
 	copyFacility := &Facility{
 		Name:       f.Name,
+		Capacity:   f.Capacity,
+		Info:       f.Info,
 		Bookings:   make([]*Booking, len(f.Bookings)),
-		BookingMap: make(map[uint16]*Booking, len(f.BookingMap)),
+		BookingMap: make(map[uint64]*Booking, len(f.BookingMap)),
+		Series:     make(map[uint16]*Series, len(f.Series)),
 	}
 
 	// Deep copy the Bookings slice.
@@ -211,5 +428,171 @@ func (f *Facility) DeepCopy() *Facility {
 		}
 	}
 
+	for id, series := range f.Series {
+		copyFacility.Series[id] = series.DeepCopy()
+	}
+	for _, w := range f.Waitlist {
+		copyFacility.Waitlist = append(copyFacility.Waitlist, w.DeepCopy())
+	}
+	copyFacility.Info.Hours = slices.Clone(f.Info.Hours)
+
 	return copyFacility
 }
+
+func (f *Facility) HasSeries(id uint16) bool {
+	f.RLock()
+	defer f.RUnlock()
+
+	_, exists := f.Series[id]
+	return exists
+}
+
+// BookSeries inserts the occurrences of s, which must already have Ids. Either all occurrences are inserted, or none
+// are if any of them clashes with an existing booking or with each other.
+func (f *Facility) BookSeries(s Series, occurrences []Booking) error {
+	f.Lock()
+	defer f.Unlock()
+	f.clean()
+
+	if _, exists := f.Series[s.Id]; exists {
+		return errors.New("series already exists")
+	}
+	for i := range occurrences {
+		if err := f.checkOpen(&occurrences[i]); err != nil {
+			return err
+		}
+	}
+
+	original := slices.Clone(f.Bookings)
+	for i := range occurrences {
+		if !f.insertBooking(&occurrences[i]) {
+			f.Bookings = original
+			for _, b := range occurrences[:i] {
+				delete(f.BookingMap, b.Id)
+			}
+			slog.Error("Unable to insert series due to clashes", "Occurrence", occurrences[i])
+			return fmt.Errorf("unable to insert series due to clashes with occurrence at %v", occurrences[i].Start)
+		}
+	}
+
+	f.Series[s.Id] = &s
+	return nil
+}
+
+// ShiftSeries moves every remaining occurrence of the series with the given id by delta. Either all occurrences are
+// moved, or none are if any of them would clash with another booking.
+func (f *Facility) ShiftSeries(id uint16, delta time.Duration) error {
+	f.Lock()
+	defer f.Unlock()
+	f.clean()
+
+	s, exists := f.Series[id]
+	if !exists {
+		return errors.New("series with specified ID not found")
+	}
+	for _, b := range f.Bookings {
+		if b.SeriesId == id {
+			shifted := Booking{Start: b.Start.Add(delta), End: b.End.Add(delta)}
+			if err := f.checkOpen(&shifted); err != nil {
+				return err
+			}
+		}
+	}
+
+	original := slices.Clone(f.Bookings)
+	var occurrences []Booking
+	f.Bookings = slices.DeleteFunc(f.Bookings, func(b *Booking) bool {
+		if b.SeriesId == id {
+			occurrences = append(occurrences, *b)
+			return true
+		}
+		return false
+	})
+
+	for i := range occurrences {
+		shifted := occurrences[i]
+		shifted.Start = shifted.Start.Add(delta)
+		shifted.End = shifted.End.Add(delta)
+
+		if !f.insertBooking(&shifted) {
+			// Fall back to original occurrences
+			f.Bookings = original
+			for _, b := range original {
+				f.BookingMap[b.Id] = b
+			}
+			slog.Error("Unable to shift series due to clashes", "Occurrence", shifted)
+			return fmt.Errorf("unable to shift series due to clashes with occurrence at %v", shifted.Start)
+		}
+	}
+
+	// The series describes the occurrences that have not been cancelled individually
+	shifted := s.DeepCopy()
+	shifted.Start, shifted.End = s.Start.Add(delta), s.End.Add(delta)
+	if !s.Until.IsZero() {
+		shifted.Until = s.Until.Add(delta)
+	}
+	for i := range shifted.Exceptions {
+		shifted.Exceptions[i] = shifted.Exceptions[i].Add(delta)
+	}
+	f.Series[id] = shifted
+
+	return nil
+}
+
+// DeleteSeries removes the series with the given id and all of its occurrences.
+func (f *Facility) DeleteSeries(id uint16) bool {
+	f.Lock()
+	defer f.Unlock()
+	f.clean()
+
+	if _, exists := f.Series[id]; !exists {
+		return false
+	}
+
+	f.removeSeries(id)
+	return true
+}
+
+// removeSeries removes the series with the given id and all of its occurrences. f must be locked.
+func (f *Facility) removeSeries(id uint16) {
+	f.Bookings = slices.DeleteFunc(f.Bookings, func(b *Booking) bool {
+		if b.SeriesId == id {
+			delete(f.BookingMap, b.Id)
+			return true
+		}
+		return false
+	})
+	delete(f.Series, id)
+}
+
+// getSeries returns a copy of the series with the given id, and copies of its occurrences.
+func (f *Facility) getSeries(id uint16) (Series, []Booking, bool) {
+	f.RLock()
+	defer f.RUnlock()
+
+	s, exists := f.Series[id]
+	if !exists {
+		return Series{}, nil, false
+	}
+
+	var occurrences []Booking
+	for _, b := range f.Bookings {
+		if b.SeriesId == id {
+			occurrences = append(occurrences, *b)
+		}
+	}
+	return *s.DeepCopy(), occurrences, true
+}
+
+// restoreSeries puts s and its occurrences into the facility, replacing the series with the same Id and its
+// occurrences. Like restoreBooking, the occurrences are not checked for clashes.
+func (f *Facility) restoreSeries(s Series, occurrences []Booking) {
+	f.Lock()
+	f.removeSeries(s.Id)
+	f.Series[s.Id] = &s
+	f.Unlock()
+
+	for _, b := range occurrences {
+		f.restoreBooking(b)
+	}
+}
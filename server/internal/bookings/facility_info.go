@@ -0,0 +1,126 @@
+package bookings
+
+import (
+	"errors"
+	"fmt"
+	"log/slog"
+	"regexp"
+	"strconv"
+	"time"
+	_ "time/tzdata" // Timezones must be available regardless of the host
+)
+
+// OpeningHours is the period a facility is open on a weekday, from Open to Close after midnight in the facility's
+// timezone. A facility that is open from 0 to 24h is open all day, one with Open equal to Close is closed all day.
+type OpeningHours struct {
+	Open  time.Duration `json:"open"`
+	Close time.Duration `json:"close"`
+}
+
+// AllDay is open all day.
+var AllDay = OpeningHours{Open: 0, Close: 24 * time.Hour}
+
+// FacilityInfo describes a facility to its users. Bookings of a facility must fall within its opening hours.
+type FacilityInfo struct {
+	Description string `json:"description,omitempty"`
+	Location    string `json:"location,omitempty"`
+	Timezone    string `json:"timezone,omitempty"` // IANA timezone, e.g. "Asia/Singapore"; empty for the server's timezone
+
+	// Hours are indexed by time.Weekday; a facility without Hours is open all day, every day.
+	Hours []OpeningHours `json:"hours,omitempty"`
+}
+
+func FacilityWithInfo(info FacilityInfo) FacilityOption {
+	return func(f *Facility) {
+		f.Info = info
+	}
+}
+
+func (i *FacilityInfo) validate() error {
+	if _, err := time.LoadLocation(i.Timezone); err != nil {
+		return fmt.Errorf("unknown timezone %q", i.Timezone)
+	}
+	if len(i.Hours) != 0 && len(i.Hours) != 7 {
+		return errors.New("opening hours must be given for all 7 weekdays")
+	}
+	for day, h := range i.Hours {
+		if h.Open < 0 || h.Close > 24*time.Hour || h.Close < h.Open {
+			return fmt.Errorf("invalid opening hours on %v: %v to %v", time.Weekday(day), h.Open, h.Close)
+		}
+	}
+	return nil
+}
+
+// location returns the timezone of the facility, falling back to the server's timezone.
+func (i *FacilityInfo) location() *time.Location {
+	// time.LoadLocation returns UTC rather than the server's timezone for an empty name
+	if i.Timezone == "" {
+		return time.Local
+	}
+	if loc, err := time.LoadLocation(i.Timezone); err == nil {
+		return loc
+	}
+	return time.Local
+}
+
+var utcOffsetPattern = regexp.MustCompile(`^(?:UTC|GMT)?([+-])(\d{1,2})(?::?(\d{2}))?$`)
+
+// ParseTimezone returns the timezone named by tz, which is either an IANA timezone (e.g. "Asia/Singapore") or a UTC
+// offset (e.g. "UTC+8", "+08:00" or "-0530"). An empty tz is the server's timezone.
+func ParseTimezone(tz string) (*time.Location, error) {
+	if tz == "" {
+		return time.Local, nil
+	}
+
+	if m := utcOffsetPattern.FindStringSubmatch(tz); m != nil {
+		hours, _ := strconv.Atoi(m[2])
+		minutes := 0
+		if m[3] != "" {
+			minutes, _ = strconv.Atoi(m[3])
+		}
+		if hours > 14 || minutes > 59 {
+			return nil, fmt.Errorf("invalid UTC offset %q", tz)
+		}
+		offset := hours*60*60 + minutes*60
+		if m[1] == "-" {
+			offset = -offset
+		}
+		return time.FixedZone(tz, offset), nil
+	}
+
+	loc, err := time.LoadLocation(tz)
+	if err != nil {
+		return nil, fmt.Errorf("unknown timezone %q", tz)
+	}
+	return loc, nil
+}
+
+// isOpen reports whether the facility is open for the whole period from start to end, given the facility's
+// timezone loc.
+func (i *FacilityInfo) isOpen(loc *time.Location, start time.Time, end time.Time) bool {
+	if len(i.Hours) == 0 {
+		return true
+	}
+
+	// Walk through each day the period spans, which must be open from where the previous day closed
+	at := start.In(loc)
+	for at.Before(end) {
+		h := i.Hours[at.Weekday()]
+		open := time.Date(at.Year(), at.Month(), at.Day(), 0, int(h.Open/time.Minute), 0, 0, loc)
+		closing := time.Date(at.Year(), at.Month(), at.Day(), 0, int(h.Close/time.Minute), 0, 0, loc)
+		if at.Before(open) || !at.Before(closing) {
+			return false
+		}
+		at = closing
+	}
+	return true
+}
+
+// checkOpen returns an error if the facility is closed at any point of b. f must be locked.
+func (f *Facility) checkOpen(b *Booking) error {
+	if !f.Info.isOpen(f.Info.location(), b.Start, b.End) {
+		slog.Error("Booking is outside of opening hours", "Facility", f.Name, "Booking", *b)
+		return fmt.Errorf("facility is closed at some point from %v to %v", b.Start, b.End)
+	}
+	return nil
+}
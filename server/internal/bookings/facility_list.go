@@ -0,0 +1,100 @@
+package bookings
+
+import (
+	"cmp"
+	"slices"
+	"strings"
+)
+
+// FacilitySort is the order in which Manager.ListFacilities lists facilities.
+type FacilitySort uint8
+
+const (
+	FacilitySortName     FacilitySort = 0x00 // By name, alphabetically
+	FacilitySortBookings FacilitySort = 0x01 // By number of bookings, most first, then by name
+)
+
+// FacilitySummary describes a facility listed by Manager.ListFacilities.
+type FacilitySummary struct {
+	Name       FacilityName
+	Capacity   int
+	Bookings   int // Bookings that have not ended yet
+	Waitlisted int
+	Info       FacilityInfo
+}
+
+// FacilityCursor is the position of a facility in a listing, so that the following facilities can be listed next.
+// The zero FacilityCursor is before the first facility.
+type FacilityCursor struct {
+	Name     FacilityName
+	Bookings int // Only used with FacilitySortBookings
+}
+
+// Cursor returns the position of s in a listing, to list the facilities following s.
+func (s FacilitySummary) Cursor() FacilityCursor {
+	return FacilityCursor{Name: s.Name, Bookings: s.Bookings}
+}
+
+// FacilityListFilter selects facilities listed by Manager.ListFacilities, zero fields match every facility.
+type FacilityListFilter struct {
+	Prefix   string // Names starting with Prefix, ignoring case
+	Contains string // Names containing Contains, ignoring case
+	Sort     FacilitySort
+	After    FacilityCursor // Facilities following After in the order of Sort
+}
+
+func (l FacilityListFilter) matches(s *FacilitySummary) bool {
+	name := strings.ToLower(string(s.Name))
+	return strings.HasPrefix(name, strings.ToLower(l.Prefix)) && strings.Contains(name, strings.ToLower(l.Contains))
+}
+
+// compare orders a and b by the sort of the filter.
+func (l FacilityListFilter) compare(a FacilityCursor, b FacilityCursor) int {
+	if l.Sort == FacilitySortBookings && a.Bookings != b.Bookings {
+		return cmp.Compare(b.Bookings, a.Bookings)
+	}
+	return cmp.Compare(a.Name, b.Name)
+}
+
+// summary returns the summary of f.
+func (f *Facility) summary() FacilitySummary {
+	f.Lock()
+	defer f.Unlock()
+	f.clean()
+
+	return FacilitySummary{
+		Name:       f.Name,
+		Capacity:   f.Capacity,
+		Bookings:   len(f.Bookings),
+		Waitlisted: len(f.Waitlist),
+		Info:       f.Info,
+	}
+}
+
+// ListFacilities returns up to limit facilities selected by filter, in the order of filter.Sort. A limit of 0 returns
+// every selected facility. The facilities following the last one returned are listed by setting filter.After to its
+// Cursor.
+func (m *Manager) ListFacilities(filter FacilityListFilter, limit int) []FacilitySummary {
+	m.RLock()
+	defer m.RUnlock()
+
+	var res []FacilitySummary
+	for _, f := range m.Facilities {
+		s := f.summary()
+		if !filter.matches(&s) {
+			continue
+		}
+		if filter.After != (FacilityCursor{}) && filter.compare(s.Cursor(), filter.After) <= 0 {
+			continue
+		}
+		res = append(res, s)
+	}
+
+	slices.SortFunc(res, func(a, b FacilitySummary) int {
+		return filter.compare(a.Cursor(), b.Cursor())
+	})
+	if limit > 0 && len(res) > limit {
+		res = res[:limit]
+	}
+	return res
+}
@@ -0,0 +1,59 @@
+package bookings
+
+import (
+	"slices"
+	"testing"
+	"time"
+)
+
+func TestManager_ListFacilities(t *testing.T) {
+	manager := NewManager()
+	for _, n := range []FacilityName{"Hall B", "Hall A", "Lab", "Meeting Hall"} {
+		if err := manager.NewFacility(n, FacilityWithCapacity(2), FacilityWithInfo(FacilityInfo{Location: "North"})); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	start := time.Now().Truncate(time.Hour).Add(time.Duration(24) * time.Hour)
+	for i, n := range []FacilityName{"Lab", "Lab", "Hall B"} {
+		if _, err := manager.NewBookingWithUnusedId(n, Booking{Start: start.Add(time.Duration(i) * time.Hour), End: start.Add(time.Duration(i+1) * time.Hour)}, MaxLegacyBookingId); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	names := func(summaries []FacilitySummary) []FacilityName {
+		var res []FacilityName
+		for _, s := range summaries {
+			res = append(res, s.Name)
+		}
+		return res
+	}
+
+	tests := []struct {
+		name     string
+		filter   FacilityListFilter
+		limit    int
+		expected []FacilityName
+	}{
+		{"all by name", FacilityListFilter{}, 0, []FacilityName{"Hall A", "Hall B", "Lab", "Meeting Hall"}},
+		{"prefix ignores case", FacilityListFilter{Prefix: "hall"}, 0, []FacilityName{"Hall A", "Hall B"}},
+		{"contains", FacilityListFilter{Contains: "HALL"}, 0, []FacilityName{"Hall A", "Hall B", "Meeting Hall"}},
+		{"limited", FacilityListFilter{}, 2, []FacilityName{"Hall A", "Hall B"}},
+		{"after cursor", FacilityListFilter{After: FacilityCursor{Name: "Hall B"}}, 0, []FacilityName{"Lab", "Meeting Hall"}},
+		{"by bookings", FacilityListFilter{Sort: FacilitySortBookings}, 0, []FacilityName{"Lab", "Hall B", "Hall A", "Meeting Hall"}},
+		{"by bookings after cursor", FacilityListFilter{Sort: FacilitySortBookings, After: FacilityCursor{Name: "Hall B", Bookings: 1}}, 0, []FacilityName{"Hall A", "Meeting Hall"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if res := names(manager.ListFacilities(tt.filter, tt.limit)); !slices.Equal(res, tt.expected) {
+				t.Errorf("E: %v, R: %v", tt.expected, res)
+			}
+		})
+	}
+
+	// Summaries hold the number of bookings and the information of each facility
+	lab := manager.ListFacilities(FacilityListFilter{Prefix: "Lab"}, 0)[0]
+	if lab.Bookings != 2 || lab.Capacity != 2 || lab.Info.Location != "North" {
+		t.Errorf("Unexpected summary %+v", lab)
+	}
+}
@@ -2,6 +2,8 @@ package bookings
 
 import (
 	"bytes"
+	"os"
+	"os/exec"
 	"testing"
 	"time"
 )
@@ -151,6 +153,60 @@ func TestFacility_UpdateBooking(t *testing.T) {
 
 }
 
+func TestFacility_ResizeBooking(t *testing.T) {
+
+	currentTime := time.Now()
+
+	b1 := &Booking{
+		Id:    1,
+		Start: currentTime.Add(time.Duration(1) * time.Hour),
+		End:   currentTime.Add(time.Duration(3) * time.Hour),
+	}
+	b2 := &Booking{
+		Id:    2,
+		Start: currentTime.Add(time.Duration(4) * time.Hour),
+		End:   currentTime.Add(time.Duration(5) * time.Hour),
+	}
+
+	f := NewFacility(FacilityName("Testing"))
+
+	_ = f.Book(*b1)
+	_ = f.Book(*b2)
+
+	// Extending the end keeps the start, and the booking does not clash with itself
+	if err := f.ResizeBooking(b1.Id, time.Time{}, b1.End.Add(time.Hour)); err != nil {
+		t.Fatal(err)
+	}
+	if resized, _ := f.getBooking(b1.Id); !resized.Start.Equal(b1.Start) || !resized.End.Equal(b1.End.Add(time.Hour)) {
+		t.Errorf("Booking was not extended properly: %v", resized)
+	}
+
+	// Starting earlier and ending earlier at once
+	if err := f.ResizeBooking(b1.Id, b1.Start.Add(-time.Duration(30)*time.Minute), b1.End.Add(-time.Hour)); err != nil {
+		t.Fatal(err)
+	}
+	if resized, _ := f.getBooking(b1.Id); !resized.Start.Equal(b1.Start.Add(-time.Duration(30)*time.Minute)) || !resized.End.Equal(b1.End.Add(-time.Hour)) {
+		t.Errorf("Booking was not resized properly: %v", resized)
+	}
+
+	// Error cases keep the booking unchanged
+	for name, end := range map[string]time.Time{
+		"clash":          b2.Start.Add(time.Minute),
+		"before start":   b1.Start.Add(-time.Hour),
+		"equal to start": b1.Start.Add(-time.Duration(30) * time.Minute),
+	} {
+		if err := f.ResizeBooking(b1.Id, time.Time{}, end); err == nil {
+			t.Errorf("%s: Expected an error to be raised", name)
+		}
+	}
+	if err := f.ResizeBooking(3, time.Time{}, b2.End); err == nil {
+		t.Error("Expected an error to be raised for a non-existent booking")
+	}
+	if resized, _ := f.getBooking(b1.Id); !resized.End.Equal(b1.End.Add(-time.Hour)) || len(f.Bookings) != 2 {
+		t.Errorf("Booking was changed by failed resizes: %v", resized)
+	}
+}
+
 func TestFacility_DeleteBooking(t *testing.T) {
 
 	currentTime := time.Now()
@@ -182,3 +238,258 @@ func TestFacility_DeleteBooking(t *testing.T) {
 	}
 
 }
+
+func TestFacility_QueryAvailabilityAt(t *testing.T) {
+	currentTime := time.Now()
+	tmr := time.Date(currentTime.Year(), currentTime.Month(), currentTime.Day()+1, 0, 0, 0, 0, time.Local)
+
+	b1 := &Booking{
+		Id:    1,
+		Start: tmr.Add(time.Duration(30) * time.Minute),
+		End:   tmr.Add(time.Duration(100) * time.Minute),
+	}
+
+	f := NewFacility(FacilityName("Testing"))
+
+	_ = f.Book(*b1)
+
+	// 30 minute slots, the partially booked slot from 90 to 120 minutes is set
+	expectedByteArr := make([]byte, 12)
+	expectedByteArr[6] = 0x70
+
+	if !bytes.Equal(f.QueryAvailabilityAt(2, time.Duration(30)*time.Minute), expectedByteArr) {
+		t.Logf("E: % X", expectedByteArr)
+		t.Logf("R: % X", f.QueryAvailabilityAt(2, time.Duration(30)*time.Minute))
+		t.Error("Availability does not match expected")
+	}
+
+}
+
+func TestFacility_Book_capacity(t *testing.T) {
+	currentTime := time.Now()
+	tmr := time.Date(currentTime.Year(), currentTime.Month(), currentTime.Day()+1, 0, 0, 0, 0, time.Local)
+
+	f := NewFacility(FacilityName("Testing"), FacilityWithCapacity(2))
+
+	// Two bookings overlapping from 1 to 2 fill the facility
+	if err := f.Book(Booking{Id: 1, Start: tmr, End: tmr.Add(time.Duration(2) * time.Hour)}); err != nil {
+		t.Fatal(err)
+	}
+	if err := f.Book(Booking{Id: 2, Start: tmr.Add(time.Hour), End: tmr.Add(time.Duration(3) * time.Hour)}); err != nil {
+		t.Fatal(err)
+	}
+	if err := f.Book(Booking{Id: 3, Start: tmr.Add(time.Duration(90) * time.Minute), End: tmr.Add(time.Duration(4) * time.Hour)}); err == nil {
+		t.Error("Expected booking exceeding capacity to be rejected")
+	}
+
+	// Each overlaps a single existing booking
+	if err := f.Book(Booking{Id: 4, Start: tmr.Add(time.Duration(30) * time.Minute), End: tmr.Add(time.Hour)}); err != nil {
+		t.Errorf("Expected booking within capacity to succeed: %v", err)
+	}
+	if err := f.Book(Booking{Id: 5, Start: tmr.Add(time.Duration(150) * time.Minute), End: tmr.Add(time.Duration(4) * time.Hour)}); err != nil {
+		t.Errorf("Expected booking within capacity to succeed: %v", err)
+	}
+
+	expected := bytes.Repeat([]byte{2}, 48)
+	copy(expected[24:], []byte{0, 0, 0, 1})
+	if r := f.QueryCapacity(2, time.Hour); !bytes.Equal(r, expected) {
+		t.Errorf("E: % X, R: % X", expected, r)
+	}
+
+	// Only the fully booked slots are set
+	if r := f.QueryAvailability(2); !bytes.Equal(r, []byte{0x00, 0x00, 0x00, 0xE0, 0x00, 0x00}) {
+		t.Errorf("Availability does not match expected, R: % X", r)
+	}
+}
+
+func TestFacility_Book_openingHours(t *testing.T) {
+	singapore, _ := time.LoadLocation("Asia/Singapore")
+	now := time.Now().In(singapore)
+	tmr := time.Date(now.Year(), now.Month(), now.Day()+1, 0, 0, 0, 0, singapore)
+
+	// Open from 9 to 17 tomorrow, all day on the day after, closed otherwise; capacity is ample so that only opening
+	// hours are checked
+	hours := make([]OpeningHours, 7)
+	hours[tmr.Weekday()] = OpeningHours{Open: time.Duration(9) * time.Hour, Close: time.Duration(17) * time.Hour}
+	hours[tmr.AddDate(0, 0, 1).Weekday()] = AllDay
+	f := NewFacility(
+		FacilityName("Testing"),
+		FacilityWithCapacity(10),
+		FacilityWithInfo(FacilityInfo{Timezone: "Asia/Singapore", Hours: hours}),
+	)
+
+	cases := []struct {
+		name   string
+		start  time.Duration
+		end    time.Duration
+		isOpen bool
+	}{
+		{name: "within", start: time.Duration(9) * time.Hour, end: time.Duration(17) * time.Hour, isOpen: true},
+		{name: "before opening", start: time.Duration(8) * time.Hour, end: time.Duration(10) * time.Hour},
+		{name: "after closing", start: time.Duration(16) * time.Hour, end: time.Duration(18) * time.Hour},
+		{name: "all day", start: time.Duration(24) * time.Hour, end: time.Duration(48) * time.Hour, isOpen: true},
+		{name: "across closed days", start: time.Duration(30) * time.Hour, end: time.Duration(50) * time.Hour},
+	}
+
+	for i, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			err := f.Book(Booking{Id: uint64(i + 1), Start: tmr.Add(c.start), End: tmr.Add(c.end)})
+			if c.isOpen && err != nil {
+				t.Errorf("Expected booking to succeed: %v", err)
+			}
+			if !c.isOpen && err == nil {
+				t.Error("Expected booking outside of opening hours to be rejected")
+			}
+		})
+	}
+}
+
+func TestFacility_Book_serverTimezone(t *testing.T) {
+	// time.Local cannot be changed safely while other tests run, so the test runs in a copy of the test binary instead
+	if os.Getenv("TZ") != "Asia/Singapore" {
+		cmd := exec.Command(os.Args[0], "-test.run=^TestFacility_Book_serverTimezone$")
+		cmd.Env = append(os.Environ(), "TZ=Asia/Singapore")
+		if out, err := cmd.CombinedOutput(); err != nil {
+			t.Fatalf("%v\n%s", err, out)
+		}
+		return
+	}
+	if _, offset := time.Now().Zone(); offset != 8*60*60 {
+		t.Fatalf("Expected server timezone to be UTC+8, got offset %d", offset)
+	}
+
+	// Open from 9 to 17 every day in the server's timezone, as the facility has none of its own
+	hours := make([]OpeningHours, 7)
+	for day := range hours {
+		hours[day] = OpeningHours{Open: time.Duration(9) * time.Hour, Close: time.Duration(17) * time.Hour}
+	}
+	f := NewFacility(FacilityName("Testing"), FacilityWithCapacity(10), FacilityWithInfo(FacilityInfo{Hours: hours}))
+
+	now := time.Now()
+	tmr := time.Date(now.Year(), now.Month(), now.Day()+1, 0, 0, 0, 0, time.Local)
+
+	// 9 to 10 in the server's timezone is 1 to 2 in UTC, when the facility would be closed
+	if err := f.Book(Booking{Id: 1, Start: tmr.Add(time.Duration(9) * time.Hour), End: tmr.Add(time.Duration(10) * time.Hour)}); err != nil {
+		t.Errorf("Expected booking within opening hours of the server's timezone to succeed: %v", err)
+	}
+	// 17 to 18 in the server's timezone is 9 to 10 in UTC, when the facility would be open
+	if err := f.Book(Booking{Id: 2, Start: tmr.Add(time.Duration(17) * time.Hour), End: tmr.Add(time.Duration(18) * time.Hour)}); err == nil {
+		t.Error("Expected booking after closing in the server's timezone to be rejected")
+	}
+}
+
+func TestFacility_QueryCapacity_closed(t *testing.T) {
+	now := time.Now()
+	tmr := time.Date(now.Year(), now.Month(), now.Day()+1, 0, 0, 0, 0, time.Local)
+
+	hours := make([]OpeningHours, 7)
+	for i := range hours {
+		hours[i] = OpeningHours{Open: time.Duration(9) * time.Hour, Close: time.Duration(17) * time.Hour}
+	}
+	f := NewFacility(FacilityName("Testing"), FacilityWithInfo(FacilityInfo{Hours: hours}))
+	if err := f.Book(Booking{Id: 1, Start: tmr.Add(time.Duration(9) * time.Hour), End: tmr.Add(time.Duration(10) * time.Hour)}); err != nil {
+		t.Fatal(err)
+	}
+
+	r := f.QueryCapacity(2, time.Hour)
+	for slot, remaining := range r {
+		hour := slot % 24
+		switch {
+		case slot == 24+9:
+			if remaining != 0 {
+				t.Errorf("Expected booked slot %d to have no capacity, got %d", slot, remaining)
+			}
+		case hour < 9 || hour >= 17:
+			if remaining != ClosedSlot {
+				t.Errorf("Expected slot %d to be closed, got %d", slot, remaining)
+			}
+		case remaining != 1:
+			t.Errorf("Expected open slot %d to have a capacity of 1, got %d", slot, remaining)
+		}
+	}
+}
+
+func TestFacility_QueryCapacityFrom_otherTimezone(t *testing.T) {
+	loc, err := ParseTimezone("UTC+5:30")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// A booking from 10 to 11 on the day after next in the client's timezone
+	now := time.Now().In(loc)
+	first := time.Date(now.Year(), now.Month(), now.Day()+2, 0, 0, 0, 0, loc)
+	f := NewFacility(FacilityName("Testing"))
+	if err := f.Book(Booking{Id: 1, Start: first.Add(time.Duration(10) * time.Hour), End: first.Add(time.Duration(11) * time.Hour)}); err != nil {
+		t.Fatal(err)
+	}
+
+	expected := bytes.Repeat([]byte{1}, 24)
+	expected[10] = 0
+	if r := f.QueryCapacityFrom(first, 1, time.Hour); !bytes.Equal(r, expected) {
+		t.Errorf("E: % X, R: % X", expected, r)
+	}
+}
+
+func TestFacility_QueryCapacityFrom_daylightSaving(t *testing.T) {
+	berlin, err := time.LoadLocation("Europe/Berlin")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Clocks go forward from 2:00 to 3:00, so the day has 23 hours and the slot from 2:00 does not exist
+	spring := time.Date(2027, time.March, 28, 0, 0, 0, 0, berlin)
+	f := NewFacility(FacilityName("Testing"))
+	if err := f.Book(Booking{Id: 1, Start: time.Date(2027, time.March, 28, 3, 0, 0, 0, berlin), End: time.Date(2027, time.March, 28, 4, 0, 0, 0, berlin)}); err != nil {
+		t.Fatal(err)
+	}
+	expected := bytes.Repeat([]byte{1}, 24)
+	expected[2] = ClosedSlot
+	expected[3] = 0
+	if r := f.QueryCapacityFrom(spring, 1, time.Hour); !bytes.Equal(r, expected) {
+		t.Errorf("E: % X, R: % X", expected, r)
+	}
+
+	// Clocks go back from 3:00 to 2:00, so the day has 25 hours and the slot from 2:00 covers both hours from 2:00
+	autumn := time.Date(2027, time.October, 31, 0, 0, 0, 0, berlin)
+	f = NewFacility(FacilityName("Testing"))
+	if err := f.Book(Booking{Id: 1, Start: autumn.Add(time.Duration(3) * time.Hour), End: autumn.Add(time.Duration(4) * time.Hour)}); err != nil {
+		t.Fatal(err)
+	}
+	if err := f.Book(Booking{Id: 2, Start: time.Date(2027, time.October, 31, 22, 0, 0, 0, berlin), End: time.Date(2027, time.October, 31, 23, 0, 0, 0, berlin)}); err != nil {
+		t.Fatal(err)
+	}
+	expected = bytes.Repeat([]byte{1}, 48)
+	expected[2] = 0
+	expected[22] = 0
+	if r := f.QueryCapacityFrom(autumn, 2, time.Hour); !bytes.Equal(r, expected) {
+		t.Errorf("E: % X, R: % X", expected, r)
+	}
+}
+
+func TestParseTimezone(t *testing.T) {
+	for tz, offset := range map[string]int{
+		"UTC+8":  8 * 60 * 60,
+		"+08:00": 8 * 60 * 60,
+		"GMT-5":  -5 * 60 * 60,
+		"-0530":  -(5*60 + 30) * 60,
+		"UTC":    0,
+	} {
+		loc, err := ParseTimezone(tz)
+		if err != nil {
+			t.Errorf("%s: %v", tz, err)
+			continue
+		}
+		if _, r := time.Date(2025, 1, 1, 0, 0, 0, 0, loc).Zone(); r != offset {
+			t.Errorf("%s: E: %d, R: %d", tz, offset, r)
+		}
+	}
+
+	if loc, err := ParseTimezone(""); err != nil || loc != time.Local {
+		t.Errorf("Expected empty timezone to be the server's timezone, got %v (%v)", loc, err)
+	}
+	for _, tz := range []string{"Nowhere/Special", "UTC+15", "+08:75"} {
+		if _, err := ParseTimezone(tz); err == nil {
+			t.Errorf("Expected %q to be rejected", tz)
+		}
+	}
+}
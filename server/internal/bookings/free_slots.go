@@ -0,0 +1,109 @@
+package bookings
+
+import (
+	"errors"
+	"fmt"
+	"log/slog"
+	"slices"
+	"time"
+)
+
+// Interval is the period of time from Start to End.
+type Interval struct {
+	Start time.Time
+	End   time.Time
+}
+
+// hourChanges returns the times the facility opens or closes from start to end, given the facility's timezone loc.
+func (i *FacilityInfo) hourChanges(loc *time.Location, start time.Time, end time.Time) []time.Time {
+	if len(i.Hours) == 0 {
+		return nil
+	}
+
+	var res []time.Time
+	at := start.In(loc)
+	for day := time.Date(at.Year(), at.Month(), at.Day(), 0, 0, 0, 0, loc); day.Before(end); day = day.AddDate(0, 0, 1) {
+		h := i.Hours[day.Weekday()]
+		for _, change := range []time.Duration{h.Open, h.Close} {
+			if t := time.Date(day.Year(), day.Month(), day.Day(), 0, int(change/time.Minute), 0, 0, loc); t.After(start) && t.Before(end) {
+				res = append(res, t)
+			}
+		}
+	}
+	return res
+}
+
+// FindFreeSlots returns up to count periods of the given duration from start to end during which the facility can be
+// booked, earliest first and not overlapping each other. Periods start on a multiple of step since the Unix epoch.
+// Periods outside the opening hours of the facility are skipped if withinHours is set.
+func (f *Facility) FindFreeSlots(start time.Time, end time.Time, duration time.Duration, step time.Duration, count int, withinHours bool) []Interval {
+	f.Lock()
+	defer f.Unlock()
+	f.clean()
+
+	// The earliest free period starts at the start of the search, or when a booking ends or the opening hours change
+	candidates := []time.Time{start}
+	for _, b := range f.Bookings {
+		if b.End.After(start) && b.End.Before(end) {
+			candidates = append(candidates, b.End)
+		}
+	}
+	loc := f.Info.location()
+	if withinHours {
+		candidates = append(candidates, f.Info.hourChanges(loc, start, end)...)
+	}
+	for i, c := range candidates {
+		if offset := time.Duration(c.UnixNano()) % step; offset != 0 {
+			candidates[i] = c.Add(step - offset)
+		}
+	}
+	slices.SortFunc(candidates, time.Time.Compare)
+	candidates = slices.CompactFunc(candidates, time.Time.Equal)
+
+	var res []Interval
+	for i := 0; i < len(candidates) && len(res) < count; i++ {
+		slot := Interval{Start: candidates[i], End: candidates[i].Add(duration)}
+		if slot.End.After(end) {
+			break
+		}
+		if res != nil && slot.Start.Before(res[len(res)-1].End) {
+			continue
+		}
+		if withinHours && !f.Info.isOpen(loc, slot.Start, slot.End) {
+			continue
+		}
+		if maxConcurrent(f.Bookings, slot.Start, slot.End) >= f.Capacity {
+			continue
+		}
+
+		// The next free period may start right after this one
+		res = append(res, slot)
+		if j, found := slices.BinarySearchFunc(candidates, slot.End, time.Time.Compare); !found {
+			candidates = slices.Insert(candidates, j, slot.End)
+		}
+	}
+	return res
+}
+
+// FindFreeSlots searches for free periods of a facility (see Facility.FindFreeSlots).
+func (m *Manager) FindFreeSlots(n FacilityName, start time.Time, end time.Time, duration time.Duration, step time.Duration, count int, withinHours bool) ([]Interval, error) {
+	m.RLock()
+	defer m.RUnlock()
+
+	switch {
+	case !start.Before(end):
+		return nil, errors.New("search must end after it starts")
+	case duration <= 0 || step <= 0:
+		return nil, errors.New("duration and step must be positive")
+	case count < 1:
+		return nil, errors.New("at least one free period must be searched for")
+	}
+
+	f, exists := m.Facilities[n]
+	if !exists {
+		slog.Error("Facility does not exist!", "FacilityName", n)
+		return nil, errors.New("facility does not exist")
+	}
+	m.monitor.Update(n, fmt.Sprintf("Executing search for %d free periods of %v on %s", count, duration, n))
+	return f.FindFreeSlots(start, end, duration, step, count, withinHours), nil
+}
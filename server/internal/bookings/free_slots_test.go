@@ -0,0 +1,102 @@
+package bookings
+
+import (
+	"slices"
+	"testing"
+	"time"
+)
+
+func equalIntervals(a, b []Interval) bool {
+	return slices.EqualFunc(a, b, func(x, y Interval) bool {
+		return x.Start.Equal(y.Start) && x.End.Equal(y.End)
+	})
+}
+
+func TestFacility_FindFreeSlots(t *testing.T) {
+	now := time.Now()
+	tmr := time.Date(now.Year(), now.Month(), now.Day()+1, 0, 0, 0, 0, time.Local)
+
+	f := NewFacility(FacilityName("Testing"))
+	for _, b := range []Booking{
+		{Id: 1, Start: tmr.Add(time.Duration(10) * time.Hour), End: tmr.Add(time.Duration(11) * time.Hour)},
+		{Id: 2, Start: tmr.Add(time.Duration(690) * time.Minute), End: tmr.Add(time.Duration(12) * time.Hour)},
+	} {
+		if err := f.Book(b); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	// Free periods start once a booking ends, or right after the previous free period
+	r := f.FindFreeSlots(tmr.Add(time.Duration(10)*time.Hour), tmr.Add(time.Duration(14)*time.Hour), time.Hour, time.Duration(30)*time.Minute, 3, false)
+	e := []Interval{
+		{Start: tmr.Add(time.Duration(12) * time.Hour), End: tmr.Add(time.Duration(13) * time.Hour)},
+		{Start: tmr.Add(time.Duration(13) * time.Hour), End: tmr.Add(time.Duration(14) * time.Hour)},
+	}
+	if !equalIntervals(r, e) {
+		t.Errorf("E: %v, R: %v", e, r)
+	}
+
+	// No more than count free periods are returned
+	if r := f.FindFreeSlots(tmr.Add(time.Duration(12)*time.Hour), tmr.Add(time.Duration(18)*time.Hour), time.Hour, time.Duration(30)*time.Minute, 2, false); len(r) != 2 {
+		t.Errorf("Expected 2 free periods, got %v", r)
+	}
+}
+
+func TestFacility_FindFreeSlots_withinHours(t *testing.T) {
+	now := time.Now()
+	tmr := time.Date(now.Year(), now.Month(), now.Day()+1, 0, 0, 0, 0, time.Local)
+
+	hours := make([]OpeningHours, 7)
+	for i := range hours {
+		hours[i] = OpeningHours{Open: time.Duration(9) * time.Hour, Close: time.Duration(17) * time.Hour}
+	}
+	f := NewFacility(FacilityName("Testing"), FacilityWithInfo(FacilityInfo{Hours: hours}))
+
+	start, end := tmr.Add(time.Duration(16)*time.Hour), tmr.Add(time.Duration(36)*time.Hour)
+
+	// The facility closes before the first free period ends, so the next one starts when the facility opens again
+	r := f.FindFreeSlots(start, end, time.Duration(2)*time.Hour, time.Hour, 2, true)
+	e := []Interval{{Start: tmr.Add(time.Duration(33) * time.Hour), End: tmr.Add(time.Duration(35) * time.Hour)}}
+	if !equalIntervals(r, e) {
+		t.Errorf("E: %v, R: %v", e, r)
+	}
+
+	r = f.FindFreeSlots(start, end, time.Duration(2)*time.Hour, time.Hour, 2, false)
+	e = []Interval{
+		{Start: tmr.Add(time.Duration(16) * time.Hour), End: tmr.Add(time.Duration(18) * time.Hour)},
+		{Start: tmr.Add(time.Duration(18) * time.Hour), End: tmr.Add(time.Duration(20) * time.Hour)},
+	}
+	if !equalIntervals(r, e) {
+		t.Errorf("E: %v, R: %v", e, r)
+	}
+}
+
+func TestManager_FindFreeSlots_invalid(t *testing.T) {
+	manager := NewManager()
+	facilityName := FacilityName("TestManager_FindFreeSlots_invalid")
+	if err := manager.NewFacility(facilityName); err != nil {
+		t.Fatal(err)
+	}
+
+	start := time.Now().Truncate(time.Hour).Add(time.Duration(24) * time.Hour)
+	for name, search := range map[string]func() ([]Interval, error){
+		"EndsBeforeStart": func() ([]Interval, error) {
+			return manager.FindFreeSlots(facilityName, start, start.Add(-time.Hour), time.Hour, time.Hour, 1, false)
+		},
+		"NoDuration": func() ([]Interval, error) {
+			return manager.FindFreeSlots(facilityName, start, start.Add(time.Hour), 0, time.Hour, 1, false)
+		},
+		"NoCount": func() ([]Interval, error) {
+			return manager.FindFreeSlots(facilityName, start, start.Add(time.Hour), time.Hour, time.Hour, 0, false)
+		},
+		"MissingFacility": func() ([]Interval, error) {
+			return manager.FindFreeSlots("Missing", start, start.Add(time.Hour), time.Hour, time.Hour, 1, false)
+		},
+	} {
+		t.Run(name, func(t *testing.T) {
+			if _, err := search(); err == nil {
+				t.Error("Expected search to be rejected")
+			}
+		})
+	}
+}
@@ -0,0 +1,99 @@
+package bookings
+
+import (
+	"errors"
+	"log/slog"
+	"sync"
+	"time"
+)
+
+// ErrIdempotencyKeyReused is returned when an idempotency key is used again for a different request within its window.
+var ErrIdempotencyKeyReused = errors.New("idempotency key was already used for a different request")
+
+// ErrIdempotencyKeyAnonymous is returned when an idempotency key is used without a principal, as keys would otherwise
+// be shared by every anonymous client.
+var ErrIdempotencyKeyAnonymous = errors.New("idempotency key requires a principal")
+
+// idempotencyScope identifies an idempotency key, which is only unique per principal.
+type idempotencyScope struct {
+	principal string
+	key       string
+}
+
+// idempotentOutcome is the outcome of the request first made with an idempotency key, returned again on repeats.
+type idempotentOutcome struct {
+	request string
+	expires time.Time
+	done    chan struct{} // Closed once id and err are set
+	id      uint64
+	err     error
+}
+
+// idempotencyKeys remembers the outcome of requests made with an idempotency key (see Manager.idempotent).
+type idempotencyKeys struct {
+	sync.Mutex
+	outcomes map[idempotencyScope]*idempotentOutcome
+}
+
+// idempotent runs do once per idempotency key of c within c.IdempotencyTTL, and returns the outcome of that run (an Id
+// and an error) when the key is used again, even if the original run failed. request describes what is requested, so
+// that a key reused for a different request is rejected with ErrIdempotencyKeyReused. Repeats made while the original
+// run is in progress wait for it to finish. do is always run if c has no idempotency key, and never if c has a key but
+// no principal (see ErrIdempotencyKeyAnonymous).
+//
+// Outcomes are kept in memory, so keys are forgotten when the server restarts. Must not be called with the lock held.
+func (m *Manager) idempotent(c Caller, request string, do func() (uint64, error)) (uint64, error) {
+	if c.IdempotencyKey == "" {
+		return do()
+	}
+	if c.Principal == "" {
+		return 0, ErrIdempotencyKeyAnonymous
+	}
+
+	scope := idempotencyScope{principal: c.Principal, key: c.IdempotencyKey}
+	now := time.Now()
+
+	m.idempotency.Lock()
+	for s, o := range m.idempotency.outcomes {
+		if o.expires.Before(now) && isDone(o.done) {
+			delete(m.idempotency.outcomes, s)
+		}
+	}
+	if o, exists := m.idempotency.outcomes[scope]; exists {
+		m.idempotency.Unlock()
+		if o.request != request {
+			return 0, ErrIdempotencyKeyReused
+		}
+		<-o.done
+		slog.Info("Answering repeated request with its original outcome", "IdempotencyKey", c.IdempotencyKey, "Principal", c.Principal)
+		return o.id, o.err
+	}
+	o := &idempotentOutcome{request: request, expires: now.Add(c.IdempotencyTTL), done: make(chan struct{})}
+	if m.idempotency.outcomes == nil {
+		m.idempotency.outcomes = make(map[idempotencyScope]*idempotentOutcome)
+	}
+	m.idempotency.outcomes[scope] = o
+	m.idempotency.Unlock()
+
+	defer close(o.done)
+	o.id, o.err = do()
+	return o.id, o.err
+}
+
+// idempotentErr is idempotent for requests that only result in an error.
+func (m *Manager) idempotentErr(c Caller, request string, do func() error) error {
+	_, err := m.idempotent(c, request, func() (uint64, error) {
+		return 0, do()
+	})
+	return err
+}
+
+// isDone reports whether done has been closed.
+func isDone(done chan struct{}) bool {
+	select {
+	case <-done:
+		return true
+	default:
+		return false
+	}
+}
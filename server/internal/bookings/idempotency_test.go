@@ -0,0 +1,93 @@
+package bookings
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestManager_idempotencyKey(t *testing.T) {
+	manager := NewManager()
+	facilityName := FacilityName("TestManager_idempotencyKey")
+	if err := manager.NewFacility(facilityName); err != nil {
+		t.Fatal(err)
+	}
+
+	start := time.Now().Truncate(time.Hour).Add(time.Duration(24) * time.Hour)
+	b := Booking{Start: start, End: start.Add(time.Hour)}
+	alice := Caller{Principal: "alice", IdempotencyKey: "make-1", IdempotencyTTL: time.Hour}
+
+	// Repeats are answered with the Id of the original booking, instead of making another one
+	first, err := manager.NewBookingWithUnusedIdAs(alice, facilityName, b, MaxLegacyBookingId)
+	if err != nil {
+		t.Fatal(err)
+	}
+	repeat, err := manager.NewBookingWithUnusedIdAs(alice, facilityName, b, MaxLegacyBookingId)
+	if err != nil || repeat != first {
+		t.Errorf("Expected repeat to be answered with booking %d, got %d (%v)", first, repeat, err)
+	}
+	if n := len(manager.GetDeepCopyOfRecords()[facilityName].Bookings); n != 1 {
+		t.Errorf("Expected a single booking to be made, got %d", n)
+	}
+
+	// The key cannot be reused for a different request, but other principals have keys of their own
+	if _, err := manager.NewBookingWithUnusedIdAs(alice, facilityName, Booking{Start: b.End, End: b.End.Add(time.Hour)}, MaxLegacyBookingId); !errors.Is(err, ErrIdempotencyKeyReused) {
+		t.Errorf("Expected reused key to be rejected, got %v", err)
+	}
+	bob := Caller{Principal: "bob", IdempotencyKey: alice.IdempotencyKey, IdempotencyTTL: time.Hour}
+	if _, err := manager.NewBookingWithUnusedIdAs(bob, facilityName, Booking{Start: b.End, End: b.End.Add(time.Hour)}, MaxLegacyBookingId); err != nil {
+		t.Errorf("Expected key of another principal to be unaffected, got %v", err)
+	}
+
+	// The key cannot be reused for a request with a different maximum Id, whose Id may not fit the original
+	if _, err := manager.NewBookingWithUnusedIdAs(alice, facilityName, b, MaxLegacyBookingId>>8); !errors.Is(err, ErrIdempotencyKeyReused) {
+		t.Errorf("Expected key reused with another maximum Id to be rejected, got %v", err)
+	}
+
+	// Keys cannot be used without a principal, as anonymous clients would share them
+	anonymous := Caller{IdempotencyKey: "make-2", IdempotencyTTL: time.Hour}
+	if _, err := manager.NewBookingWithUnusedIdAs(anonymous, facilityName, Booking{Start: b.End, End: b.End.Add(time.Hour)}, MaxLegacyBookingId); !errors.Is(err, ErrIdempotencyKeyAnonymous) {
+		t.Errorf("Expected key without a principal to be rejected, got %v", err)
+	}
+
+	// Facility updates are repeated once, and cannot reuse their key for other information
+	update := Caller{Principal: "alice", IdempotencyKey: "update-1", IdempotencyTTL: time.Hour}
+	info := FacilityInfo{Timezone: "Asia/Singapore"}
+	for i := 0; i < 2; i++ {
+		if err := manager.UpdateFacilityAs(update, facilityName, 1, info); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := manager.UpdateFacilityAs(update, facilityName, 1, FacilityInfo{Timezone: "Europe/Berlin"}); !errors.Is(err, ErrIdempotencyKeyReused) {
+		t.Errorf("Expected key reused for another update to be rejected, got %v", err)
+	}
+
+	// Failures are remembered too, even once the request would succeed
+	shift := Caller{Principal: "alice", IdempotencyKey: "shift-1", IdempotencyTTL: time.Hour}
+	if err := manager.ShiftBookingFromIdAs(shift, first, time.Hour); err == nil {
+		t.Fatal("Expected shift onto another booking to fail")
+	}
+	for _, b := range manager.GetDeepCopyOfRecords()[facilityName].Bookings {
+		if b.Id != first {
+			if err := manager.DeleteBookingFromId(b.Id); err != nil {
+				t.Fatal(err)
+			}
+		}
+	}
+	if err := manager.ShiftBookingFromIdAs(shift, first, time.Hour); err == nil {
+		t.Error("Expected repeat to be answered with the original failure")
+	}
+
+	// Keys are forgotten once their window has passed
+	short := Caller{Principal: "alice", IdempotencyKey: "shift-2", IdempotencyTTL: time.Millisecond}
+	if err := manager.ShiftBookingFromIdAs(short, first, time.Hour); err != nil {
+		t.Fatal(err)
+	}
+	time.Sleep(time.Duration(5) * time.Millisecond)
+	if err := manager.ShiftBookingFromIdAs(short, first, time.Hour); err != nil {
+		t.Fatal(err)
+	}
+	if b := manager.GetDeepCopyOfRecords()[facilityName].BookingMap[first]; !b.Start.Equal(start.Add(time.Duration(2) * time.Hour)) {
+		t.Errorf("Expected booking to be shifted twice, got %v", b)
+	}
+}
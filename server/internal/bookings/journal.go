@@ -0,0 +1,60 @@
+package bookings
+
+type MutationType uint8
+
+const (
+	MutationFacilityCreate MutationType = 0x01
+	MutationFacilityDelete MutationType = 0x02
+	MutationFacilityUpdate MutationType = 0x03
+
+	MutationBookingMake   MutationType = 0x11
+	MutationBookingUpdate MutationType = 0x12
+	MutationBookingDelete MutationType = 0x13
+	MutationBookingsMake  MutationType = 0x14 // Bookings made together, possibly in different facilities
+
+	MutationSeriesMake   MutationType = 0x21
+	MutationSeriesUpdate MutationType = 0x22
+	MutationSeriesDelete MutationType = 0x23
+
+	MutationWaitlistJoin  MutationType = 0x31
+	MutationWaitlistLeave MutationType = 0x32
+
+	MutationReset MutationType = 0xFF
+)
+
+// Mutation describes a single successful change made to the facilities and bookings held by a Manager.
+// Replaying the mutations of a Manager in order onto an empty Manager results in the same facilities and bookings.
+type Mutation struct {
+	Type      MutationType  `json:"type"`
+	Facility  FacilityName  `json:"facility,omitempty"`
+	Capacity  int           `json:"capacity,omitempty"`   // Capacity of the facility, for facility create and update; 0 for a capacity of 1
+	Info      *FacilityInfo `json:"info,omitempty"`       // Information of the facility, for facility create and update
+	Booking   *Booking      `json:"booking,omitempty"`    // Booking after the mutation, for make and update, or waitlisted booking for join
+	BookingId uint64        `json:"booking_id,omitempty"` // Booking affected by the mutation, for update, delete and leave
+	Series    *Series       `json:"series,omitempty"`     // Series after the mutation, for series make and update
+	Bookings  []Booking     `json:"bookings,omitempty"`   // Occurrences of the series after the mutation, for series make and update
+	Batch     []Mutation    `json:"batch,omitempty"`      // Mutations made together, for bookings make
+}
+
+// Journal durably records the mutations made to a Manager.
+type Journal interface {
+	// Append records mut, and must only return once mut has been persisted.
+	Append(mut Mutation) error
+
+	// Snapshot records the complete state of a Manager, superseding all previously appended mutations.
+	Snapshot(facilities map[FacilityName]*Facility) error
+}
+
+// AuditJournal durably records the audit log of a Manager (see AuditEntry). Unlike mutations, entries are never
+// superseded by snapshots. An entry is recorded after the change it describes, so the entry of the last change may be
+// missing if the server crashed in between.
+type AuditJournal interface {
+	// AppendAudit records e, and must only return once e has been persisted.
+	AppendAudit(e AuditEntry) error
+
+	// ReadAudit calls fn with every recorded entry, oldest first, until fn returns false.
+	ReadAudit(fn func(e AuditEntry) bool) error
+
+	// AuditSeq returns the sequence of the last recorded entry, 0 if there is none.
+	AuditSeq() uint64
+}
@@ -4,32 +4,202 @@ import (
 	"errors"
 	"fmt"
 	"log/slog"
+	"math"
+	"slices"
 	"sync"
 	"time"
 )
 
 // Manager is responsible for handling facilities, bookings and monitoring.
+//
+// Changes to facilities and bookings are serialised, and recorded in the Manager's Journal (if any) in the order they
+// are made. A change that cannot be recorded is undone, and an error is returned.
 type Manager struct {
 	sync.RWMutex
 	Facilities map[FacilityName]*Facility
 	monitor    *Monitor
+	journal    Journal
+
+	// bookingIndex holds the facility of every booking and waitlisted booking by Id, which is unique across all
+	// facilities.
+	bookingIndex map[uint64]FacilityName
+
+	// auditLog holds the latest changes made since m was created, in the order they were made (see QueryAudit),
+	// unless they are recorded in auditJournal instead.
+	auditLog     []AuditEntry
+	auditSeq     uint64
+	auditJournal AuditJournal
+
+	// idempotency remembers the outcome of requests made with an idempotency key (see Caller.IdempotencyKey).
+	idempotency idempotencyKeys
+
+	// releases holds the timers releasing held bookings by Id (see scheduleRelease), which are stopped once closed.
+	releases map[uint64]*time.Timer
+	closed   bool
 }
 
-var (
-	manager     *Manager
-	onceManager sync.Once
-)
+func NewManager() *Manager {
+	return &Manager{
+		Facilities:   make(map[FacilityName]*Facility),
+		monitor:      NewMonitor(),
+		bookingIndex: make(map[uint64]FacilityName),
+		releases:     make(map[uint64]*time.Timer),
+	}
+}
+
+// Close stops releasing held bookings, as their release can no longer be recorded once the journal of m is closed.
+// Held bookings are released by the next Manager restored from the journal instead.
+func (m *Manager) Close() {
+	m.Lock()
+	defer m.Unlock()
 
-func GetManager() *Manager {
+	m.closed = true
+	for id, t := range m.releases {
+		t.Stop()
+		delete(m.releases, id)
+	}
+}
 
-	onceManager.Do(func() {
-		manager = &Manager{
-			Facilities: make(map[FacilityName]*Facility),
-			monitor:    GetMonitor(),
+// Monitor returns the Monitor that receives updates for the facilities held by m.
+func (m *Manager) Monitor() *Monitor {
+	return m.monitor
+}
+
+// SetJournal sets the Journal that records all subsequent changes made to m.
+func (m *Manager) SetJournal(j Journal) {
+	m.Lock()
+	defer m.Unlock()
+	m.journal = j
+}
+
+// SetAuditJournal sets the AuditJournal that records all subsequent entries of the audit log of m, which continue the
+// sequence of the entries already recorded by j. Entries are then queried from j, rather than kept in memory.
+func (m *Manager) SetAuditJournal(j AuditJournal) {
+	m.Lock()
+	defer m.Unlock()
+	m.auditJournal = j
+	m.auditSeq = max(m.auditSeq, j.AuditSeq())
+	m.auditLog = nil
+}
+
+// record appends mut to the journal, calling undo to revert the change if mut cannot be recorded.
+// Must be called with the write lock held, after the change described by mut has been made.
+func (m *Manager) record(mut Mutation, undo func()) error {
+	if m.journal == nil {
+		return nil
+	}
+	if err := m.journal.Append(mut); err != nil {
+		slog.Error("Unable to record change, reverting", "Mutation", mut, "err", err)
+		undo()
+		return fmt.Errorf("unable to persist change: %w", err)
+	}
+	return nil
+}
+
+// Snapshot records the current facilities and bookings in the journal, returns an error if m has no journal.
+func (m *Manager) Snapshot() error {
+	m.RLock()
+	defer m.RUnlock()
+
+	if m.journal == nil {
+		return errors.New("manager has no journal")
+	}
+
+	records := make(map[FacilityName]*Facility)
+	for n, f := range m.Facilities {
+		records[n] = f.DeepCopy()
+	}
+	return m.journal.Snapshot(records)
+}
+
+// Apply makes the change described by mut without recording it or notifying the monitor, and is used to restore the
+// state of m from its journal.
+func (m *Manager) Apply(mut Mutation) error {
+	m.Lock()
+	defer m.Unlock()
+	return m.apply(mut)
+}
+
+// apply must be called with the write lock held.
+func (m *Manager) apply(mut Mutation) error {
+	switch mut.Type {
+	case MutationReset:
+		m.Facilities = make(map[FacilityName]*Facility)
+		m.bookingIndex = make(map[uint64]FacilityName)
+		return nil
+	case MutationFacilityCreate:
+		if _, exists := m.Facilities[mut.Facility]; exists {
+			return errors.New("facility already exists")
 		}
-	})
+		f := NewFacility(mut.Facility)
+		if mut.Capacity > 0 {
+			f.Capacity = mut.Capacity
+		}
+		if mut.Info != nil {
+			f.Info = *mut.Info
+		}
+		m.Facilities[mut.Facility] = f
+		return nil
+	case MutationBookingsMake:
+		for _, b := range mut.Batch {
+			if err := m.apply(b); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+
+	f, exists := m.Facilities[mut.Facility]
+	if !exists {
+		return errors.New("facility does not exist")
+	}
+
+	switch mut.Type {
+	case MutationFacilityDelete:
+		delete(m.Facilities, mut.Facility)
+	case MutationFacilityUpdate:
+		f.Lock()
+		f.Capacity = max(mut.Capacity, 1)
+		f.Info = FacilityInfo{}
+		if mut.Info != nil {
+			f.Info = *mut.Info
+		}
+		f.Unlock()
+	case MutationBookingMake, MutationBookingUpdate:
+		if mut.Booking == nil {
+			return errors.New("mutation is missing booking")
+		}
+		f.restoreBooking(*mut.Booking)
 
-	return manager
+		// Bookings made for waitlisted bookings leave the waitlist
+		f.LeaveWaitlist(mut.Booking.Id)
+		if mut.Booking.IsHeld() {
+			m.scheduleRelease(mut.Booking.Id, mut.Booking.HeldUntil)
+		}
+	case MutationBookingDelete:
+		f.DeleteBooking(mut.BookingId)
+	case MutationSeriesMake, MutationSeriesUpdate:
+		if mut.Series == nil {
+			return errors.New("mutation is missing series")
+		}
+		f.restoreSeries(*mut.Series, mut.Bookings)
+	case MutationSeriesDelete:
+		if mut.Series == nil {
+			return errors.New("mutation is missing series")
+		}
+		f.DeleteSeries(mut.Series.Id)
+	case MutationWaitlistJoin:
+		if mut.Booking == nil {
+			return errors.New("mutation is missing booking")
+		}
+		f.restoreWaiter(*mut.Booking)
+	case MutationWaitlistLeave:
+		f.LeaveWaitlist(mut.BookingId)
+	default:
+		return fmt.Errorf("unknown mutation type %d", mut.Type)
+	}
+	m.indexBookings(f)
+	return nil
 }
 
 func (m *Manager) Reset() {
@@ -38,11 +208,29 @@ func (m *Manager) Reset() {
 
 	slog.Warn("Resetting Facility/Booking Manager. This will remove all existing facilities and bookings; monitor will be reset")
 
+	previous, previousIndex := m.Facilities, m.bookingIndex
 	m.Facilities = make(map[FacilityName]*Facility)
+	m.bookingIndex = make(map[uint64]FacilityName)
+	if err := m.record(Mutation{Type: MutationReset}, func() { m.Facilities, m.bookingIndex = previous, previousIndex }); err != nil {
+		return
+	}
+	m.audit(SystemCaller, MutationReset, "", 0, nil, nil)
 	m.monitor.Reset()
 }
 
-func (m *Manager) NewFacility(name FacilityName) error {
+func (m *Manager) NewFacility(name FacilityName, opts ...FacilityOption) error {
+	return m.NewFacilityAs(SystemCaller, name, opts...)
+}
+
+// NewFacilityAs is NewFacility on behalf of c.
+func (m *Manager) NewFacilityAs(c Caller, name FacilityName, opts ...FacilityOption) error {
+	return m.idempotentErr(c, fmt.Sprintf("FacilityCreate %s", name), func() error {
+		return m.newFacility(c, name, opts...)
+	})
+}
+
+// newFacility must not be called with the lock held.
+func (m *Manager) newFacility(c Caller, name FacilityName, opts ...FacilityOption) error {
 	m.Lock()
 	defer m.Unlock()
 
@@ -50,29 +238,143 @@ func (m *Manager) NewFacility(name FacilityName) error {
 		return errors.New("facility name cannot be empty")
 	}
 
+	f := NewFacility(name, opts...)
+	if f.Capacity < 1 {
+		return errors.New("facility capacity must be at least 1")
+	}
+	if err := f.Info.validate(); err != nil {
+		return err
+	}
+
 	if _, exists := m.Facilities[name]; exists {
 		slog.Error("Attempted to create a Facility that already exists!", "Facility", name)
 		return errors.New("facility already exists")
 	}
-	m.Facilities[name] = NewFacility(name)
+	m.Facilities[name] = f
+
+	if err := m.record(
+		Mutation{Type: MutationFacilityCreate, Facility: name, Capacity: f.Capacity, Info: &f.Info},
+		func() { delete(m.Facilities, name) },
+	); err != nil {
+		return err
+	}
+
+	m.audit(c, MutationFacilityCreate, name, 0, nil, facilityState(f.Capacity, f.Info))
+	return nil
+}
+
+// UpdateFacility replaces the capacity and information of an existing facility (see Facility.Update).
+func (m *Manager) UpdateFacility(name FacilityName, capacity int, info FacilityInfo) error {
+	return m.UpdateFacilityAs(SystemCaller, name, capacity, info)
+}
+
+// UpdateFacilityAs is UpdateFacility on behalf of c.
+func (m *Manager) UpdateFacilityAs(c Caller, name FacilityName, capacity int, info FacilityInfo) error {
+	return m.idempotentErr(c, fmt.Sprintf("FacilityUpdate %s %d %+v", name, capacity, info), func() error {
+		return m.updateFacility(c, name, capacity, info)
+	})
+}
+
+// updateFacility must not be called with the lock held.
+func (m *Manager) updateFacility(c Caller, name FacilityName, capacity int, info FacilityInfo) error {
+	m.Lock()
+	defer m.Unlock()
+
+	f, exists := m.Facilities[name]
+	if !exists {
+		slog.Error("Attempted to update a Facility that does not exist!", "Facility", name)
+		return errors.New("facility does not exist")
+	}
+
+	original := f.DeepCopy()
+	if err := f.Update(capacity, info); err != nil {
+		slog.Error("Unable to update Facility", "Facility", name, "err", err)
+		return err
+	}
+	if err := m.record(
+		Mutation{Type: MutationFacilityUpdate, Facility: name, Capacity: capacity, Info: &info},
+		func() { _ = f.Update(original.Capacity, original.Info) },
+	); err != nil {
+		return err
+	}
+
+	m.audit(c, MutationFacilityUpdate, name, 0, facilityState(original.Capacity, original.Info), facilityState(capacity, info))
+	m.monitor.Update(name, "Facility information has been updated")
+	m.promoteWaiters(f)
 	return nil
 }
 
+// QueryFacility queries the availability of a facility for the next number of days, by the hour (see
+// Facility.QueryAvailability). Closed hours are marked like booked hours; QueryFacilityCapacity marks them distinctly.
 func (m *Manager) QueryFacility(n FacilityName, days int) ([]byte, error) {
+	return m.QueryFacilityAt(n, days, time.Hour)
+}
+
+// QueryFacilityAt queries the availability of a facility for the next number of days, in slots of the given
+// resolution (see Facility.QueryAvailabilityAt).
+func (m *Manager) QueryFacilityAt(n FacilityName, days int, resolution time.Duration) ([]byte, error) {
+	return m.QueryFacilityFrom(n, today(), days, resolution)
+}
+
+// QueryFacilityFrom queries the availability of a facility for a number of days from first, in slots of the given
+// resolution (see Facility.QueryAvailabilityFrom).
+func (m *Manager) QueryFacilityFrom(n FacilityName, first time.Time, days int, resolution time.Duration) ([]byte, error) {
+	m.RLock()
+	defer m.RUnlock()
+
+	if resolution <= 0 || (24*time.Hour)%resolution != 0 {
+		return []byte{}, fmt.Errorf("resolution %v must divide a day", resolution)
+	}
 	if _, exists := m.Facilities[n]; !exists {
 		slog.Error("Facility does not exist!", "FacilityName", n)
 		return []byte{}, errors.New("facility does not exist")
 	}
-	m.monitor.Update(n, fmt.Sprintf("Executing query on %s for %d days", n, days))
-	return m.Facilities[n].QueryAvailability(days), nil
+	m.monitor.Update(n, fmt.Sprintf("Executing query on %s for %d days from %s", n, days, first.Format(time.DateOnly)))
+	return m.Facilities[n].QueryAvailabilityFrom(first, days, resolution), nil
+}
+
+// QueryFacilityCapacity queries the remaining capacity of a facility for the next number of days, in slots of the
+// given resolution (see Facility.QueryCapacity).
+func (m *Manager) QueryFacilityCapacity(n FacilityName, days int, resolution time.Duration) ([]byte, error) {
+	return m.QueryFacilityCapacityFrom(n, today(), days, resolution)
+}
+
+// QueryFacilityCapacityFrom queries the remaining capacity of a facility for a number of days from first, in slots of
+// the given resolution (see Facility.QueryCapacityFrom).
+func (m *Manager) QueryFacilityCapacityFrom(n FacilityName, first time.Time, days int, resolution time.Duration) ([]byte, error) {
+	m.RLock()
+	defer m.RUnlock()
+
+	if resolution <= 0 || (24*time.Hour)%resolution != 0 {
+		return []byte{}, fmt.Errorf("resolution %v must divide a day", resolution)
+	}
+	if _, exists := m.Facilities[n]; !exists {
+		slog.Error("Facility does not exist!", "FacilityName", n)
+		return []byte{}, errors.New("facility does not exist")
+	}
+	m.monitor.Update(n, fmt.Sprintf("Executing capacity query on %s for %d days from %s", n, days, first.Format(time.DateOnly)))
+	return m.Facilities[n].QueryCapacityFrom(first, days, resolution), nil
 }
 
 func (m *Manager) DeleteFacility(name FacilityName) error {
+	return m.DeleteFacilityAs(SystemCaller, name)
+}
+
+// DeleteFacilityAs is DeleteFacility on behalf of c.
+func (m *Manager) DeleteFacilityAs(c Caller, name FacilityName) error {
+	return m.idempotentErr(c, fmt.Sprintf("FacilityDelete %s", name), func() error {
+		return m.deleteFacility(c, name)
+	})
+}
+
+// deleteFacility must not be called with the lock held.
+func (m *Manager) deleteFacility(c Caller, name FacilityName) error {
 	m.Lock()
 	defer m.Unlock()
 
 	// Check if it exists
-	if r, exists := m.Facilities[name]; !exists || len(r.Bookings) > 0 {
+	r, exists := m.Facilities[name]
+	if !exists || len(r.Bookings) > 0 || len(r.Waitlist) > 0 {
 		switch {
 		case !exists:
 			slog.Error("Attempted to delete a Facility that does not exists!", "Facility", name)
@@ -81,91 +383,236 @@ func (m *Manager) DeleteFacility(name FacilityName) error {
 			slog.Error("Attempted to delete a Facility with existing bookings!", "Facility", name, "bookings", r)
 			m.monitor.Update(name, "A deletion was attempted on this facility! There are existing bookings can cannot be deleted yet.")
 			return errors.New("facility has existing bookings")
+		case len(r.Waitlist) > 0:
+			slog.Error("Attempted to delete a Facility with waitlisted bookings!", "Facility", name, "waitlist", r.Waitlist)
+			m.monitor.Update(name, "A deletion was attempted on this facility! There are waitlisted bookings that cannot be deleted yet.")
+			return errors.New("facility has waitlisted bookings")
 		}
 	}
 
 	// "OK" to delete at this point
 	delete(m.Facilities, name)
+	if err := m.record(
+		Mutation{Type: MutationFacilityDelete, Facility: name},
+		func() { m.Facilities[name] = r },
+	); err != nil {
+		return err
+	}
+
+	m.audit(c, MutationFacilityDelete, name, 0, facilityState(r.Capacity, r.Info), nil)
 	m.monitor.Update(name, "This facility has been deleted.")
 	slog.Info("Deleted facility", "FacilityName", name)
 	m.monitor.Clear(name)
 	return nil
 }
 
+// NewBooking makes the booking b in the facility, b.Id must not be in use by any booking.
 func (m *Manager) NewBooking(n FacilityName, b Booking) error {
 	m.Lock()
 	defer m.Unlock()
+	return m.newBooking(SystemCaller, n, b)
+}
+
+// NewBookingWithUnusedId makes the booking b in the facility, assigning it an Id no larger than maxId that is not in
+// use by any booking. Returns the assigned Id.
+func (m *Manager) NewBookingWithUnusedId(n FacilityName, b Booking, maxId uint64) (uint64, error) {
+	return m.NewBookingWithUnusedIdAs(SystemCaller, n, b, maxId)
+}
+
+// NewBookingWithUnusedIdAs is NewBookingWithUnusedId on behalf of c.
+func (m *Manager) NewBookingWithUnusedIdAs(c Caller, n FacilityName, b Booking, maxId uint64) (uint64, error) {
+	// maxId differs between payload versions, which must not be answered with each other's (possibly wider) Ids
+	return m.idempotent(c, fmt.Sprintf("BookingMake %s %d %d %d", n, b.Start.Unix(), b.End.Unix(), maxId), func() (uint64, error) {
+		return m.newBookingWithUnusedId(c, n, b, maxId)
+	})
+}
+
+// newBookingWithUnusedId must not be called with the lock held.
+func (m *Manager) newBookingWithUnusedId(c Caller, n FacilityName, b Booking, maxId uint64) (uint64, error) {
+	m.Lock()
+	defer m.Unlock()
+
+	id, err := m.unusedBookingId(maxId, nil)
+	if err != nil {
+		return 0, err
+	}
+	b.Id = id
+	return id, m.newBooking(c, n, b)
+}
+
+// newBooking must be called with the write lock held.
+func (m *Manager) newBooking(c Caller, n FacilityName, b Booking) error {
+	if m.facilityOfBooking(b.Id) != nil || m.facilityOfWaiter(b.Id) != nil {
+		slog.Error("Booking Id is already in use!", "BookingId", b.Id)
+		return errors.New("booking Id is already in use")
+	}
 
 	if b.End.Before(time.Now()) {
 		slog.Error("Booking time must not end before current time!", "currentTime", time.Now(), "bookingTimeEnd", b.End)
 	}
 
-	if _, exists := m.Facilities[n]; !exists {
+	f, exists := m.Facilities[n]
+	if !exists {
 		slog.Error("Attempted to book a Facility that does not exists!", "FacilityName", n)
 		return errors.New("facility does not exists")
 	}
-	if err := m.Facilities[n].Book(b); err != nil {
+	if err := f.Book(b); err != nil {
 		slog.Error("Unable to make booking", "FacilityName", n, "Booking", b)
 		m.monitor.Update(n, fmt.Sprintf("Error attempting to make booking at %s with %v.", n, b))
 		return err
 	}
+	if err := m.record(
+		Mutation{Type: MutationBookingMake, Facility: n, Booking: &b, BookingId: b.Id},
+		func() { f.DeleteBooking(b.Id) },
+	); err != nil {
+		return err
+	}
+	m.bookingIndex[b.Id] = n
+	m.audit(c, MutationBookingMake, n, b.Id, nil, bookingState(&b))
+
 	slog.Info("Made successful booking", "FacilityName", n, "Booking", b)
+	if b.IsHeld() {
+		m.monitor.Update(n, fmt.Sprintf("Successfully held booking at %s with %v until %v", n, b, b.HeldUntil))
+		m.scheduleRelease(b.Id, b.HeldUntil)
+		return nil
+	}
 	m.monitor.Update(n, fmt.Sprintf("Successfully made booking at %s with %v", n, b))
 	return nil
 }
 
-func (m *Manager) UpdateBooking(n FacilityName, bookingId uint16, deltaHours int) error {
-	m.RLock()
-	defer m.RUnlock()
+func (m *Manager) UpdateBooking(n FacilityName, bookingId uint64, deltaHours int) error {
+	m.Lock()
+	defer m.Unlock()
+	return m.updateBooking(SystemCaller, n, bookingId, time.Duration(deltaHours)*time.Hour)
+}
 
-	if _, exists := m.Facilities[n]; !exists {
+// updateBooking must be called with the write lock held.
+func (m *Manager) updateBooking(c Caller, n FacilityName, bookingId uint64, delta time.Duration) error {
+	f, exists := m.Facilities[n]
+	if !exists {
 		slog.Error("Facility does not exist!", "FacilityName", n)
 		return errors.New("facility does not exist")
 	}
-	if err := m.Facilities[n].UpdateBooking(bookingId, deltaHours); err != nil {
-		slog.Error("Failed to update booking!", "BookingId", bookingId, "DeltaHours", deltaHours)
-		m.monitor.Update(n, fmt.Sprintf("Failed to update BookingId %v by %d hours.", bookingId, deltaHours))
+
+	original, _ := f.getBooking(bookingId)
+	if err := f.ShiftBooking(bookingId, delta); err != nil {
+		slog.Error("Failed to update booking!", "BookingId", bookingId, "Delta", delta)
+		m.monitor.Update(n, fmt.Sprintf("Failed to update BookingId %v by %v.", bookingId, delta))
 		return err
 	}
-	m.monitor.Update(n, fmt.Sprintf("Updated BookingId %v by %d hours", bookingId, deltaHours))
+	updated, _ := f.getBooking(bookingId)
+	if err := m.record(
+		Mutation{Type: MutationBookingUpdate, Facility: n, Booking: &updated, BookingId: bookingId},
+		func() { f.restoreBooking(original) },
+	); err != nil {
+		return err
+	}
+
+	m.audit(c, MutationBookingUpdate, n, bookingId, bookingState(&original), bookingState(&updated))
+	m.monitor.Update(n, fmt.Sprintf("Updated BookingId %v by %v", bookingId, delta))
+	m.promoteWaiters(f)
 	return nil
 }
 
-func (m *Manager) UpdateBookingFromId(id uint16, deltaHours int) error {
-	m.RLock()
-	defer m.RUnlock()
+func (m *Manager) UpdateBookingFromId(id uint64, deltaHours int) error {
+	return m.ShiftBookingFromId(id, time.Duration(deltaHours)*time.Hour)
+}
 
-	updated := false
+// ShiftBookingFromId moves the booking with the given id by delta, in whichever facility it is in.
+func (m *Manager) ShiftBookingFromId(id uint64, delta time.Duration) error {
+	return m.ShiftBookingFromIdAs(SystemCaller, id, delta)
+}
 
-	for _, f := range m.Facilities {
-		if f.HasId(id) {
-			if err := m.UpdateBooking(f.Name, id, deltaHours); err != nil {
-				return err
-			}
-			updated = true
-		}
+// ShiftBookingFromIdAs is ShiftBookingFromId on behalf of c, returning ErrForbidden if c may not change the booking.
+func (m *Manager) ShiftBookingFromIdAs(c Caller, id uint64, delta time.Duration) error {
+	return m.idempotentErr(c, fmt.Sprintf("BookingUpdate %d %d", id, delta), func() error {
+		return m.shiftBookingFromId(c, id, delta)
+	})
+}
+
+// shiftBookingFromId must not be called with the lock held.
+func (m *Manager) shiftBookingFromId(c Caller, id uint64, delta time.Duration) error {
+	m.Lock()
+	defer m.Unlock()
+
+	f, err := m.authorizeBooking(c, id)
+	if err != nil {
+		return err
 	}
 
-	if !updated {
-		slog.Error("Booking with Id not found!", "BookingId", id)
-		return errors.New("booking with Id not found")
+	return m.updateBooking(c, f.Name, id, delta)
+}
+
+// ResizeBookingFromId changes the start and end of the booking with the given id independently (see
+// Facility.ResizeBooking), in whichever facility it is in.
+func (m *Manager) ResizeBookingFromId(id uint64, start time.Time, end time.Time) error {
+	return m.ResizeBookingFromIdAs(SystemCaller, id, start, end)
+}
+
+// ResizeBookingFromIdAs is ResizeBookingFromId on behalf of c, returning ErrForbidden if c may not change the booking.
+func (m *Manager) ResizeBookingFromIdAs(c Caller, id uint64, start time.Time, end time.Time) error {
+	return m.idempotentErr(c, fmt.Sprintf("BookingResize %d %d %d", id, start.Unix(), end.Unix()), func() error {
+		return m.resizeBookingFromId(c, id, start, end)
+	})
+}
+
+// resizeBookingFromId must not be called with the lock held.
+func (m *Manager) resizeBookingFromId(c Caller, id uint64, start time.Time, end time.Time) error {
+	m.Lock()
+	defer m.Unlock()
+
+	f, err := m.authorizeBooking(c, id)
+	if err != nil {
+		return err
 	}
 
+	original, _ := f.getBooking(id)
+	if err := f.ResizeBooking(id, start, end); err != nil {
+		slog.Error("Failed to resize booking!", "BookingId", id, "Start", start, "End", end)
+		m.monitor.Update(f.Name, fmt.Sprintf("Failed to resize BookingId %v.", id))
+		return err
+	}
+	updated, _ := f.getBooking(id)
+	if err := m.record(
+		Mutation{Type: MutationBookingUpdate, Facility: f.Name, Booking: &updated, BookingId: id},
+		func() { f.restoreBooking(original) },
+	); err != nil {
+		return err
+	}
+
+	m.audit(c, MutationBookingUpdate, f.Name, id, bookingState(&original), bookingState(&updated))
+	m.monitor.Update(f.Name, fmt.Sprintf("Resized BookingId %v from %v - %v to %v - %v", id, original.Start, original.End, updated.Start, updated.End))
+	m.promoteWaiters(f)
 	return nil
 }
 
-func (m *Manager) DeleteBooking(n FacilityName, bookingId uint16) error {
-	m.RLock()
-	defer m.RUnlock()
+func (m *Manager) DeleteBooking(n FacilityName, bookingId uint64) error {
+	m.Lock()
+	defer m.Unlock()
+	return m.deleteBooking(SystemCaller, n, bookingId)
+}
 
-	if _, exists := m.Facilities[n]; !exists {
+// deleteBooking must be called with the write lock held.
+func (m *Manager) deleteBooking(c Caller, n FacilityName, bookingId uint64) error {
+	f, exists := m.Facilities[n]
+	if !exists {
 		slog.Error("Facility does not exist!", "FacilityName", n)
 		return errors.New("facility does not exist")
 	}
 
-	if deleted := m.Facilities[n].DeleteBooking(bookingId); deleted {
+	original, _ := f.getBooking(bookingId)
+	if deleted := f.DeleteBooking(bookingId); deleted {
+		if err := m.record(
+			Mutation{Type: MutationBookingDelete, Facility: n, BookingId: bookingId},
+			func() { f.restoreBooking(original) },
+		); err != nil {
+			return err
+		}
+		delete(m.bookingIndex, bookingId)
+		m.audit(c, MutationBookingDelete, n, bookingId, bookingState(&original), nil)
 		slog.Info("Deleted booking", "BookingId", bookingId)
 		m.monitor.Update(n, fmt.Sprintf("Successfully deleted Booking %X from %s.", bookingId, n))
+		m.promoteWaiters(f)
 	} else {
 		slog.Warn("Attempted to delete non-existent booking", "BookingId", bookingId)
 		m.monitor.Update(n, fmt.Sprintf("Attempted to delete non-existant Booking %X from %s.", bookingId, n))
@@ -174,29 +621,147 @@ func (m *Manager) DeleteBooking(n FacilityName, bookingId uint16) error {
 	return nil
 }
 
-func (m *Manager) DeleteBookingFromId(id uint16) error {
-	m.RLock()
-	defer m.RUnlock()
+func (m *Manager) DeleteBookingFromId(id uint64) error {
+	return m.DeleteBookingFromIdAs(SystemCaller, id)
+}
 
-	deleted := false
+// DeleteBookingFromIdAs is DeleteBookingFromId on behalf of c, returning ErrForbidden if c may not change the booking.
+func (m *Manager) DeleteBookingFromIdAs(c Caller, id uint64) error {
+	return m.idempotentErr(c, fmt.Sprintf("BookingDelete %d", id), func() error {
+		return m.deleteBookingFromId(c, id)
+	})
+}
 
-	for _, f := range m.Facilities {
-		if f.HasId(id) {
-			if err := m.DeleteBooking(f.Name, id); err != nil {
-				return err
-			}
-			deleted = true
-		}
+// deleteBookingFromId must not be called with the lock held.
+func (m *Manager) deleteBookingFromId(c Caller, id uint64) error {
+	m.Lock()
+	defer m.Unlock()
+
+	f, err := m.authorizeBooking(c, id)
+	if err != nil {
+		return err
 	}
 
-	if !deleted {
-		slog.Error("Booking with Id not found!", "BookingId", id)
-		return errors.New("booking with Id not found")
+	return m.deleteBooking(c, f.Name, id)
+}
+
+// HoldBooking makes b in the facility as a tentative booking, assigning it an Id that is not in use by any booking. The
+// booking is released once ttl has passed, unless it has been confirmed (see ConfirmBookingAs). Returns the assigned Id
+// and when the booking is released.
+func (m *Manager) HoldBooking(n FacilityName, b Booking, ttl time.Duration) (uint64, time.Time, error) {
+	return m.HoldBookingAs(SystemCaller, n, b, ttl)
+}
+
+// HoldBookingAs is HoldBooking on behalf of c.
+func (m *Manager) HoldBookingAs(c Caller, n FacilityName, b Booking, ttl time.Duration) (uint64, time.Time, error) {
+	m.Lock()
+	defer m.Unlock()
+
+	if ttl <= 0 {
+		return 0, time.Time{}, errors.New("hold must last for a positive duration")
+	}
+
+	id, err := m.unusedBookingId(math.MaxUint64, nil)
+	if err != nil {
+		return 0, time.Time{}, err
+	}
+	b.Id = id
+	b.HeldUntil = time.Now().Add(ttl)
+	return id, b.HeldUntil, m.newBooking(c, n, b)
+}
+
+// ConfirmBookingAs turns the held booking with the given id into a confirmed booking on behalf of c, returning
+// ErrForbidden if c may not change the booking.
+func (m *Manager) ConfirmBookingAs(c Caller, id uint64) error {
+	m.Lock()
+	defer m.Unlock()
+
+	f, err := m.authorizeBooking(c, id)
+	if err != nil {
+		return err
+	}
+
+	original, _ := f.getBooking(id)
+	switch {
+	case !original.IsHeld():
+		return errors.New("booking is not held")
+	case !time.Now().Before(original.HeldUntil):
+		return errors.New("hold on booking has expired")
 	}
 
+	confirmed := original
+	confirmed.HeldUntil = time.Time{}
+	f.restoreBooking(confirmed)
+	if err := m.record(
+		Mutation{Type: MutationBookingUpdate, Facility: f.Name, Booking: &confirmed, BookingId: id},
+		func() { f.restoreBooking(original) },
+	); err != nil {
+		return err
+	}
+
+	m.audit(c, MutationBookingUpdate, f.Name, id, bookingState(&original), bookingState(&confirmed))
+	slog.Info("Confirmed held booking", "FacilityName", f.Name, "Booking", confirmed)
+	m.monitor.Update(f.Name, fmt.Sprintf("Held Booking %X at %s has been confirmed", id, f.Name))
 	return nil
 }
 
+// scheduleRelease deletes the held booking with the given id once until has passed, unless the booking has been
+// confirmed by then or m has been closed. Must be called with the write lock held.
+func (m *Manager) scheduleRelease(id uint64, until time.Time) {
+	if m.closed {
+		return
+	}
+	if t, exists := m.releases[id]; exists {
+		t.Stop()
+	}
+
+	var t *time.Timer
+	t = time.AfterFunc(time.Until(until), func() {
+		m.Lock()
+		defer m.Unlock()
+
+		if m.releases[id] == t {
+			delete(m.releases, id)
+		}
+		if m.closed {
+			return
+		}
+
+		// The booking may have been confirmed, deleted or released since
+		f := m.facilityOfBooking(id)
+		if f == nil {
+			return
+		}
+		if b, _ := f.getBooking(id); !b.HeldUntil.Equal(until) {
+			return
+		}
+
+		slog.Info("Releasing held booking", "BookingId", id, "HeldUntil", until)
+		m.monitor.Update(f.Name, fmt.Sprintf("Hold on Booking %X at %s has expired, releasing it.", id, f.Name))
+		if err := m.deleteBooking(SystemCaller, f.Name, id); err != nil {
+			slog.Error("Unable to release held booking", "BookingId", id, "err", err)
+		}
+	})
+	m.releases[id] = t
+}
+
+// authorizeBooking returns the facility holding the booking with the given id, if c may change the booking.
+// Must be called with the lock held.
+func (m *Manager) authorizeBooking(c Caller, id uint64) (*Facility, error) {
+	f := m.facilityOfBooking(id)
+	if f == nil {
+		slog.Error("Booking with Id not found!", "BookingId", id)
+		return nil, errors.New("booking with Id not found")
+	}
+
+	if b, _ := f.getBooking(id); !c.mayChange(b.Owner) {
+		slog.Error("Caller is not the owner of the booking!", "BookingId", id, "Principal", c.Principal)
+		m.monitor.Update(f.Name, fmt.Sprintf("Rejected change to Booking %X by someone other than its owner.", id))
+		return nil, ErrForbidden
+	}
+	return f, nil
+}
+
 func (m *Manager) GetDeepCopyOfRecords() map[FacilityName]*Facility {
 	m.RLock()
 	defer m.RUnlock()
@@ -209,3 +774,316 @@ func (m *Manager) GetDeepCopyOfRecords() map[FacilityName]*Facility {
 
 	return res
 }
+
+// NewSeries books every occurrence of s in the facility, assigning each occurrence a new Id. Either all occurrences
+// are booked, or none are if any of them clashes. Returns the Ids of the occurrences in chronological order, which
+// are legacy Ids no larger than MaxLegacyBookingId.
+func (m *Manager) NewSeries(n FacilityName, s Series) ([]uint64, error) {
+	return m.NewSeriesAs(SystemCaller, n, s)
+}
+
+// NewSeriesAs is NewSeries on behalf of c.
+func (m *Manager) NewSeriesAs(c Caller, n FacilityName, s Series) ([]uint64, error) {
+	m.Lock()
+	defer m.Unlock()
+
+	f, exists := m.Facilities[n]
+	if !exists {
+		slog.Error("Attempted to book a Facility that does not exists!", "FacilityName", n)
+		return nil, errors.New("facility does not exists")
+	}
+	if m.seriesIdInUse(s.Id) {
+		return nil, errors.New("series Id is already in use")
+	}
+
+	occurrences, err := s.Expand()
+	if err != nil {
+		return nil, err
+	}
+	ids := make([]uint64, len(occurrences))
+	for i := range occurrences {
+		if occurrences[i].Id, err = m.unusedBookingId(MaxLegacyBookingId, ids[:i]); err != nil {
+			return nil, err
+		}
+		ids[i] = occurrences[i].Id
+	}
+
+	if err := f.BookSeries(s, occurrences); err != nil {
+		slog.Error("Unable to make series", "FacilityName", n, "Series", s)
+		m.monitor.Update(n, fmt.Sprintf("Error attempting to make recurring booking at %s with %v.", n, s))
+		return nil, err
+	}
+	if err := m.record(
+		Mutation{Type: MutationSeriesMake, Facility: n, Series: &s, Bookings: occurrences},
+		func() { f.DeleteSeries(s.Id) },
+	); err != nil {
+		return nil, err
+	}
+	m.indexBookings(f)
+	m.auditOccurrences(c, MutationSeriesMake, n, nil, occurrences)
+
+	slog.Info("Made successful recurring booking", "FacilityName", n, "Series", s, "Occurrences", len(occurrences))
+	m.monitor.Update(n, fmt.Sprintf("Successfully made recurring booking at %s with %d occurrences", n, len(occurrences)))
+	return ids, nil
+}
+
+// ShiftSeriesFromId moves every remaining occurrence of the series with the given id by delta. Either all occurrences
+// are moved, or none are if any of them would clash.
+func (m *Manager) ShiftSeriesFromId(id uint16, delta time.Duration) error {
+	return m.ShiftSeriesFromIdAs(SystemCaller, id, delta)
+}
+
+// ShiftSeriesFromIdAs is ShiftSeriesFromId on behalf of c, returning ErrForbidden if c may not change the series.
+func (m *Manager) ShiftSeriesFromIdAs(c Caller, id uint16, delta time.Duration) error {
+	m.Lock()
+	defer m.Unlock()
+
+	f, err := m.authorizeSeries(c, id)
+	if err != nil {
+		return err
+	}
+
+	original, originalOccurrences, _ := f.getSeries(id)
+	if err := f.ShiftSeries(id, delta); err != nil {
+		slog.Error("Failed to update series!", "SeriesId", id, "Delta", delta)
+		m.monitor.Update(f.Name, fmt.Sprintf("Failed to update recurring booking %v by %v.", id, delta))
+		return err
+	}
+	updated, occurrences, _ := f.getSeries(id)
+	if err := m.record(
+		Mutation{Type: MutationSeriesUpdate, Facility: f.Name, Series: &updated, Bookings: occurrences},
+		func() { f.restoreSeries(original, originalOccurrences) },
+	); err != nil {
+		return err
+	}
+
+	m.auditOccurrences(c, MutationSeriesUpdate, f.Name, originalOccurrences, occurrences)
+	m.monitor.Update(f.Name, fmt.Sprintf("Updated recurring booking %v by %v", id, delta))
+	m.promoteWaiters(f)
+	return nil
+}
+
+// DeleteSeriesFromId removes the series with the given id and all of its remaining occurrences.
+func (m *Manager) DeleteSeriesFromId(id uint16) error {
+	return m.DeleteSeriesFromIdAs(SystemCaller, id)
+}
+
+// DeleteSeriesFromIdAs is DeleteSeriesFromId on behalf of c, returning ErrForbidden if c may not change the series.
+func (m *Manager) DeleteSeriesFromIdAs(c Caller, id uint16) error {
+	m.Lock()
+	defer m.Unlock()
+
+	f, err := m.authorizeSeries(c, id)
+	if err != nil {
+		return err
+	}
+
+	original, originalOccurrences, _ := f.getSeries(id)
+	f.DeleteSeries(id)
+	if err := m.record(
+		Mutation{Type: MutationSeriesDelete, Facility: f.Name, Series: &Series{Id: id}},
+		func() { f.restoreSeries(original, originalOccurrences) },
+	); err != nil {
+		return err
+	}
+
+	m.auditOccurrences(c, MutationSeriesDelete, f.Name, originalOccurrences, nil)
+	slog.Info("Deleted series", "SeriesId", id)
+	m.monitor.Update(f.Name, fmt.Sprintf("Successfully deleted recurring booking %X from %s.", id, f.Name))
+	m.promoteWaiters(f)
+	return nil
+}
+
+// JoinWaitlist adds b to the waitlist of the facility, assigning it an Id that is not in use by any booking. b is booked
+// with that Id as soon as it no longer clashes, e.g. once a clashing booking is deleted or moved, and watchers of the
+// facility are notified. b is booked right away if it does not clash. Returns the assigned Id, and whether b has been
+// booked.
+func (m *Manager) JoinWaitlist(n FacilityName, b Booking) (uint64, bool, error) {
+	return m.JoinWaitlistAs(SystemCaller, n, b)
+}
+
+// JoinWaitlistAs is JoinWaitlist on behalf of c.
+func (m *Manager) JoinWaitlistAs(c Caller, n FacilityName, b Booking) (uint64, bool, error) {
+	m.Lock()
+	defer m.Unlock()
+
+	f, exists := m.Facilities[n]
+	if !exists {
+		slog.Error("Attempted to join the waitlist of a Facility that does not exists!", "FacilityName", n)
+		return 0, false, errors.New("facility does not exists")
+	}
+
+	id, err := m.unusedBookingId(math.MaxUint64, nil)
+	if err != nil {
+		return 0, false, err
+	}
+	b.Id = id
+
+	if err := f.JoinWaitlist(b); err != nil {
+		slog.Error("Unable to join waitlist", "FacilityName", n, "Booking", b, "err", err)
+		return 0, false, err
+	}
+	if err := m.record(
+		Mutation{Type: MutationWaitlistJoin, Facility: n, Booking: &b, BookingId: id},
+		func() { f.LeaveWaitlist(id) },
+	); err != nil {
+		return 0, false, err
+	}
+	m.bookingIndex[id] = n
+
+	m.audit(c, MutationWaitlistJoin, n, id, nil, bookingState(&b))
+	slog.Info("Joined waitlist", "FacilityName", n, "Booking", b)
+	m.monitor.Update(n, fmt.Sprintf("Booking %X has joined the waitlist of %s with %v", id, n, b))
+	m.promoteWaiters(f)
+	return id, m.facilityOfBooking(id) != nil, nil
+}
+
+// LeaveWaitlistAs removes the waitlisted booking with the given id on behalf of c, returning ErrForbidden if c may not
+// change the booking.
+func (m *Manager) LeaveWaitlistAs(c Caller, id uint64) error {
+	m.Lock()
+	defer m.Unlock()
+
+	f := m.facilityOfWaiter(id)
+	if f == nil {
+		slog.Error("Waitlisted booking with Id not found!", "BookingId", id)
+		return errors.New("waitlisted booking with Id not found")
+	}
+
+	original, _ := f.getWaiter(id)
+	if !c.mayChange(original.Owner) {
+		slog.Error("Caller is not the owner of the waitlisted booking!", "BookingId", id, "Principal", c.Principal)
+		return ErrForbidden
+	}
+
+	f.LeaveWaitlist(id)
+	if err := m.record(
+		Mutation{Type: MutationWaitlistLeave, Facility: f.Name, BookingId: id},
+		func() { f.restoreWaiter(original) },
+	); err != nil {
+		return err
+	}
+
+	m.audit(c, MutationWaitlistLeave, f.Name, id, bookingState(&original), nil)
+	slog.Info("Left waitlist", "BookingId", id)
+	m.monitor.Update(f.Name, fmt.Sprintf("Booking %X has left the waitlist of %s.", id, f.Name))
+	return nil
+}
+
+// promoteWaiters books the waitlisted bookings of f that no longer clash, and notifies watchers of f of each booking
+// made. Must be called with the write lock held, after a change that may have cleared a clash.
+func (m *Manager) promoteWaiters(f *Facility) {
+	for _, b := range f.promoteWaiters() {
+		if err := m.record(
+			Mutation{Type: MutationBookingMake, Facility: f.Name, Booking: &b, BookingId: b.Id},
+			func() {
+				f.DeleteBooking(b.Id)
+				f.restoreWaiter(b)
+			},
+		); err != nil {
+			continue
+		}
+		m.bookingIndex[b.Id] = f.Name
+		m.audit(SystemCaller, MutationBookingMake, f.Name, b.Id, nil, bookingState(&b))
+
+		slog.Info("Made booking from waitlist", "FacilityName", f.Name, "Booking", b)
+		m.monitor.Update(f.Name, fmt.Sprintf("Waitlisted Booking %X has been booked at %s with %v", b.Id, f.Name, b))
+	}
+}
+
+// facilityOfWaiter returns the facility with the waitlisted booking with the given id, nil if there is none.
+// Must be called with the lock held.
+func (m *Manager) facilityOfWaiter(id uint64) *Facility {
+	if f := m.indexedFacility(id); f != nil && f.IsWaiting(id) {
+		return f
+	}
+	return nil
+}
+
+// facilityOfSeries returns the facility holding the series with the given id, nil if there is none.
+// Must be called with the lock held.
+func (m *Manager) facilityOfSeries(id uint16) *Facility {
+	for _, f := range m.Facilities {
+		if f.HasSeries(id) {
+			return f
+		}
+	}
+	return nil
+}
+
+// authorizeSeries returns the facility holding the series with the given id, if c may change the series.
+// Must be called with the lock held.
+func (m *Manager) authorizeSeries(c Caller, id uint16) (*Facility, error) {
+	f := m.facilityOfSeries(id)
+	if f == nil {
+		slog.Error("Series with Id not found!", "SeriesId", id)
+		return nil, errors.New("series with Id not found")
+	}
+
+	if s, _, _ := f.getSeries(id); !c.mayChange(s.Owner) {
+		slog.Error("Caller is not the owner of the series!", "SeriesId", id, "Principal", c.Principal)
+		m.monitor.Update(f.Name, fmt.Sprintf("Rejected change to recurring booking %X by someone other than its owner.", id))
+		return nil, ErrForbidden
+	}
+	return f, nil
+}
+
+// seriesIdInUse must be called with the lock held.
+func (m *Manager) seriesIdInUse(id uint16) bool {
+	return m.facilityOfSeries(id) != nil
+}
+
+// bookingIdAttempts is the number of random Ids drawn before giving up on finding an unused booking Id.
+const bookingIdAttempts = 1 << 16
+
+// unusedBookingId returns a random booking Id no larger than maxId that is neither held nor waitlisted by any facility,
+// nor in reserved, or an error if none could be found. Must be called with the lock held.
+func (m *Manager) unusedBookingId(maxId uint64, reserved []uint64) (uint64, error) {
+	for range bookingIdAttempts {
+		var b Booking
+		BookingWithRandomId()(&b)
+		if maxId < math.MaxUint64 {
+			b.Id %= maxId + 1
+		}
+		if b.Id != 0 && !slices.Contains(reserved, b.Id) && m.facilityOfBooking(b.Id) == nil && m.facilityOfWaiter(b.Id) == nil {
+			return b.Id, nil
+		}
+	}
+	return 0, errors.New("no booking Id is available")
+}
+
+// facilityOfBooking returns the facility holding the booking with the given id, nil if there is none.
+// Must be called with the lock held.
+func (m *Manager) facilityOfBooking(id uint64) *Facility {
+	if f := m.indexedFacility(id); f != nil && f.HasId(id) {
+		return f
+	}
+	return nil
+}
+
+// indexedFacility returns the facility that holds or waitlists the booking with the given id according to the booking
+// index, nil if there is none. Index entries of bookings that have since been removed from their facility are dropped.
+// Must be called with the lock held.
+func (m *Manager) indexedFacility(id uint64) *Facility {
+	n, indexed := m.bookingIndex[id]
+	if !indexed {
+		return nil
+	}
+	if f, exists := m.Facilities[n]; exists && (f.HasId(id) || f.IsWaiting(id)) {
+		return f
+	}
+	delete(m.bookingIndex, id)
+	return nil
+}
+
+// indexBookings adds the bookings held and waitlisted by f to the booking index. Must be called with the lock held.
+func (m *Manager) indexBookings(f *Facility) {
+	f.RLock()
+	defer f.RUnlock()
+	for id := range f.BookingMap {
+		m.bookingIndex[id] = f.Name
+	}
+	for _, w := range f.Waitlist {
+		m.bookingIndex[w.Id] = f.Name
+	}
+}
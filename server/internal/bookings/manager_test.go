@@ -1,13 +1,15 @@
 package bookings
 
 import (
+	"errors"
 	"fmt"
+	"math"
 	"testing"
 	"time"
 )
 
 func TestManager_NewBooking(t *testing.T) {
-	manager := GetManager()
+	manager := NewManager()
 	facilityName := FacilityName("TestManager_NewBooking")
 
 	currentTime := time.Now()
@@ -32,7 +34,7 @@ func TestManager_NewBooking(t *testing.T) {
 }
 
 func TestManager_NewBooking_fail_duplicate(t *testing.T) {
-	manager := GetManager()
+	manager := NewManager()
 	facilityName := FacilityName("TestManager_NewBooking_fail_duplicate")
 
 	currentTime := time.Now()
@@ -61,7 +63,7 @@ func TestManager_NewBooking_fail_duplicate(t *testing.T) {
 }
 
 func TestManager_NewBooking_fail_clashing(t *testing.T) {
-	manager := GetManager()
+	manager := NewManager()
 	facilityName := FacilityName("TestManager_NewBooking_fail_clashing")
 
 	currentTime := time.Now()
@@ -101,7 +103,7 @@ func TestManager_NewBooking_fail_clashing(t *testing.T) {
 
 func TestManager_DeleteBookingFromId(t *testing.T) {
 
-	manager := GetManager()
+	manager := NewManager()
 	facilityName := FacilityName("TestManager_DeleteBookingFromId")
 
 	currentTime := time.Now()
@@ -124,3 +126,176 @@ func TestManager_DeleteBookingFromId(t *testing.T) {
 		t.Error(err)
 	}
 }
+
+func TestManager_ResizeBookingFromId_promotesWaiters(t *testing.T) {
+
+	manager := NewManager()
+	facilityName := FacilityName("TestManager_ResizeBookingFromId_promotesWaiters")
+
+	currentTime := time.Now()
+
+	if err := manager.NewFacility(facilityName); err != nil {
+		t.Fatal(err)
+	}
+
+	b1 := Booking{
+		Id:    1,
+		Start: currentTime.Add(time.Duration(1) * time.Hour),
+		End:   currentTime.Add(time.Duration(3) * time.Hour),
+	}
+	waiter := Booking{
+		Start: currentTime.Add(time.Duration(2) * time.Hour),
+		End:   currentTime.Add(time.Duration(3) * time.Hour),
+	}
+
+	if err := manager.NewBooking(facilityName, b1); err != nil {
+		t.Fatal(err)
+	}
+	waiterId, booked, err := manager.JoinWaitlist(facilityName, waiter)
+	if err != nil || booked {
+		t.Fatalf("Expected booking to be waitlisted, got %v (%v)", booked, err)
+	}
+
+	// Ending earlier frees the time the waiter is waiting for
+	if err := manager.ResizeBookingFromId(b1.Id, time.Time{}, waiter.Start); err != nil {
+		t.Fatal(err)
+	}
+	if _, exists := manager.GetDeepCopyOfRecords()[facilityName].BookingMap[waiterId]; !exists {
+		t.Error("Expected waitlisted booking to be made once the booking ended earlier")
+	}
+
+	entries := queryAudit(t, manager, AuditFilter{BookingId: b1.Id}, 0)
+	if last := entries[len(entries)-1]; last.Type != MutationBookingUpdate || !last.After.End.Equal(waiter.Start) {
+		t.Errorf("Unexpected audit entry: %+v", last)
+	}
+}
+
+func TestManager_NewBooking_fail_duplicateAcrossFacilities(t *testing.T) {
+	manager := NewManager()
+
+	currentTime := time.Now()
+
+	for _, n := range []FacilityName{"A", "B"} {
+		if err := manager.NewFacility(n); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	b1 := Booking{
+		Id:    1,
+		Start: currentTime.Add(time.Duration(1) * time.Hour),
+		End:   currentTime.Add(time.Duration(3) * time.Hour),
+	}
+
+	if err := manager.NewBooking("A", b1); err != nil {
+		t.Fatal(err)
+	}
+	if err := manager.NewBooking("B", b1); err == nil {
+		t.Error("expected booking to fail since its Id is in use by another facility")
+	}
+
+	// The Id can be reused once the booking is gone
+	if err := manager.DeleteBookingFromId(b1.Id); err != nil {
+		t.Fatal(err)
+	}
+	if err := manager.NewBooking("B", b1); err != nil {
+		t.Error(err)
+	}
+}
+
+func TestManager_NewBookingWithUnusedId(t *testing.T) {
+	manager := NewManager()
+	facilityName := FacilityName("TestManager_NewBookingWithUnusedId")
+
+	if err := manager.NewFacility(facilityName, FacilityWithCapacity(200)); err != nil {
+		t.Fatal(err)
+	}
+
+	start := time.Now().Add(time.Hour)
+	b := Booking{Start: start, End: start.Add(time.Hour)}
+
+	ids := make(map[uint64]bool)
+	for i := range 200 {
+		maxId := uint64(math.MaxUint64)
+		if i%2 == 0 {
+			maxId = MaxLegacyBookingId
+		}
+
+		id, err := manager.NewBookingWithUnusedId(facilityName, b, maxId)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if id == 0 || id > maxId || ids[id] {
+			t.Fatalf("Expected unused Id no larger than %d, got %d", maxId, id)
+		}
+		ids[id] = true
+	}
+}
+
+func TestManager_HoldBooking_confirmAndRelease(t *testing.T) {
+	manager := NewManager()
+	facilityName := FacilityName("TestManager_HoldBooking_confirmAndRelease")
+	if err := manager.NewFacility(facilityName); err != nil {
+		t.Fatal(err)
+	}
+
+	start := time.Now().Truncate(time.Hour).Add(time.Duration(24) * time.Hour)
+	confirmed, _, err := manager.HoldBooking(facilityName, Booking{Owner: "alice", Start: start, End: start.Add(time.Hour)}, time.Minute)
+	if err != nil {
+		t.Fatal(err)
+	}
+	released, heldUntil, err := manager.HoldBooking(facilityName, Booking{Start: start.Add(time.Hour), End: start.Add(time.Duration(2) * time.Hour)}, time.Duration(50)*time.Millisecond)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Held bookings take up the facility like any other booking
+	if err := manager.NewBooking(facilityName, Booking{Id: 1, Start: start, End: start.Add(time.Duration(2) * time.Hour)}); err == nil {
+		t.Error("Expected booking clashing with held bookings to be rejected")
+	}
+
+	// Only the owner may confirm a held booking, and only once
+	if err := manager.ConfirmBookingAs(Caller{Principal: "bob"}, confirmed); !errors.Is(err, ErrForbidden) {
+		t.Errorf("E: %v, R: %v", ErrForbidden, err)
+	}
+	if err := manager.ConfirmBookingAs(Caller{Principal: "alice"}, confirmed); err != nil {
+		t.Fatal(err)
+	}
+	if err := manager.ConfirmBookingAs(Caller{Principal: "alice"}, confirmed); err == nil {
+		t.Error("Expected booking that is no longer held to be rejected")
+	}
+
+	// The other held booking is released once it expires
+	time.Sleep(time.Until(heldUntil) + time.Duration(100)*time.Millisecond)
+	f := manager.GetDeepCopyOfRecords()[facilityName]
+	if _, exists := f.BookingMap[released]; exists {
+		t.Errorf("Expected held booking %d to be released", released)
+	}
+	if b, exists := f.BookingMap[confirmed]; !exists || b.IsHeld() {
+		t.Errorf("Expected booking %d to be confirmed, got %v", confirmed, b)
+	}
+	if err := manager.ConfirmBookingAs(SystemCaller, released); err == nil {
+		t.Error("Expected released booking to be rejected")
+	}
+}
+
+func TestManager_Close_stopsReleases(t *testing.T) {
+	manager := NewManager()
+	facilityName := FacilityName("TestManager_Close_stopsReleases")
+	if err := manager.NewFacility(facilityName); err != nil {
+		t.Fatal(err)
+	}
+
+	start := time.Now().Truncate(time.Hour).Add(time.Duration(24) * time.Hour)
+	held, heldUntil, err := manager.HoldBooking(facilityName, Booking{Start: start, End: start.Add(time.Hour)}, time.Duration(50)*time.Millisecond)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Held bookings are no longer released once closed, as their release could not be recorded
+	manager.Close()
+	time.Sleep(time.Until(heldUntil) + time.Duration(100)*time.Millisecond)
+	if _, exists := manager.GetDeepCopyOfRecords()[facilityName].BookingMap[held]; !exists {
+		t.Errorf("Expected held booking %d not to be released after close", held)
+	}
+}
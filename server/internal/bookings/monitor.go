@@ -18,20 +18,13 @@ type MonitorConsumer struct {
 type Monitor struct {
 	sync.Mutex
 	Watchers map[FacilityName][]*MonitorConsumer
+	shutdown bool
 }
 
-var (
-	monitor     *Monitor
-	onceMonitor sync.Once
-)
-
-func GetMonitor() *Monitor {
-	onceMonitor.Do(func() {
-		monitor = &Monitor{
-			Watchers: make(map[FacilityName][]*MonitorConsumer),
-		}
-	})
-	return monitor
+func NewMonitor() *Monitor {
+	return &Monitor{
+		Watchers: make(map[FacilityName][]*MonitorConsumer),
+	}
 }
 
 func (m *Monitor) Reset() {
@@ -58,6 +51,15 @@ func (m *Monitor) Watch(f FacilityName, ttl time.Duration) *MonitorConsumer {
 	}
 
 	m.Lock()
+	if m.shutdown {
+		// Monitor no longer accepts watchers, consumer is terminated immediately
+		m.Unlock()
+		consumer.closed.Do(func() {
+			close(consumer.Channel)
+		})
+		cancel()
+		return consumer
+	}
 	m.Watchers[f] = append(m.Watchers[f], consumer)
 	m.Unlock()
 
@@ -86,8 +88,8 @@ func (m *Monitor) Update(f FacilityName, message string) {
 	m.Lock()
 	defer m.Unlock()
 
-	// Silently exit if no known watches for facility
-	if _, e := m.Watchers[f]; !e {
+	// Silently exit if no known watches for facility, or if the channels of all watchers have been closed by Shutdown
+	if _, e := m.Watchers[f]; !e || m.shutdown {
 		return
 	}
 
@@ -104,8 +106,8 @@ func (m *Monitor) Clear(f FacilityName) {
 	m.Lock()
 	defer m.Unlock()
 
-	// Silently exit if no known watches for facility
-	if _, e := m.Watchers[f]; !e {
+	// Silently exit if no known watches for facility, or if the channels of all watchers have been closed by Shutdown
+	if _, e := m.Watchers[f]; !e || m.shutdown {
 		return
 	}
 
@@ -120,4 +122,32 @@ func (m *Monitor) Clear(f FacilityName) {
 		})
 	}
 
+	// Channels are closed, so that the watchers must no longer receive updates (e.g. of a facility of the same name)
+	delete(m.Watchers, f)
+}
+
+// Shutdown sends a final message to all watchers before terminating them. No new watchers are accepted afterwards.
+func (m *Monitor) Shutdown(message string) {
+	m.Lock()
+	defer m.Unlock()
+
+	slog.Info("Shutting down monitor, terminating all watchers")
+	m.shutdown = true
+
+	for _, w := range m.Watchers {
+		for _, c := range w {
+			c.closed.Do(func() {
+				// Consumer may have already stopped listening if its context is done
+				select {
+				case c.Channel <- message:
+				case <-c.Ctx.Done():
+				}
+				close(c.Channel)
+				c.Cancel()
+			})
+		}
+	}
+
+	// Channels are closed, so that the watchers must no longer receive updates
+	m.Watchers = make(map[FacilityName][]*MonitorConsumer)
 }
@@ -0,0 +1,55 @@
+package bookings
+
+import (
+	"testing"
+	"time"
+)
+
+func TestMonitor_Shutdown(t *testing.T) {
+
+	monitor := NewMonitor()
+	facilityName := FacilityName("TestMonitor_Shutdown")
+
+	consumer := monitor.Watch(facilityName, time.Duration(10)*time.Second)
+
+	go monitor.Shutdown("Server is shutting down.")
+
+	if message := <-consumer.Channel; message != "Server is shutting down." {
+		t.Errorf("Unexpected shutdown message: %s", message)
+	}
+	if _, ok := <-consumer.Channel; ok {
+		t.Error("Expected consumer channel to be closed after shutdown")
+	}
+	if consumer.Ctx.Err() == nil {
+		t.Error("Expected consumer context to be cancelled after shutdown")
+	}
+
+	// Watchers added after shutdown are terminated immediately
+	late := monitor.Watch(facilityName, time.Duration(10)*time.Second)
+	if _, ok := <-late.Channel; ok {
+		t.Error("Expected consumer channel to be closed when watching after shutdown")
+	}
+}
+
+func TestMonitor_UpdateAfterShutdown(t *testing.T) {
+
+	monitor := NewMonitor()
+	facilityName := FacilityName("TestMonitor_UpdateAfterShutdown")
+
+	consumer := monitor.Watch(facilityName, time.Duration(10)*time.Second)
+	go func() {
+		for range consumer.Channel {
+		}
+	}()
+	monitor.Shutdown("Server is shutting down.")
+
+	// Requests still in flight during shutdown must not send to the closed channels of watchers
+	monitor.Update(facilityName, "Update after shutdown")
+	monitor.Clear(facilityName)
+
+	monitor.Lock()
+	defer monitor.Unlock()
+	if n := len(monitor.Watchers[facilityName]); n != 0 {
+		t.Errorf("Expected no watchers after shutdown, got %d", n)
+	}
+}
@@ -0,0 +1,166 @@
+package bookings
+
+import (
+	"errors"
+	"fmt"
+	"math/rand"
+	"slices"
+	"time"
+)
+
+// MaxSeriesOccurrences is the maximum number of occurrences a Series may expand to.
+const MaxSeriesOccurrences = 366
+
+type Frequency uint8
+
+const (
+	FrequencyDaily   Frequency = 0x01
+	FrequencyWeekly  Frequency = 0x02
+	FrequencyMonthly Frequency = 0x03
+)
+
+// Series describes a recurring booking, whose occurrences are held by a Facility as Bookings with the Series' Id as
+// their SeriesId. The first occurrence is from Start to End; occurrences repeat at Frequency until Count occurrences
+// have been made or Until has passed, whichever is first (0 or the zero time for none). Occurrences starting at one of
+// the Exceptions are skipped.
+type Series struct {
+	Id         uint16      `json:"id"`
+	Frequency  Frequency   `json:"frequency"`
+	Start      time.Time   `json:"start"`
+	End        time.Time   `json:"end"`
+	Count      int         `json:"count,omitempty"`
+	Until      time.Time   `json:"until,omitempty"`
+	Exceptions []time.Time `json:"exceptions,omitempty"`
+	Owner      string      `json:"owner,omitempty"` // Principal that made the series, empty if anyone may change it
+}
+
+type SeriesOption func(*Series)
+
+func SeriesWithRandomId() SeriesOption {
+
+	randomUint16 := uint16(rand.Uint32() & 0xFFFF) // Extract lower 16 bits
+
+	return func(s *Series) {
+		s.Id = randomUint16
+	}
+}
+
+func SeriesWithOwner(owner string) SeriesOption {
+	return func(s *Series) {
+		s.Owner = owner
+	}
+}
+
+func SeriesWithFrequency(f Frequency) SeriesOption {
+	return func(s *Series) {
+		s.Frequency = f
+	}
+}
+
+// SeriesWithFirstOccurrence sets the times of the first occurrence of the series.
+func SeriesWithFirstOccurrence(start time.Time, end time.Time) SeriesOption {
+	return func(s *Series) {
+		s.Start = start
+		s.End = end
+	}
+}
+
+func SeriesWithCount(count int) SeriesOption {
+	return func(s *Series) {
+		s.Count = count
+	}
+}
+
+func SeriesWithUntil(until time.Time) SeriesOption {
+	return func(s *Series) {
+		s.Until = until
+	}
+}
+
+// SeriesWithExceptions skips the occurrences starting at any of the given times.
+func SeriesWithExceptions(exceptions ...time.Time) SeriesOption {
+	return func(s *Series) {
+		s.Exceptions = exceptions
+	}
+}
+
+func NewSeries(opts ...SeriesOption) (Series, error) {
+	s := &Series{}
+	for _, o := range opts {
+		o(s)
+	}
+
+	if err := s.validate(); err != nil {
+		return Series{}, err
+	}
+
+	return *s, nil
+}
+
+func (s *Series) validate() error {
+	if s.Id == 0 || s.Start.IsZero() || s.End.IsZero() || !s.Start.Before(s.End) {
+		return errors.New("invalid configuration for Series struct")
+	}
+	if s.Frequency < FrequencyDaily || s.Frequency > FrequencyMonthly {
+		return fmt.Errorf("unknown series frequency %d", s.Frequency)
+	}
+	if s.Count < 0 || (s.Count == 0 && s.Until.IsZero()) {
+		return errors.New("series must end after a number of occurrences or at a date")
+	}
+	if s.Count > MaxSeriesOccurrences {
+		return fmt.Errorf("series must not have more than %d occurrences", MaxSeriesOccurrences)
+	}
+	return nil
+}
+
+// nth returns the start of the nth occurrence of s, counting from 0 and including exceptions. Monthly occurrences
+// are normalised in the same way as time.Time.AddDate, e.g. a series starting on the 31st skips into the next month.
+func (s *Series) nth(n int) time.Time {
+	switch s.Frequency {
+	case FrequencyDaily:
+		return s.Start.AddDate(0, 0, n)
+	case FrequencyWeekly:
+		return s.Start.AddDate(0, 0, 7*n)
+	default:
+		return s.Start.AddDate(0, n, 0)
+	}
+}
+
+// Expand returns the occurrences of s as Bookings without Ids, in chronological order.
+func (s *Series) Expand() ([]Booking, error) {
+	if err := s.validate(); err != nil {
+		return nil, err
+	}
+
+	duration := s.End.Sub(s.Start)
+	var occurrences []Booking
+
+	for n := 0; s.Count == 0 || n < s.Count; n++ {
+		start := s.nth(n)
+		if !s.Until.IsZero() && start.After(s.Until) {
+			break
+		}
+		if n >= MaxSeriesOccurrences {
+			return nil, fmt.Errorf("series must not have more than %d occurrences", MaxSeriesOccurrences)
+		}
+		if slices.ContainsFunc(s.Exceptions, start.Equal) {
+			continue
+		}
+		occurrences = append(occurrences, Booking{SeriesId: s.Id, Owner: s.Owner, Start: start, End: start.Add(duration)})
+	}
+
+	if len(occurrences) == 0 {
+		return nil, errors.New("series has no occurrences")
+	}
+	return occurrences, nil
+}
+
+// DeepCopy returns a new pointer to a Series that is a deep copy of s.
+func (s *Series) DeepCopy() *Series {
+	if s == nil {
+		return nil
+	}
+	c := *s
+	c.Exceptions = slices.Clone(s.Exceptions)
+	return &c
+}
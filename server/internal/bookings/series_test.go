@@ -0,0 +1,195 @@
+package bookings
+
+import (
+	"testing"
+	"time"
+)
+
+// nextTuesday returns 18:00 on the next Tuesday, at least a day from now.
+func nextTuesday() time.Time {
+	now := time.Now()
+	d := time.Date(now.Year(), now.Month(), now.Day()+1, 18, 0, 0, 0, time.Local)
+	for d.Weekday() != time.Tuesday {
+		d = d.AddDate(0, 0, 1)
+	}
+	return d
+}
+
+func newTestSeries(t *testing.T, opts ...SeriesOption) Series {
+	t.Helper()
+
+	start := nextTuesday()
+	s, err := NewSeries(append([]SeriesOption{
+		SeriesWithRandomId(),
+		SeriesWithFrequency(FrequencyWeekly),
+		SeriesWithFirstOccurrence(start, start.Add(time.Duration(2)*time.Hour)),
+	}, opts...)...)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return s
+}
+
+func TestSeries_Expand(t *testing.T) {
+	start := nextTuesday()
+
+	cases := []struct {
+		name     string
+		opts     []SeriesOption
+		expected []time.Time
+	}{
+		{
+			name:     "count",
+			opts:     []SeriesOption{SeriesWithCount(3)},
+			expected: []time.Time{start, start.AddDate(0, 0, 7), start.AddDate(0, 0, 14)},
+		},
+		{
+			name:     "until",
+			opts:     []SeriesOption{SeriesWithUntil(start.AddDate(0, 0, 14))},
+			expected: []time.Time{start, start.AddDate(0, 0, 7), start.AddDate(0, 0, 14)},
+		},
+		{
+			name:     "exceptions",
+			opts:     []SeriesOption{SeriesWithCount(3), SeriesWithExceptions(start.AddDate(0, 0, 7))},
+			expected: []time.Time{start, start.AddDate(0, 0, 14)},
+		},
+		{
+			name:     "daily",
+			opts:     []SeriesOption{SeriesWithFrequency(FrequencyDaily), SeriesWithCount(2)},
+			expected: []time.Time{start, start.AddDate(0, 0, 1)},
+		},
+		{
+			name:     "monthly",
+			opts:     []SeriesOption{SeriesWithFrequency(FrequencyMonthly), SeriesWithCount(2)},
+			expected: []time.Time{start, start.AddDate(0, 1, 0)},
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			s := newTestSeries(t, c.opts...)
+			occurrences, err := s.Expand()
+			if err != nil {
+				t.Fatal(err)
+			}
+			if len(occurrences) != len(c.expected) {
+				t.Fatalf("E: %d occurrences, R: %d", len(c.expected), len(occurrences))
+			}
+			for i, o := range occurrences {
+				if !o.Start.Equal(c.expected[i]) || o.End.Sub(o.Start) != time.Duration(2)*time.Hour || o.SeriesId != s.Id {
+					t.Errorf("E: %v, R: %v", c.expected[i], o)
+				}
+			}
+		})
+	}
+}
+
+func TestSeries_Expand_invalid(t *testing.T) {
+	start := nextTuesday()
+
+	if _, err := NewSeries(SeriesWithRandomId(), SeriesWithFrequency(FrequencyWeekly), SeriesWithFirstOccurrence(start, start.Add(time.Hour))); err == nil {
+		t.Error("Expected series without count or until to be rejected")
+	}
+
+	s := newTestSeries(t, SeriesWithUntil(start.AddDate(2, 0, 0)), SeriesWithFrequency(FrequencyDaily))
+	if _, err := s.Expand(); err == nil {
+		t.Errorf("Expected series with more than %d occurrences to be rejected", MaxSeriesOccurrences)
+	}
+}
+
+func TestManager_NewSeries_clashIsAtomic(t *testing.T) {
+	manager := NewManager()
+	facilityName := FacilityName("TestManager_NewSeries_clashIsAtomic")
+	if err := manager.NewFacility(facilityName); err != nil {
+		t.Fatal(err)
+	}
+
+	// Clashes with the third occurrence only
+	start := nextTuesday().AddDate(0, 0, 14)
+	b, _ := NewBooking(BookingWithRandomId(), BookingWithStartTime(start), BookingWithEndTime(start.Add(time.Hour)))
+	if err := manager.NewBooking(facilityName, b); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := manager.NewSeries(facilityName, newTestSeries(t, SeriesWithCount(4))); err == nil {
+		t.Fatal("Expected series clashing with an existing booking to fail")
+	}
+	if f := manager.GetDeepCopyOfRecords()[facilityName]; len(f.Bookings) != 1 || len(f.Series) != 0 {
+		t.Errorf("Expected no occurrences to be booked, got %d bookings and %d series", len(f.Bookings), len(f.Series))
+	}
+}
+
+func TestManager_Series_occurrenceAndWholeSeries(t *testing.T) {
+	manager := NewManager()
+	facilityName := FacilityName("TestManager_Series_occurrenceAndWholeSeries")
+	if err := manager.NewFacility(facilityName); err != nil {
+		t.Fatal(err)
+	}
+
+	s := newTestSeries(t, SeriesWithCount(3))
+	ids, err := manager.NewSeries(facilityName, s)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(ids) != 3 {
+		t.Fatalf("E: 3 occurrences, R: %d", len(ids))
+	}
+
+	// A single occurrence is shifted and cancelled like any other booking
+	if err := manager.ShiftBookingFromId(ids[0], time.Hour); err != nil {
+		t.Fatal(err)
+	}
+	if err := manager.DeleteBookingFromId(ids[1]); err != nil {
+		t.Fatal(err)
+	}
+
+	// The whole series is shifted by a day, leaving the cancelled occurrence cancelled
+	if err := manager.ShiftSeriesFromId(s.Id, time.Duration(24)*time.Hour); err != nil {
+		t.Fatal(err)
+	}
+	f := manager.GetDeepCopyOfRecords()[facilityName]
+	if len(f.Bookings) != 2 {
+		t.Fatalf("E: 2 occurrences, R: %d", len(f.Bookings))
+	}
+	if expected := s.Start.Add(time.Duration(25) * time.Hour); !f.Bookings[0].Start.Equal(expected) {
+		t.Errorf("E: %v, R: %v", expected, f.Bookings[0].Start)
+	}
+	if expected := s.Start.AddDate(0, 0, 14).Add(time.Duration(24) * time.Hour); !f.Bookings[1].Start.Equal(expected) {
+		t.Errorf("E: %v, R: %v", expected, f.Bookings[1].Start)
+	}
+
+	if err := manager.DeleteSeriesFromId(s.Id); err != nil {
+		t.Fatal(err)
+	}
+	if f := manager.GetDeepCopyOfRecords()[facilityName]; len(f.Bookings) != 0 || len(f.Series) != 0 {
+		t.Errorf("Expected series to be deleted, got %d bookings and %d series", len(f.Bookings), len(f.Series))
+	}
+}
+
+func TestManager_ShiftSeriesFromId_clashIsAtomic(t *testing.T) {
+	manager := NewManager()
+	facilityName := FacilityName("TestManager_ShiftSeriesFromId_clashIsAtomic")
+	if err := manager.NewFacility(facilityName); err != nil {
+		t.Fatal(err)
+	}
+
+	s := newTestSeries(t, SeriesWithCount(3))
+	if _, err := manager.NewSeries(facilityName, s); err != nil {
+		t.Fatal(err)
+	}
+
+	// Clashes with the last occurrence once shifted
+	start := s.Start.AddDate(0, 0, 14).Add(time.Duration(3) * time.Hour)
+	b, _ := NewBooking(BookingWithRandomId(), BookingWithStartTime(start), BookingWithEndTime(start.Add(time.Hour)))
+	if err := manager.NewBooking(facilityName, b); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := manager.ShiftSeriesFromId(s.Id, time.Duration(3)*time.Hour); err == nil {
+		t.Fatal("Expected shift clashing with an existing booking to fail")
+	}
+	f := manager.GetDeepCopyOfRecords()[facilityName]
+	if len(f.Bookings) != 4 || !f.Bookings[0].Start.Equal(s.Start) {
+		t.Errorf("Expected occurrences to be unchanged, got %v", f.Bookings)
+	}
+}
@@ -0,0 +1,88 @@
+package bookings
+
+import (
+	"errors"
+	"slices"
+	"time"
+)
+
+// JoinWaitlist adds b to the end of the waitlist of the facility, to be booked once it no longer clashes (see
+// promoteWaiters). b must already have an Id, which it is booked with.
+func (f *Facility) JoinWaitlist(b Booking) error {
+	f.Lock()
+	defer f.Unlock()
+	f.clean()
+
+	if !b.Start.Before(b.End) {
+		return errors.New("booking must end after it starts")
+	}
+	if b.Start.Before(time.Now()) {
+		return errors.New("waitlisted booking must not start in the past")
+	}
+	if err := f.checkOpen(&b); err != nil {
+		return err
+	}
+	if slices.ContainsFunc(f.Waitlist, func(w *Booking) bool { return w.Id == b.Id }) {
+		return errors.New("booking is already waitlisted")
+	}
+
+	f.Waitlist = append(f.Waitlist, &b)
+	return nil
+}
+
+// LeaveWaitlist removes the waitlisted booking with the given id, returns false if there is none.
+func (f *Facility) LeaveWaitlist(id uint64) bool {
+	f.Lock()
+	defer f.Unlock()
+
+	n := len(f.Waitlist)
+	f.Waitlist = slices.DeleteFunc(f.Waitlist, func(w *Booking) bool { return w.Id == id })
+	return len(f.Waitlist) != n
+}
+
+func (f *Facility) IsWaiting(id uint64) bool {
+	f.RLock()
+	defer f.RUnlock()
+
+	return slices.ContainsFunc(f.Waitlist, func(w *Booking) bool { return w.Id == id })
+}
+
+// getWaiter returns a copy of the waitlisted booking with the given id.
+func (f *Facility) getWaiter(id uint64) (Booking, bool) {
+	f.RLock()
+	defer f.RUnlock()
+
+	if i := slices.IndexFunc(f.Waitlist, func(w *Booking) bool { return w.Id == id }); i >= 0 {
+		return *f.Waitlist[i], true
+	}
+	return Booking{}, false
+}
+
+// restoreWaiter puts b at the end of the waitlist. Like restoreBooking, b is not checked, as it is only used to
+// restore waitlisted bookings that were previously accepted.
+func (f *Facility) restoreWaiter(b Booking) {
+	f.Lock()
+	defer f.Unlock()
+
+	f.Waitlist = slices.DeleteFunc(f.Waitlist, func(w *Booking) bool { return w.Id == b.Id })
+	f.Waitlist = append(f.Waitlist, &b)
+}
+
+// promoteWaiters books every waitlisted booking that no longer clashes, in the order they joined the waitlist, and
+// returns the bookings made. Waitlisted bookings that have started without being booked are dropped.
+func (f *Facility) promoteWaiters() []Booking {
+	f.Lock()
+	defer f.Unlock()
+	f.clean()
+
+	var made []Booking
+	f.Waitlist = slices.DeleteFunc(f.Waitlist, func(w *Booking) bool {
+		b := *w
+		if f.checkOpen(&b) != nil || !f.insertBooking(&b) {
+			return false
+		}
+		made = append(made, b)
+		return true
+	})
+	return made
+}
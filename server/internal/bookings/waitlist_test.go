@@ -0,0 +1,147 @@
+package bookings
+
+import (
+	"errors"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestManager_JoinWaitlist_promotedInOrder(t *testing.T) {
+	manager := NewManager()
+	facilityName := FacilityName("TestManager_JoinWaitlist_promotedInOrder")
+	if err := manager.NewFacility(facilityName); err != nil {
+		t.Fatal(err)
+	}
+
+	start := time.Now().Truncate(time.Hour).Add(time.Duration(24) * time.Hour)
+	if err := manager.NewBooking(facilityName, Booking{Id: 1, Start: start, End: start.Add(time.Duration(3) * time.Hour)}); err != nil {
+		t.Fatal(err)
+	}
+
+	// Both waitlisted bookings clash with the booking, the first one also clashes with the second one
+	first, booked, err := manager.JoinWaitlist(facilityName, Booking{Owner: "alice", Start: start, End: start.Add(time.Duration(3) * time.Hour)})
+	if err != nil || booked {
+		t.Fatalf("Expected booking to be waitlisted, got %v (%v)", booked, err)
+	}
+	second, booked, err := manager.JoinWaitlist(facilityName, Booking{Start: start.Add(time.Duration(2) * time.Hour), End: start.Add(time.Duration(3) * time.Hour)})
+	if err != nil || booked {
+		t.Fatalf("Expected booking to be waitlisted, got %v (%v)", booked, err)
+	}
+
+	// Waitlisted bookings that do not clash are booked right away
+	if _, booked, err := manager.JoinWaitlist(facilityName, Booking{Start: start.Add(time.Duration(5) * time.Hour), End: start.Add(time.Duration(6) * time.Hour)}); err != nil || !booked {
+		t.Errorf("Expected booking to be booked right away, got %v (%v)", booked, err)
+	}
+
+	// Watchers are notified once the first waitlisted booking has been booked
+	consumer := manager.Monitor().Watch(facilityName, time.Duration(10)*time.Second)
+	defer consumer.Cancel()
+	notified := make(chan bool, 1)
+	go func() {
+		found := false
+		for message := range consumer.Channel {
+			found = found || strings.Contains(message, "Waitlisted Booking")
+		}
+		notified <- found
+	}()
+
+	if err := manager.DeleteBookingFromId(1); err != nil {
+		t.Fatal(err)
+	}
+	consumer.Cancel()
+	if !<-notified {
+		t.Error("Expected watchers to be notified of the waitlisted booking")
+	}
+
+	f := manager.GetDeepCopyOfRecords()[facilityName]
+	if _, exists := f.BookingMap[first]; !exists || f.BookingMap[first].Owner != "alice" {
+		t.Errorf("Expected waitlisted booking %d to be booked for its owner, got %v", first, f.BookingMap[first])
+	}
+	if len(f.Waitlist) != 1 || f.Waitlist[0].Id != second {
+		t.Fatalf("Expected booking %d to keep waiting, got %v", second, f.Waitlist)
+	}
+
+	// Waitlisted bookings leave the waitlist on behalf of their owner
+	if err := manager.LeaveWaitlistAs(Caller{}, first); err == nil {
+		t.Error("Expected booking that is no longer waitlisted to be rejected")
+	}
+	if err := manager.LeaveWaitlistAs(Caller{}, second); err != nil {
+		t.Error(err)
+	}
+}
+
+func TestManager_JoinWaitlist_invalid(t *testing.T) {
+	manager := NewManager()
+	facilityName := FacilityName("TestManager_JoinWaitlist_invalid")
+
+	hours := make([]OpeningHours, 7)
+	for day := range hours {
+		hours[day] = OpeningHours{Open: time.Duration(8) * time.Hour, Close: time.Duration(18) * time.Hour}
+	}
+	if err := manager.NewFacility(facilityName, FacilityWithInfo(FacilityInfo{Hours: hours})); err != nil {
+		t.Fatal(err)
+	}
+
+	now := time.Now()
+	tmr := time.Date(now.Year(), now.Month(), now.Day()+1, 0, 0, 0, 0, time.Local)
+	for _, b := range []Booking{
+		{Start: tmr.Add(time.Duration(20) * time.Hour), End: tmr.Add(time.Duration(21) * time.Hour)}, // Closed
+		{Start: now.Add(-time.Hour), End: now.Add(time.Hour)},                                        // Started
+		{Start: tmr.Add(time.Duration(10) * time.Hour), End: tmr.Add(time.Duration(9) * time.Hour)},  // Ends before it starts
+	} {
+		if _, _, err := manager.JoinWaitlist(facilityName, b); err == nil {
+			t.Errorf("Expected %v to be rejected", b)
+		}
+	}
+
+	// Only the owner may leave the waitlist
+	if err := manager.NewBooking(facilityName, Booking{Id: 1, Start: tmr.Add(time.Duration(10) * time.Hour), End: tmr.Add(time.Duration(11) * time.Hour)}); err != nil {
+		t.Fatal(err)
+	}
+	id, _, err := manager.JoinWaitlist(facilityName, Booking{Owner: "alice", Start: tmr.Add(time.Duration(10) * time.Hour), End: tmr.Add(time.Duration(11) * time.Hour)})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := manager.LeaveWaitlistAs(Caller{Principal: "bob"}, id); !errors.Is(err, ErrForbidden) {
+		t.Errorf("E: %v, R: %v", ErrForbidden, err)
+	}
+	if err := manager.LeaveWaitlistAs(Caller{Principal: "alice"}, id); err != nil {
+		t.Error(err)
+	}
+}
+
+func TestManager_DeleteFacility_waitlisted(t *testing.T) {
+	manager := NewManager()
+	facilityName := FacilityName("TestManager_DeleteFacility_waitlisted")
+	if err := manager.NewFacility(facilityName); err != nil {
+		t.Fatal(err)
+	}
+
+	start := time.Now().Truncate(time.Hour).Add(time.Duration(24) * time.Hour)
+	if err := manager.NewBooking(facilityName, Booking{Id: 1, Start: start, End: start.Add(time.Hour)}); err != nil {
+		t.Fatal(err)
+	}
+	id, booked, err := manager.JoinWaitlist(facilityName, Booking{Start: start, End: start.Add(time.Hour)})
+	if err != nil || booked {
+		t.Fatalf("Expected booking to be waitlisted, got %v (%v)", booked, err)
+	}
+
+	// Closing the facility keeps the waitlisted booking waiting once the clashing booking is deleted
+	if err := manager.UpdateFacility(facilityName, 1, FacilityInfo{Hours: make([]OpeningHours, 7)}); err != nil {
+		t.Fatal(err)
+	}
+	if err := manager.DeleteBookingFromId(1); err != nil {
+		t.Fatal(err)
+	}
+	if err := manager.DeleteFacility(facilityName); err == nil {
+		t.Fatal("Expected facility with waitlisted bookings to be rejected")
+	}
+
+	if err := manager.LeaveWaitlistAs(SystemCaller, id); err != nil {
+		t.Fatal(err)
+	}
+	if err := manager.DeleteFacility(facilityName); err != nil {
+		t.Errorf("Expected facility to be deleted once the waitlist is empty, got %v", err)
+	}
+}
@@ -2,11 +2,10 @@ package chance
 
 import (
 	"math/rand"
-	"server/internal/vars"
 )
 
-func DropPacket() bool {
-	dropRate := vars.GetStaticEnv().PacketDropRate
+// DropPacket decides if a packet should be dropped to simulate an unreliable network, given the dropRate [0,1).
+func DropPacket(dropRate float32) bool {
 	if rand.Float32() < dropRate {
 		return true
 	}
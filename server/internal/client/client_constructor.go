@@ -4,11 +4,13 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"log/slog"
 	"net"
 	"reflect"
 	"server/internal/protocol/proto_defs"
 	"server/internal/rpc/response"
 	"server/tests"
+	"strconv"
 	"time"
 )
 
@@ -30,6 +32,16 @@ func WithTargetAsIpV4(host string, port int) NewClientOpt {
 	}
 }
 
+func WithTargetAsIpV6(host string, port int) NewClientOpt {
+	return func(c *Client) {
+		addr, err := net.ResolveUDPAddr("udp6", net.JoinHostPort(host, strconv.Itoa(port)))
+		if err != nil {
+			return
+		}
+		c.targetServer = addr
+	}
+}
+
 func WithClientName(name string) NewClientOpt {
 	return func(c *Client) {
 		if name == "" {
@@ -40,6 +52,13 @@ func WithClientName(name string) NewClientOpt {
 	}
 }
 
+// WithLogger replaces the client's logger, e.g. to silence clients used for generating load.
+func WithLogger(l *slog.Logger) NewClientOpt {
+	return func(c *Client) {
+		c.logger = l
+	}
+}
+
 func WithTimeout(t time.Duration) NewClientOpt {
 	return func(c *Client) {
 		c.Ctx, c.Cancel = context.WithTimeout(c.Ctx, t)
@@ -11,14 +11,18 @@ import (
 )
 
 func (c *Client) createConn() error {
-	// Resolve a UDP address with port 0 (OS assigns a free port)
-	addr, err := net.ResolveUDPAddr("udp", "0.0.0.0:0")
+	// Resolve a UDP address with port 0 (OS assigns a free port), of the same family as the target server
+	network, address := "udp4", "0.0.0.0:0"
+	if c.targetServer.IP.To4() == nil {
+		network, address = "udp6", "[::]:0"
+	}
+	addr, err := net.ResolveUDPAddr(network, address)
 	if err != nil {
 		return fmt.Errorf("failed to resolve UDP address: %w", err)
 	}
 
 	// Listen on a random available port
-	conn, err := net.ListenUDP("udp", addr)
+	conn, err := net.ListenUDP(network, addr)
 	if err != nil {
 		return fmt.Errorf("failed to listen on a UDP port: %w", err)
 	}
@@ -46,8 +50,12 @@ func (c *Client) receivePacketLoop() {
 			res := [proto_defs.PacketSizeLimit]byte{}
 			copy(res[:n], buffer[:n])
 
-			// Send bytes to chan
-			c.responseBytes <- res
+			// Send bytes to chan, unless the client is closed while the chan is full
+			select {
+			case c.responseBytes <- res:
+			case <-c.Ctx.Done():
+				return
+			}
 		}
 	}
 }
@@ -140,9 +148,6 @@ func (c *Client) handleIncomingPacket() {
 			default: // Unrecognised message types + requests
 				c.logger.Warn("Unsupported packet type", "type", p.Header.MessageType)
 			}
-
-		default:
-			continue
 		}
 	}
 
@@ -94,9 +94,6 @@ LOOP:
 				}
 			}
 			s.mu.Unlock()
-
-		default:
-			continue
 		}
 	}
 }
@@ -135,9 +132,6 @@ LOOP:
 				}
 			}
 			s.mu.Unlock()
-
-		default:
-			continue
 		}
 	}
 }
@@ -6,15 +6,13 @@ import (
 	"net"
 	"server/internal/chance"
 	"server/internal/monitor"
-	"server/internal/network"
 	"server/internal/pools"
 	"server/internal/protocol"
 	"server/internal/protocol/constructors"
 	"server/internal/protocol/proto_defs"
-	"server/internal/rpc/response"
 )
 
-func IncomingPacket(
+func (h *Handler) IncomingPacket(
 	conn *net.UDPConn,
 	addr *net.UDPAddr,
 	nBytes int,
@@ -32,7 +30,7 @@ func IncomingPacket(
 	monitor.MarkPacketIn()
 
 	// Drop Chance
-	if chance.DropPacket() {
+	if chance.DropPacket(h.env.PacketDropRate) {
 		monitor.MarkPacketInDropped()
 		slog.Warn(fmt.Sprintf("[IN:DROP] %d from %s", nBytes, addr.String()))
 		return
@@ -53,13 +51,19 @@ func IncomingPacket(
 		return
 	}
 
+	// Drop new requests without acknowledging them when draining, so that the client continues retrying
+	if packet.Header.MessageType == proto_defs.MessageTypeRequest && h.IsDraining() {
+		slog.Warn("[IN:DRAINING] Server is shutting down, dropping request packet", "MessageId", packet.Header.MessageId)
+		return
+	}
+
 	// Handle acknowledgements
 	if packet.Header.Flags.AckRequired() {
 		ackPacket, err := constructors.NewAck(packet.Header.MessageId, packet.Header.PacketNumber)
 		if err != nil {
 			slog.Error("[IN:ACK] Unable to create ack packet to be sent")
 		}
-		if err := network.SendPacket(conn, addr, ackPacket); err != nil {
+		if err := h.sender.SendPacket(conn, addr, ackPacket); err != nil {
 			slog.Error("Unable to send ack packet", "AckPacket", ackPacket)
 		}
 		slog.Info("[IN:ACK] Packet acknowledged")
@@ -74,18 +78,18 @@ func IncomingPacket(
 		}
 		ident := ackPayload.ToPacketIdent()
 		// Packet has been confirmed to be received
-		network.GetSendHistoryInstance().Remove(*ident)
+		h.sender.Remove(*ident)
 		// Once first ack of res packet is received, the response is removed
-		response.GetResponseHistoryInstance().RemoveResponse(ident.MessageId)
+		h.responses.RemoveResponse(ident.MessageId)
 		break
 	case proto_defs.MessageTypeRequestResend:
 		slog.Info("[IN:SORT] Requesting for packet resend")
-		RequestResendPacket(conn, addr, &packet)
+		h.RequestResendPacket(conn, addr, &packet)
 		break
 	default:
 		// Pass off to message assembly
 		slog.Info("[IN:HANDOFF] Packet validated and acknowledged, handing off to assembler")
-		AssembleMessageFromPacket(conn, addr, &packet)
+		h.assembler.AssembleMessageFromPacket(conn, addr, &packet)
 		break
 	}
 
@@ -2,6 +2,7 @@ package handle
 
 import (
 	"bytes"
+	"context"
 	"errors"
 	"log/slog"
 	"math/bits"
@@ -65,7 +66,7 @@ func (m *MessagePartial) GetMissingPackets() []uint8 {
 	return missing
 }
 
-func (m *MessagePartial) RequestMissingPackets() {
+func (m *MessagePartial) RequestMissingPackets(sender *network.SendHistory) {
 	m.RLock()
 	defer m.RUnlock()
 
@@ -91,7 +92,7 @@ func (m *MessagePartial) RequestMissingPackets() {
 			slog.Error("Unable to create Request Resend packet", "err", err)
 			continue
 		}
-		if err := network.SendPacket(m.Conn, m.Addr, p); err != nil {
+		if err := sender.SendPacket(m.Conn, m.Addr, p); err != nil {
 			slog.Error("Unable to send Request Resend packet", "err", err)
 		}
 	}
@@ -163,33 +164,49 @@ func (m *MessagePartial) UpsertPacket(p *protocol.Packet) error {
 	return nil
 }
 
+// MessageHandoff is called with each message that has been fully assembled.
+type MessageHandoff func(c *net.UDPConn, a *net.UDPAddr, m *protocol.Message)
+
 type MessageAssembler struct {
 	sync.RWMutex
 	Incomplete map[protocol.PacketIdent]*MessagePartial
 	Complete   map[protocol.PacketIdent]struct{}
+
+	env       *vars.StaticEnvStruct
+	sender    *network.SendHistory
+	responses *response.History
+	handoff   MessageHandoff
 }
 
-var onceMessageAssembler sync.Once
-var messageAssembler *MessageAssembler
+func NewMessageAssembler(
+	env *vars.StaticEnvStruct,
+	sender *network.SendHistory,
+	responses *response.History,
+	handoff MessageHandoff,
+) *MessageAssembler {
+	return &MessageAssembler{
+		Incomplete: make(map[protocol.PacketIdent]*MessagePartial),
+		Complete:   make(map[protocol.PacketIdent]struct{}),
+		env:        env,
+		sender:     sender,
+		responses:  responses,
+		handoff:    handoff,
+	}
+}
 
-func GetMessageAssembler() *MessageAssembler {
-	onceMessageAssembler.Do(func() {
-		messageAssembler = &MessageAssembler{
-			Incomplete: make(map[protocol.PacketIdent]*MessagePartial),
-			Complete:   make(map[protocol.PacketIdent]struct{}),
-		}
+// Run requests missing packets on existing incomplete messages at an interval, until ctx is cancelled.
+func (m *MessageAssembler) Run(ctx context.Context) {
+	t := time.NewTicker(time.Duration(m.env.MessageAssemblerIntervals) * time.Millisecond)
+	defer t.Stop()
 
-		// Create an interval to request missing packets on existing incomplete packets
-		t := time.NewTicker(time.Duration(vars.GetStaticEnv().MessageAssemblerIntervals) * time.Millisecond)
-		go func() {
-			defer t.Stop()
-			for range t.C {
-				messageAssembler.RequestMissingPackets()
-			}
-		}()
-	})
-
-	return messageAssembler
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-t.C:
+			m.RequestMissingPackets()
+		}
+	}
 }
 
 func (m *MessageAssembler) RequestMissingPackets() {
@@ -202,12 +219,22 @@ func (m *MessageAssembler) RequestMissingPackets() {
 
 	slog.Debug("Requesting missing packets in all message partials")
 	for _, partial := range m.Incomplete {
-		go partial.RequestMissingPackets()
+		go partial.RequestMissingPackets(m.sender)
 	}
 	slog.Debug("Requesting missing packets done")
 }
 
+// AssembleMessageFromPacket adds p to its message, and hands off the message once it is complete.
+// The handoff is executed on the calling GoRoutine.
 func (m *MessageAssembler) AssembleMessageFromPacket(c *net.UDPConn, a *net.UDPAddr, p *protocol.Packet) {
+	if message := m.assemble(c, a, p); message != nil {
+		// Handoff message to be processed, outside the lock so that other packets can continue to be assembled
+		m.handoff(c, a, message)
+	}
+}
+
+// assemble adds p to its message, returns the message if it has been completed by p.
+func (m *MessageAssembler) assemble(c *net.UDPConn, a *net.UDPAddr, p *protocol.Packet) *protocol.Message {
 	m.Lock()
 	defer m.Unlock()
 
@@ -215,31 +242,31 @@ func (m *MessageAssembler) AssembleMessageFromPacket(c *net.UDPConn, a *net.UDPA
 	ident := protocol.ExtractIdentFromPacket(p)
 
 	// Check if the message has already been completed (prevents duplicate messages)
-	if _, exists := m.Complete[ident]; exists && vars.GetStaticEnv().EnableDuplicateFiltering {
+	if _, exists := m.Complete[ident]; exists && m.env.EnableDuplicateFiltering {
 		slog.Info("Message has already been assembled and handed off, resending cached response", "MessageId", p.Header.MessageId)
-		res, err := response.GetResponseHistoryInstance().GetResponse(p.Header.MessageId)
+		res, err := m.responses.GetResponse(p.Header.MessageId)
 		if err != nil {
 			slog.Error("Unable to resend cached response", "err", err)
 		}
 		if res == nil {
 			slog.Warn("Response has yet to be completed, dropping request packet")
-			return
+			return nil
 		}
-		response.SendResponse(c, a, res)
-		return
+		m.responses.SendResponse(c, a, res)
+		return nil
 	}
 
 	// Add packet to MessagePartial
 	if mp, exists := m.Incomplete[ident]; exists {
 		slog.Info("Upsert packet that already exists", "PacketIdent", ident)
 		if err := mp.UpsertPacket(p); err != nil {
-			return
+			return nil
 		}
 	} else {
 		slog.Info("Setting new partial", "PacketIdent", ident)
 		m.Incomplete[ident] = NewMessagePartial(c, a, int(p.Header.TotalPackets))
 		if err := m.Incomplete[ident].UpsertPacket(p); err != nil {
-			return
+			return nil
 		}
 	}
 
@@ -250,12 +277,8 @@ func (m *MessageAssembler) AssembleMessageFromPacket(c *net.UDPConn, a *net.UDPA
 		delete(m.Incomplete, ident)
 		m.Complete[ident] = struct{}{}
 
-		// Handoff message to be processed
-		go IncomingMessage(c, a, message)
+		return message
 	}
 
-}
-
-func AssembleMessageFromPacket(c *net.UDPConn, a *net.UDPAddr, p *protocol.Packet) {
-	GetMessageAssembler().AssembleMessageFromPacket(c, a, p)
+	return nil
 }
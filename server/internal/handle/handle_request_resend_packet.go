@@ -3,11 +3,10 @@ package handle
 import (
 	"log/slog"
 	"net"
-	"server/internal/network"
 	"server/internal/protocol"
 )
 
-func RequestResendPacket(c *net.UDPConn, a *net.UDPAddr, m *protocol.Packet) {
+func (h *Handler) RequestResendPacket(c *net.UDPConn, a *net.UDPAddr, m *protocol.Packet) {
 
 	var p protocol.AckResendPayload
 	if err := p.UnmarshalBinary(m.Payload); err != nil {
@@ -15,13 +14,12 @@ func RequestResendPacket(c *net.UDPConn, a *net.UDPAddr, m *protocol.Packet) {
 		return
 	}
 
-	h := network.GetSendHistoryInstance()
-	packet, err := h.Get(*p.ToPacketIdent())
+	packet, err := h.sender.Get(*p.ToPacketIdent())
 	if err != nil {
 		slog.Error("Unable to retrieve corresponding packet from packet history", "err", err)
 		return
 	}
-	err = network.SendPacket(c, a, packet)
+	err = h.sender.SendPacket(c, a, packet)
 	if err != nil {
 		slog.Error("Unable to send packet", "err", err)
 		return
@@ -0,0 +1,48 @@
+package handle_requests
+
+import (
+	"log/slog"
+	"net"
+	"server/internal/bookings"
+	"server/internal/protocol"
+	"server/internal/rpc/request"
+	"server/internal/rpc/response"
+)
+
+// auditQueryLimit is the number of audit entries retrieved for a query, more than fit within a single response packet.
+const auditQueryLimit = 64
+
+// AuditQuery responds with the entries of the audit log selected by the request, oldest first. If an admin principal
+// is configured, only the admin may query the audit log.
+func (h *Handler) AuditQuery(c *net.UDPConn, a *net.UDPAddr, message *protocol.Message) {
+
+	// Get the caller, who may have supplied a principal with the payload
+	caller, payload, err := h.caller(a, message)
+	if err != nil {
+		slog.Error("Unable to determine caller", "err", err)
+		h.responses.SendResponse(c, a, response.NewErrorResponse(message.Header.MessageId, response.StatusBadRequest, err.Error()))
+		return
+	}
+	if h.env.AdminPrincipal != "" && !caller.Admin {
+		slog.Error("Caller may not query the audit log", "Principal", caller.Principal)
+		h.responses.SendResponse(c, a, response.NewErrorResponse(message.Header.MessageId, response.StatusForbidden, bookings.ErrForbidden.Error()))
+		return
+	}
+
+	// Get message payload unmarshalled
+	var p request.AuditQueryPayload
+	if err := p.UnmarshalBinary(payload); err != nil {
+		slog.Error("Unable to unmarshall AuditQueryPayload", "err", err)
+		h.responses.SendResponse(c, a, response.NewErrorResponse(message.Header.MessageId, response.StatusBadRequest, err.Error()))
+		return
+	}
+
+	entries, err := h.manager.QueryAudit(p.Filter, auditQueryLimit)
+	if err != nil {
+		slog.Error("Unable to query audit log", "Filter", p.Filter, "err", err)
+		h.responses.SendResponse(c, a, response.NewErrorResponse(message.Header.MessageId, response.StatusInternalServerError, err.Error()))
+		return
+	}
+	slog.Info("Successfully queried audit log", "Filter", p.Filter, "Found", len(entries))
+	h.responses.SendResponse(c, a, response.NewAuditResponse(message.Header.MessageId, entries, maxAvailabilityBytes))
+}
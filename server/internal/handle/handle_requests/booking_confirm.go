@@ -0,0 +1,40 @@
+package handle_requests
+
+import (
+	"log/slog"
+	"net"
+	"server/internal/protocol"
+	"server/internal/rpc/request"
+	"server/internal/rpc/response"
+)
+
+func (h *Handler) BookingConfirm(c *net.UDPConn, a *net.UDPAddr, message *protocol.Message) {
+
+	// Get the caller, who may have supplied a principal with the payload
+	caller, payload, err := h.caller(a, message)
+	if err != nil {
+		slog.Error("Unable to determine caller", "err", err)
+		h.responses.SendResponse(c, a, response.NewErrorResponse(message.Header.MessageId, response.StatusBadRequest, err.Error()))
+		return
+	}
+
+	// Get message payload unmarshalled
+	var p request.BookingDeletePayloadV3
+	if err := p.UnmarshalBinary(payload); err != nil {
+		slog.Error("Unable to unmarshall BookingDeletePayloadV3", "err", err)
+		h.responses.SendResponse(c, a, response.NewErrorResponse(message.Header.MessageId, response.StatusBadRequest, err.Error()))
+		return
+	}
+
+	// Confirm booking
+	err = h.manager.ConfirmBookingAs(caller, p.Id)
+	if err != nil {
+		slog.Error("Unable to confirm booking", "err", err)
+		h.responses.SendResponse(c, a, response.NewErrorResponse(message.Header.MessageId, errorStatus(err), err.Error()))
+		return
+	}
+
+	// Confirmation ok
+	slog.Info("Successfully confirmed booking", "BookingId", p.Id)
+	h.responses.SendResponse(c, a, response.NewOkResponse(message.Header.MessageId))
+}
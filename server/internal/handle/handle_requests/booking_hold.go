@@ -0,0 +1,49 @@
+package handle_requests
+
+import (
+	"log/slog"
+	"net"
+	"server/internal/protocol"
+	"server/internal/rpc/request"
+	"server/internal/rpc/response"
+	"time"
+)
+
+func (h *Handler) BookingHold(c *net.UDPConn, a *net.UDPAddr, message *protocol.Message) {
+
+	// Get the caller, who may have supplied a principal with the payload
+	caller, payload, err := h.caller(a, message)
+	if err != nil {
+		slog.Error("Unable to determine caller", "err", err)
+		h.responses.SendResponse(c, a, response.NewErrorResponse(message.Header.MessageId, response.StatusBadRequest, err.Error()))
+		return
+	}
+
+	// Get message payload unmarshalled
+	var p request.BookingMakePayloadV2
+	if err := p.UnmarshalBinary(payload); err != nil {
+		slog.Error("Unable to unmarshall BookingMakePayloadV2", "err", err)
+		h.responses.SendResponse(c, a, response.NewErrorResponse(message.Header.MessageId, response.StatusBadRequest, err.Error()))
+		return
+	}
+
+	booking, err := p.GetBooking(h.slot())
+	if err != nil {
+		slog.Error("Unable to create instance of booking", "err", err)
+		h.responses.SendResponse(c, a, response.NewErrorResponse(message.Header.MessageId, response.StatusBadRequest, err.Error()))
+		return
+	}
+
+	booking.Owner = caller.Principal
+
+	// Held bookings always have a 64 bit Id
+	id, heldUntil, err := h.manager.HoldBookingAs(caller, p.Name, booking, time.Duration(h.env.HoldTTL)*time.Millisecond)
+	if err != nil {
+		slog.Error("Unable to hold booking", "err", err)
+		h.responses.SendResponse(c, a, response.NewErrorResponse(message.Header.MessageId, response.StatusBadRequest, err.Error()))
+		return
+	}
+
+	slog.Info("Successfully held booking", "Booking", p, "BookingId", id, "HeldUntil", heldUntil)
+	h.responses.SendResponse(c, a, response.NewHoldResponse(message.Header.MessageId, id, heldUntil))
+}
@@ -0,0 +1,54 @@
+package handle_requests
+
+import (
+	"log/slog"
+	"net"
+	"server/internal/protocol"
+	"server/internal/rpc/request"
+	"server/internal/rpc/response"
+)
+
+// maxBookingList is the largest number of bookings listed in a single response.
+const maxBookingList = 0xFF
+
+// BookingList responds with a page of the bookings of a facility within a time window, as many as fit within a single
+// response packet. Owners are only listed for bookings the caller may change. Clients list the following page by
+// requesting the bookings after the cursor of the last booking listed.
+func (h *Handler) BookingList(c *net.UDPConn, a *net.UDPAddr, message *protocol.Message) {
+
+	// Get the caller, who may have supplied a principal with the payload
+	caller, payload, err := h.caller(a, message)
+	if err != nil {
+		slog.Error("Unable to determine caller", "err", err)
+		h.responses.SendResponse(c, a, response.NewErrorResponse(message.Header.MessageId, response.StatusBadRequest, err.Error()))
+		return
+	}
+
+	// Unmarshal into payload
+	var p request.BookingListPayload
+	if err := p.UnmarshalBinary(payload); err != nil {
+		slog.Error("Unable to unmarshal BookingListPayload", "err", err)
+		h.responses.SendResponse(c, a, response.NewErrorResponse(message.Header.MessageId, response.StatusBadRequest, err.Error()))
+		return
+	}
+
+	limit := p.Limit
+	if limit == 0 {
+		limit = maxBookingList
+	}
+
+	// One more booking than the limit is listed, to tell whether more bookings follow
+	listed, err := h.manager.ListBookingsAs(caller, p.Name, p.Start, p.End, p.After, limit+1)
+	if err != nil {
+		slog.Error("Unable to list bookings", "FacilityName", p.Name, "Start", p.Start, "End", p.End, "err", err)
+		h.responses.SendResponse(c, a, response.NewErrorResponse(message.Header.MessageId, response.StatusBadRequest, err.Error()))
+		return
+	}
+	more := len(listed) > limit
+	if more {
+		listed = listed[:limit]
+	}
+
+	slog.Info("Successfully listed bookings", "FacilityName", p.Name, "Found", len(listed))
+	h.responses.SendResponse(c, a, response.NewBookingListResponse(message.Header.MessageId, listed, more, maxAvailabilityBytes))
+}
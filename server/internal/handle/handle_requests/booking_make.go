@@ -10,27 +10,26 @@ import (
 	"server/internal/rpc/response"
 )
 
-func BookingMake(c *net.UDPConn, a *net.UDPAddr, message *protocol.Message) {
+func (h *Handler) BookingMake(c *net.UDPConn, a *net.UDPAddr, message *protocol.Message) {
 
 	// Get message payload unmarshalled
 	var p request.BookingMakePayload
 	if err := p.UnmarshalBinary(message.Payload[1:]); err != nil {
 		slog.Error("Unable to unmarshall BookingMakePayload", "err", err)
-		response.SendResponse(c, a, response.NewErrorResponse(message.Header.MessageId, response.StatusInternalServerError, err.Error()))
+		h.responses.SendResponse(c, a, response.NewErrorResponse(message.Header.MessageId, response.StatusInternalServerError, err.Error()))
 		return
 	}
 
 	booking, err := p.GetBooking()
 	if err != nil {
 		slog.Error("Unable to create instance of booking", "err", err)
-		response.SendResponse(c, a, response.NewErrorResponse(message.Header.MessageId, response.StatusBadRequest, err.Error()))
+		h.responses.SendResponse(c, a, response.NewErrorResponse(message.Header.MessageId, response.StatusBadRequest, err.Error()))
 		return
 	}
 
-	manager := bookings.GetManager()
-	if err := manager.NewBooking(p.Name, booking); err != nil {
+	if booking.Id, err = h.manager.NewBookingWithUnusedIdAs(anonymousCaller(a, message), p.Name, booking, bookings.MaxLegacyBookingId); err != nil {
 		slog.Error("Unable to make booking", "err", err)
-		response.SendResponse(c, a, response.NewErrorResponse(message.Header.MessageId, response.StatusBadRequest, err.Error()))
+		h.responses.SendResponse(c, a, response.NewErrorResponse(message.Header.MessageId, response.StatusBadRequest, err.Error()))
 		return
 	}
 
@@ -43,5 +42,5 @@ func BookingMake(c *net.UDPConn, a *net.UDPAddr, message *protocol.Message) {
 	)
 
 	slog.Info("Successfully made booking", "Booking", p)
-	response.SendResponse(c, a, res)
+	h.responses.SendResponse(c, a, res)
 }
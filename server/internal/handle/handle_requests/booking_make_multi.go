@@ -0,0 +1,60 @@
+package handle_requests
+
+import (
+	"errors"
+	"fmt"
+	"log/slog"
+	"math"
+	"net"
+	"server/internal/bookings"
+	"server/internal/protocol"
+	"server/internal/rpc/request"
+	"server/internal/rpc/response"
+)
+
+func (h *Handler) BookingMakeMulti(c *net.UDPConn, a *net.UDPAddr, message *protocol.Message) {
+
+	// Get the caller, who may have supplied a principal with the payload
+	caller, payload, err := h.caller(a, message)
+	if err != nil {
+		slog.Error("Unable to determine caller", "err", err)
+		h.responses.SendResponse(c, a, response.NewErrorResponse(message.Header.MessageId, response.StatusBadRequest, err.Error()))
+		return
+	}
+
+	// Get message payload unmarshalled
+	var p request.BookingMakeMultiPayload
+	if err := p.UnmarshalBinary(payload); err != nil {
+		slog.Error("Unable to unmarshall BookingMakeMultiPayload", "err", err)
+		h.responses.SendResponse(c, a, response.NewErrorResponse(message.Header.MessageId, response.StatusBadRequest, err.Error()))
+		return
+	}
+
+	items := make([]bookings.BookingItem, len(p.Items))
+	for i, item := range p.Items {
+		booking, err := item.GetBooking(h.slot())
+		if err != nil {
+			slog.Error("Unable to create instance of booking", "Index", i, "err", err)
+			h.responses.SendResponse(c, a, response.NewConflictResponse(message.Header.MessageId, i, err.Error()))
+			return
+		}
+		booking.Owner = caller.Principal
+		items[i] = bookings.BookingItem{Facility: item.Name, Booking: booking}
+	}
+
+	// Bookings made together always have 64 bit Ids
+	ids, err := h.manager.NewBookingsAs(caller, items, math.MaxUint64)
+	if err != nil {
+		slog.Error("Unable to make bookings", "err", err)
+		var conflict *bookings.ConflictError
+		if errors.As(err, &conflict) {
+			h.responses.SendResponse(c, a, response.NewConflictResponse(message.Header.MessageId, conflict.Index, conflict.Err.Error()))
+			return
+		}
+		h.responses.SendResponse(c, a, response.NewErrorResponse(message.Header.MessageId, response.StatusBadRequest, err.Error()))
+		return
+	}
+
+	slog.Info("Successfully made bookings", "BookingIds", fmt.Sprintf("%v", ids))
+	h.responses.SendResponse(c, a, response.NewMultiResponse(message.Header.MessageId, ids))
+}
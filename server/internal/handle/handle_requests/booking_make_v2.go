@@ -0,0 +1,63 @@
+package handle_requests
+
+import (
+	"fmt"
+	"log/slog"
+	"math"
+	"net"
+	"server/internal/bookings"
+	"server/internal/protocol"
+	"server/internal/rpc/request"
+	"server/internal/rpc/response"
+)
+
+func (h *Handler) BookingMakeV2(c *net.UDPConn, a *net.UDPAddr, message *protocol.Message) {
+
+	// Get the caller, who may have supplied a principal with the payload
+	caller, payload, err := h.caller(a, message)
+	if err != nil {
+		slog.Error("Unable to determine caller", "err", err)
+		h.responses.SendResponse(c, a, response.NewErrorResponse(message.Header.MessageId, response.StatusBadRequest, err.Error()))
+		return
+	}
+
+	// Get message payload unmarshalled
+	var p request.BookingMakePayloadV2
+	if err := p.UnmarshalBinary(payload); err != nil {
+		slog.Error("Unable to unmarshall BookingMakePayloadV2", "err", err)
+		h.responses.SendResponse(c, a, response.NewErrorResponse(message.Header.MessageId, response.StatusBadRequest, err.Error()))
+		return
+	}
+
+	booking, err := p.GetBooking(h.slot())
+	if err != nil {
+		slog.Error("Unable to create instance of booking", "err", err)
+		h.responses.SendResponse(c, a, response.NewErrorResponse(message.Header.MessageId, response.StatusBadRequest, err.Error()))
+		return
+	}
+
+	booking.Owner = caller.Principal
+
+	// Payloads of version 3 are made with a 64 bit Id
+	maxId, idBytes := uint64(bookings.MaxLegacyBookingId), (*bookings.Booking).GetIdAsBytes
+	if p.Version == request.PayloadVersion3 {
+		maxId, idBytes = math.MaxUint64, (*bookings.Booking).GetWideIdAsBytes
+	}
+
+	if booking.Id, err = h.manager.NewBookingWithUnusedIdAs(caller, p.Name, booking, maxId); err != nil {
+		slog.Error("Unable to make booking", "err", err)
+		h.responses.SendResponse(c, a, response.NewErrorResponse(message.Header.MessageId, response.StatusBadRequest, err.Error()))
+		return
+	}
+
+	slog.Info("Booking has been made", "BookingId", fmt.Sprintf("%v", booking.Id))
+
+	res := response.NewResponse(
+		response.WithStatusCode(response.StatusOk),
+		response.WithOriginalMessageId(message.Header.MessageId),
+		response.WithPayloadBytes(idBytes(&booking)),
+	)
+
+	slog.Info("Successfully made booking", "Booking", p)
+	h.responses.SendResponse(c, a, res)
+}
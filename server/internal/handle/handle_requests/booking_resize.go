@@ -0,0 +1,45 @@
+package handle_requests
+
+import (
+	"log/slog"
+	"net"
+	"server/internal/protocol"
+	"server/internal/rpc/request"
+	"server/internal/rpc/response"
+)
+
+func (h *Handler) BookingResize(c *net.UDPConn, a *net.UDPAddr, message *protocol.Message) {
+
+	// Get the caller, who may have supplied a principal with the payload
+	caller, payload, err := h.caller(a, message)
+	if err != nil {
+		slog.Error("Unable to determine caller", "err", err)
+		h.responses.SendResponse(c, a, response.NewErrorResponse(message.Header.MessageId, response.StatusBadRequest, err.Error()))
+		return
+	}
+
+	// Get message payload unmarshalled
+	var p request.BookingResizePayload
+	if err := p.UnmarshalBinary(payload); err != nil {
+		slog.Error("Unable to unmarshall BookingResizePayload", "err", err)
+		h.responses.SendResponse(c, a, response.NewErrorResponse(message.Header.MessageId, response.StatusBadRequest, err.Error()))
+		return
+	}
+	start, end, err := p.GetTimes(h.slot())
+	if err != nil {
+		slog.Error("Invalid booking resize", "err", err)
+		h.responses.SendResponse(c, a, response.NewErrorResponse(message.Header.MessageId, response.StatusBadRequest, err.Error()))
+		return
+	}
+
+	// Resize booking
+	if err := h.manager.ResizeBookingFromIdAs(caller, p.Id, start, end); err != nil {
+		slog.Error("Unable to resize booking", "err", err)
+		h.responses.SendResponse(c, a, response.NewErrorResponse(message.Header.MessageId, errorStatus(err), err.Error()))
+		return
+	}
+
+	// Booking has been resized
+	slog.Info("Booking has been resized", "BookingId", p.Id, "Start", start, "End", end)
+	h.responses.SendResponse(c, a, response.NewOkResponse(message.Header.MessageId))
+}
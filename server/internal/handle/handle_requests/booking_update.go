@@ -3,32 +3,31 @@ package handle_requests
 import (
 	"log/slog"
 	"net"
-	"server/internal/bookings"
 	"server/internal/protocol"
 	"server/internal/rpc/request"
 	"server/internal/rpc/response"
+	"time"
 )
 
-func BookingUpdate(c *net.UDPConn, a *net.UDPAddr, message *protocol.Message) {
+func (h *Handler) BookingUpdate(c *net.UDPConn, a *net.UDPAddr, message *protocol.Message) {
 
 	// Get message payload unmarshalled
 	var p request.BookingModifyPayload
 	if err := p.UnmarshalBinary(message.Payload[1:]); err != nil {
 		slog.Error("Unable to unmarshall BookingModifyPayload", "err", err)
-		response.SendResponse(c, a, response.NewErrorResponse(message.Header.MessageId, response.StatusInternalServerError, err.Error()))
+		h.responses.SendResponse(c, a, response.NewErrorResponse(message.Header.MessageId, response.StatusInternalServerError, err.Error()))
 		return
 	}
 
-	// Get manager
-	m := bookings.GetManager()
-	err := m.UpdateBookingFromId(p.Id, p.DeltaHour)
+	// Update booking
+	err := h.manager.ShiftBookingFromIdAs(anonymousCaller(a, message), uint64(p.Id), time.Duration(p.DeltaHour)*time.Hour)
 	if err != nil {
 		slog.Error("Unable to update booking", "err", err)
-		response.SendResponse(c, a, response.NewErrorResponse(message.Header.MessageId, response.StatusBadRequest, err.Error()))
+		h.responses.SendResponse(c, a, response.NewErrorResponse(message.Header.MessageId, errorStatus(err), err.Error()))
 		return
 	}
 
 	// Booking has been updated
 	slog.Info("Booking has been updated", "BookingId", p.Id)
-	response.SendResponse(c, a, response.NewOkResponse(message.Header.MessageId))
+	h.responses.SendResponse(c, a, response.NewOkResponse(message.Header.MessageId))
 }
@@ -0,0 +1,54 @@
+package handle_requests
+
+import (
+	"log/slog"
+	"net"
+	"server/internal/protocol"
+	"server/internal/rpc/request"
+	"server/internal/rpc/response"
+	"time"
+)
+
+func (h *Handler) BookingUpdateV2(c *net.UDPConn, a *net.UDPAddr, message *protocol.Message) {
+
+	// Get the caller, who may have supplied a principal with the payload
+	caller, payload, err := h.caller(a, message)
+	if err != nil {
+		slog.Error("Unable to determine caller", "err", err)
+		h.responses.SendResponse(c, a, response.NewErrorResponse(message.Header.MessageId, response.StatusBadRequest, err.Error()))
+		return
+	}
+
+	// Get message payload unmarshalled, payloads of version 3 carry a 64 bit Id
+	var id uint64
+	var delta time.Duration
+	if request.GetPayloadVersion(payload) == request.PayloadVersion3 {
+		var p request.BookingModifyPayloadV3
+		if err = p.UnmarshalBinary(payload); err == nil {
+			id = p.Id
+			delta, err = p.GetDelta(h.slot())
+		}
+	} else {
+		var p request.BookingModifyPayloadV2
+		if err = p.UnmarshalBinary(payload); err == nil {
+			id = uint64(p.Id)
+			delta, err = p.GetDelta(h.slot())
+		}
+	}
+	if err != nil {
+		slog.Error("Unable to unmarshall booking modification", "err", err)
+		h.responses.SendResponse(c, a, response.NewErrorResponse(message.Header.MessageId, response.StatusBadRequest, err.Error()))
+		return
+	}
+
+	// Update booking
+	if err := h.manager.ShiftBookingFromIdAs(caller, id, delta); err != nil {
+		slog.Error("Unable to update booking", "err", err)
+		h.responses.SendResponse(c, a, response.NewErrorResponse(message.Header.MessageId, errorStatus(err), err.Error()))
+		return
+	}
+
+	// Booking has been updated
+	slog.Info("Booking has been updated", "BookingId", id, "Delta", delta)
+	h.responses.SendResponse(c, a, response.NewOkResponse(message.Header.MessageId))
+}
@@ -0,0 +1,39 @@
+package handle_requests
+
+import (
+	"fmt"
+	"log/slog"
+	"net"
+	"server/internal/protocol"
+	"server/internal/rpc/request"
+	"server/internal/rpc/response"
+)
+
+// FacilityCommonFree responds with the free time common to every requested facility, in the format of
+// FacilityFreeSlots.
+func (h *Handler) FacilityCommonFree(c *net.UDPConn, a *net.UDPAddr, message *protocol.Message) {
+
+	// Unmarshal into payload
+	var p request.FacilityCommonFreePayload
+	if err := p.UnmarshalBinary(message.Payload[1:]); err != nil {
+		slog.Error("Unable to unmarshal FacilityCommonFreePayload", "err", err)
+		h.responses.SendResponse(c, a, response.NewErrorResponse(message.Header.MessageId, response.StatusBadRequest, err.Error()))
+		return
+	}
+
+	// Search facilities
+	free, err := h.manager.CommonFreeTime(p.Names, p.Start, p.End, p.MinDuration)
+	if err != nil {
+		slog.Error("Unable to execute common free time search", "FacilityNames", p.Names, "Start", p.Start, "End", p.End, "MinDuration", p.MinDuration, "err", err)
+		h.responses.SendResponse(c, a, response.NewErrorResponse(message.Header.MessageId, response.StatusBadRequest, err.Error()))
+		return
+	}
+	if len(free) > maxFreeSlots {
+		err := fmt.Errorf("%d periods of common free time exceed the limit of %d, search a shorter window", len(free), maxFreeSlots)
+		slog.Error("Unable to execute common free time search", "FacilityNames", p.Names, "err", err)
+		h.responses.SendResponse(c, a, response.NewErrorResponse(message.Header.MessageId, response.StatusBadRequest, err.Error()))
+		return
+	}
+	slog.Info("Successfully searched for common free time", "FacilityNames", p.Names, "Start", p.Start, "End", p.End, "MinDuration", p.MinDuration, "Found", len(free))
+	h.responses.SendResponse(c, a, response.NewSlotsResponse(message.Header.MessageId, free))
+}
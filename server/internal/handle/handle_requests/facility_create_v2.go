@@ -0,0 +1,41 @@
+package handle_requests
+
+import (
+	"log/slog"
+	"net"
+	"server/internal/bookings"
+	"server/internal/protocol"
+	"server/internal/rpc/request"
+	"server/internal/rpc/response"
+)
+
+func (h *Handler) FacilityCreateV2(c *net.UDPConn, a *net.UDPAddr, message *protocol.Message) {
+
+	// Get the caller, who may have supplied a principal with the payload
+	caller, payload, err := h.caller(a, message)
+	if err != nil {
+		slog.Error("Unable to determine caller", "err", err)
+		h.responses.SendResponse(c, a, response.NewErrorResponse(message.Header.MessageId, response.StatusBadRequest, err.Error()))
+		return
+	}
+
+	// Get message payload unmarshalled
+	var p request.FacilityCreatePayloadV2
+	if err := p.UnmarshalBinary(payload); err != nil {
+		slog.Error("Unable to unmarshall FacilityCreatePayloadV2", "err", err)
+		h.responses.SendResponse(c, a, response.NewErrorResponse(message.Header.MessageId, response.StatusBadRequest, err.Error()))
+		return
+	}
+
+	// Create facility
+	err = h.manager.NewFacilityAs(caller, p.Name, bookings.FacilityWithCapacity(p.Capacity))
+	if err != nil {
+		slog.Error("Unable to create new Facility", "err", err)
+		h.responses.SendResponse(c, a, response.NewErrorResponse(message.Header.MessageId, response.StatusBadRequest, err.Error()))
+		return
+	}
+
+	// Facility successfully created
+	slog.Info("Successfully created facility", "Facility", p.Name, "Capacity", p.Capacity)
+	h.responses.SendResponse(c, a, response.NewOkResponse(message.Header.MessageId))
+}
@@ -0,0 +1,39 @@
+package handle_requests
+
+import (
+	"log/slog"
+	"net"
+	"server/internal/protocol"
+	"server/internal/rpc/request"
+	"server/internal/rpc/response"
+)
+
+func (h *Handler) FacilityDeleteV2(c *net.UDPConn, a *net.UDPAddr, message *protocol.Message) {
+
+	// Get the caller, who may have supplied a principal with the payload
+	caller, payload, err := h.caller(a, message)
+	if err != nil {
+		slog.Error("Unable to determine caller", "err", err)
+		h.responses.SendResponse(c, a, response.NewErrorResponse(message.Header.MessageId, response.StatusBadRequest, err.Error()))
+		return
+	}
+
+	// Get payload
+	var p request.FacilityDeletePayloadV2
+	if err := p.UnmarshalBinary(payload); err != nil {
+		slog.Error("Unable to unmarshall FacilityDeletePayloadV2", "err", err)
+		h.responses.SendResponse(c, a, response.NewErrorResponse(message.Header.MessageId, response.StatusBadRequest, err.Error()))
+		return
+	}
+
+	// Process the request
+	if err := h.manager.DeleteFacilityAs(caller, p.Name); err != nil {
+		slog.Error("Unable to delete Facility", "FacilityName", p.Name, "err", err)
+		h.responses.SendResponse(c, a, response.NewErrorResponse(message.Header.MessageId, response.StatusBadRequest, err.Error()))
+		return
+	}
+
+	// Successfully deleted
+	slog.Info("Successfully deleted facility, sending response", "FacilityName", p.Name)
+	h.responses.SendResponse(c, a, response.NewOkResponse(message.Header.MessageId))
+}
@@ -0,0 +1,49 @@
+package handle_requests
+
+import (
+	"fmt"
+	"log/slog"
+	"net"
+	"server/internal/protocol"
+	"server/internal/rpc/request"
+	"server/internal/rpc/response"
+)
+
+// maxFreeSlots is the largest number of free periods that fits within a single response packet.
+const maxFreeSlots = (maxAvailabilityBytes - 1) / 8
+
+// FacilityFreeSlots responds with the next free periods of the facility, each starting on a booking slot.
+func (h *Handler) FacilityFreeSlots(c *net.UDPConn, a *net.UDPAddr, message *protocol.Message) {
+
+	// Unmarshal into payload
+	var p request.FacilityFreeSlotsPayload
+	if err := p.UnmarshalBinary(message.Payload[1:]); err != nil {
+		slog.Error("Unable to unmarshal FacilityFreeSlotsPayload", "err", err)
+		h.responses.SendResponse(c, a, response.NewErrorResponse(message.Header.MessageId, response.StatusBadRequest, err.Error()))
+		return
+	}
+
+	slot := h.slot()
+	if p.Count > maxFreeSlots {
+		err := fmt.Errorf("%d free periods exceed the limit of %d", p.Count, maxFreeSlots)
+		slog.Error("Unable to execute free slot search", "FacilityName", p.Name, "err", err)
+		h.responses.SendResponse(c, a, response.NewErrorResponse(message.Header.MessageId, response.StatusBadRequest, err.Error()))
+		return
+	}
+	if p.Duration%slot != 0 {
+		err := fmt.Errorf("duration %v is not a multiple of the booking slot %v", p.Duration, slot)
+		slog.Error("Unable to execute free slot search", "FacilityName", p.Name, "err", err)
+		h.responses.SendResponse(c, a, response.NewErrorResponse(message.Header.MessageId, response.StatusBadRequest, err.Error()))
+		return
+	}
+
+	// Search facility
+	slots, err := h.manager.FindFreeSlots(p.Name, p.Start, p.End, p.Duration, slot, p.Count, p.WithinHours())
+	if err != nil {
+		slog.Error("Unable to execute free slot search", "FacilityName", p.Name, "Start", p.Start, "End", p.End, "Duration", p.Duration, "err", err)
+		h.responses.SendResponse(c, a, response.NewErrorResponse(message.Header.MessageId, response.StatusBadRequest, err.Error()))
+		return
+	}
+	slog.Info("Successfully searched for free slots", "FacilityName", p.Name, "Start", p.Start, "End", p.End, "Duration", p.Duration, "Found", len(slots))
+	h.responses.SendResponse(c, a, response.NewSlotsResponse(message.Header.MessageId, slots))
+}
@@ -0,0 +1,40 @@
+package handle_requests
+
+import (
+	"log/slog"
+	"net"
+	"server/internal/protocol"
+	"server/internal/rpc/request"
+	"server/internal/rpc/response"
+)
+
+// maxFacilityList is the largest number of facilities listed in a single response.
+const maxFacilityList = 0xFF
+
+// FacilityList responds with a page of the facilities selected by the request, as many as fit within a single response
+// packet. Clients list the following page by requesting the facilities after the cursor of the last facility listed.
+func (h *Handler) FacilityList(c *net.UDPConn, a *net.UDPAddr, message *protocol.Message) {
+
+	// Unmarshal into payload
+	var p request.FacilityListPayload
+	if err := p.UnmarshalBinary(message.Payload[1:]); err != nil {
+		slog.Error("Unable to unmarshal FacilityListPayload", "err", err)
+		h.responses.SendResponse(c, a, response.NewErrorResponse(message.Header.MessageId, response.StatusBadRequest, err.Error()))
+		return
+	}
+
+	limit := p.Limit
+	if limit == 0 {
+		limit = maxFacilityList
+	}
+
+	// One more facility than the limit is listed, to tell whether more facilities follow
+	facilities := h.manager.ListFacilities(p.Filter, limit+1)
+	more := len(facilities) > limit
+	if more {
+		facilities = facilities[:limit]
+	}
+
+	slog.Info("Successfully listed facilities", "Filter", p.Filter, "Found", len(facilities))
+	h.responses.SendResponse(c, a, response.NewFacilityListResponse(message.Header.MessageId, facilities, more, maxAvailabilityBytes))
+}
@@ -11,53 +11,58 @@ import (
 	"time"
 )
 
-func FacilityMonitor(c *net.UDPConn, a *net.UDPAddr, message *protocol.Message) {
+func (h *Handler) FacilityMonitor(c *net.UDPConn, a *net.UDPAddr, message *protocol.Message) {
 
 	// Get request payload
 	var p request.FacilityMonitorPayload
 	if err := p.UnmarshalBinary(message.Payload[1:]); err != nil {
 		slog.Error("Unable to unmarshall FacilityMonitorPayload", "err", err)
-		response.SendResponse(c, a, response.NewErrorResponse(message.Header.MessageId, response.StatusInternalServerError, err.Error()))
+		h.responses.SendResponse(c, a, response.NewErrorResponse(message.Header.MessageId, response.StatusInternalServerError, err.Error()))
 		return
 	}
 
 	// Register connection as a client
+	h.responses.SendResponse(c, a, response.NewResponse(
+		response.WithOriginalMessageId(message.Header.MessageId),
+		response.WithStatusCode(response.StatusOk),
+		response.WithPayloadMessage(fmt.Sprintf("Monitoring %s for %d seconds", p.Name, p.Ttl)),
+	))
+	consumer := h.manager.Monitor().Watch(p.Name, time.Duration(p.Ttl)*time.Second)
+
+	// Monitoring lasts for the TTL, therefore it is moved off the worker onto a background GoRoutine
+	h.background.Add(1)
 	go func() {
-		response.SendResponse(c, a, response.NewResponse(
-			response.WithOriginalMessageId(message.Header.MessageId),
-			response.WithStatusCode(response.StatusOk),
-			response.WithPayloadMessage(fmt.Sprintf("Monitoring %s for %d seconds", p.Name, p.Ttl)),
-		))
-		consumer := bookings.GetMonitor().Watch(p.Name, time.Duration(p.Ttl)*time.Second)
+		defer h.background.Done()
+		h.streamMonitorUpdates(c, a, message, consumer)
+	}()
+}
 
-		// Continuously listen for messages and send them to client
-		for {
-			select {
-			case s, ok := <-consumer.Channel:
-				if !ok {
-					// Channel closed, exit gracefully
-					response.SendResponse(c, a, response.NewResponse(
-						response.WithOriginalMessageId(message.Header.MessageId),
-						response.WithStatusCode(response.StatusOk),
-						response.WithPayloadMessage("Monitoring stopped (channel closed)"),
-					))
-					return
-				}
-				response.SendResponse(c, a, response.NewResponse(
-					response.WithOriginalMessageId(message.Header.MessageId),
-					response.WithStatusCode(response.StatusOk),
-					response.WithPayloadMessage(s),
-				))
-			case <-consumer.Ctx.Done():
-				response.SendResponse(c, a, response.NewResponse(
+// streamMonitorUpdates continuously listens for messages and sends them to the client, until monitoring ends.
+func (h *Handler) streamMonitorUpdates(c *net.UDPConn, a *net.UDPAddr, message *protocol.Message, consumer *bookings.MonitorConsumer) {
+	for {
+		select {
+		case s, ok := <-consumer.Channel:
+			if !ok {
+				// Channel closed, exit gracefully
+				h.responses.SendResponse(c, a, response.NewResponse(
 					response.WithOriginalMessageId(message.Header.MessageId),
 					response.WithStatusCode(response.StatusOk),
-					response.WithPayloadMessage("Monitoring over"),
+					response.WithPayloadMessage("Monitoring stopped (channel closed)"),
 				))
 				return
 			}
+			h.responses.SendResponse(c, a, response.NewResponse(
+				response.WithOriginalMessageId(message.Header.MessageId),
+				response.WithStatusCode(response.StatusOk),
+				response.WithPayloadMessage(s),
+			))
+		case <-consumer.Ctx.Done():
+			h.responses.SendResponse(c, a, response.NewResponse(
+				response.WithOriginalMessageId(message.Header.MessageId),
+				response.WithStatusCode(response.StatusOk),
+				response.WithPayloadMessage("Monitoring over"),
+			))
+			return
 		}
-
-	}()
-
+	}
 }
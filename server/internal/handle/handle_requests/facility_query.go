@@ -3,13 +3,14 @@ package handle_requests
 import (
 	"log/slog"
 	"net"
-	"server/internal/bookings"
 	"server/internal/protocol"
 	"server/internal/rpc/request"
 	"server/internal/rpc/response"
 )
 
-func FacilityQuery(c *net.UDPConn, a *net.UDPAddr, message *protocol.Message) {
+// FacilityQuery responds with one bit per hour, set if the facility is unavailable during that hour because it is
+// either booked or closed. Clients that need to tell closed hours apart must use FacilityQueryCapacity instead.
+func (h *Handler) FacilityQuery(c *net.UDPConn, a *net.UDPAddr, message *protocol.Message) {
 
 	// Unmarshal into payload
 	var p request.FacilityQueryPayload
@@ -19,18 +20,17 @@ func FacilityQuery(c *net.UDPConn, a *net.UDPAddr, message *protocol.Message) {
 	}
 
 	// Query facility
-	m := bookings.GetManager()
-	r, err := m.QueryFacility(p.Name, p.Days)
+	r, err := h.manager.QueryFacility(p.Name, p.Days)
 	if err != nil {
 		slog.Error("Unable to execute query", "FacilityName", p.Name, "Days", p.Days, "err", err)
-		response.SendResponse(c, a, response.NewResponse(
+		h.responses.SendResponse(c, a, response.NewResponse(
 			response.WithOriginalMessageId(message.Header.MessageId),
 			response.WithStatusCode(response.StatusBadRequest),
 		))
 		return
 	}
 	slog.Info("Successfully queried facility", "FacilityName", p.Name, "Days", p.Days, "Res", r)
-	response.SendResponse(c, a, response.NewResponse(
+	h.responses.SendResponse(c, a, response.NewResponse(
 		response.WithOriginalMessageId(message.Header.MessageId),
 		response.WithStatusCode(response.StatusOk),
 		response.WithPayloadBytes(r),
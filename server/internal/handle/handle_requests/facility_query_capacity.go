@@ -0,0 +1,55 @@
+package handle_requests
+
+import (
+	"fmt"
+	"log/slog"
+	"net"
+	"server/internal/protocol"
+	"server/internal/rpc/request"
+	"server/internal/rpc/response"
+	"time"
+)
+
+// FacilityQueryCapacity responds with one byte per slot, the remaining capacity of the facility during that slot or
+// bookings.ClosedSlot if the facility is closed.
+func (h *Handler) FacilityQueryCapacity(c *net.UDPConn, a *net.UDPAddr, message *protocol.Message) {
+
+	// Unmarshal into payload
+	var p request.FacilityQueryPayloadV2
+	if err := p.UnmarshalBinary(message.Payload[1:]); err != nil {
+		slog.Error("Unable to unmarshal FacilityQueryPayloadV2", "err", err)
+		h.responses.SendResponse(c, a, response.NewErrorResponse(message.Header.MessageId, response.StatusBadRequest, err.Error()))
+		return
+	}
+
+	resolution := p.GetResolution(h.slot())
+	if resolution > 0 && (24*time.Hour)%resolution == 0 {
+		if size := p.Days * int(24*time.Hour/resolution); size > maxAvailabilityBytes {
+			err := fmt.Errorf("capacity of %d days at %v is %d bytes, exceeding %d bytes", p.Days, resolution, size, maxAvailabilityBytes)
+			slog.Error("Unable to execute capacity query", "FacilityName", p.Name, "err", err)
+			h.responses.SendResponse(c, a, response.NewErrorResponse(message.Header.MessageId, response.StatusBadRequest, err.Error()))
+			return
+		}
+	}
+
+	first, err := p.GetFirstDay()
+	if err != nil {
+		slog.Error("Unable to execute capacity query", "FacilityName", p.Name, "err", err)
+		h.responses.SendResponse(c, a, response.NewErrorResponse(message.Header.MessageId, response.StatusBadRequest, err.Error()))
+		return
+	}
+
+	// Query facility
+	r, err := h.manager.QueryFacilityCapacityFrom(p.Name, first, p.Days, resolution)
+	if err != nil {
+		slog.Error("Unable to execute capacity query", "FacilityName", p.Name, "First", first, "Days", p.Days, "Resolution", resolution, "err", err)
+		h.responses.SendResponse(c, a, response.NewErrorResponse(message.Header.MessageId, response.StatusBadRequest, err.Error()))
+		return
+	}
+	slog.Info("Successfully queried facility capacity", "FacilityName", p.Name, "First", first, "Days", p.Days, "Resolution", resolution, "Res", r)
+	h.responses.SendResponse(c, a, response.NewResponse(
+		response.WithOriginalMessageId(message.Header.MessageId),
+		response.WithStatusCode(response.StatusOk),
+		response.WithPayloadBytes(r),
+	))
+}
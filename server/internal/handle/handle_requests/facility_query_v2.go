@@ -0,0 +1,60 @@
+package handle_requests
+
+import (
+	"fmt"
+	"log/slog"
+	"net"
+	"server/internal/protocol"
+	"server/internal/protocol/proto_defs"
+	"server/internal/rpc/request"
+	"server/internal/rpc/response"
+	"time"
+)
+
+// maxAvailabilityBytes is the largest availability that fits within a single response packet, after the original
+// message id and status code.
+const maxAvailabilityBytes = proto_defs.PacketPayloadSizeLimit - 18
+
+// FacilityQueryV2 responds with one bit per slot, set if the facility is unavailable during that slot because it is
+// either booked or closed. Clients that need to tell closed slots apart must use FacilityQueryCapacity instead.
+func (h *Handler) FacilityQueryV2(c *net.UDPConn, a *net.UDPAddr, message *protocol.Message) {
+
+	// Unmarshal into payload
+	var p request.FacilityQueryPayloadV2
+	if err := p.UnmarshalBinary(message.Payload[1:]); err != nil {
+		slog.Error("Unable to unmarshal FacilityQueryPayloadV2", "err", err)
+		h.responses.SendResponse(c, a, response.NewErrorResponse(message.Header.MessageId, response.StatusBadRequest, err.Error()))
+		return
+	}
+
+	resolution := p.GetResolution(h.slot())
+	if resolution > 0 && (24*time.Hour)%resolution == 0 {
+		if size := (p.Days*int(24*time.Hour/resolution) + 7) / 8; size > maxAvailabilityBytes {
+			err := fmt.Errorf("availability of %d days at %v is %d bytes, exceeding %d bytes", p.Days, resolution, size, maxAvailabilityBytes)
+			slog.Error("Unable to execute query", "FacilityName", p.Name, "err", err)
+			h.responses.SendResponse(c, a, response.NewErrorResponse(message.Header.MessageId, response.StatusBadRequest, err.Error()))
+			return
+		}
+	}
+
+	first, err := p.GetFirstDay()
+	if err != nil {
+		slog.Error("Unable to execute query", "FacilityName", p.Name, "err", err)
+		h.responses.SendResponse(c, a, response.NewErrorResponse(message.Header.MessageId, response.StatusBadRequest, err.Error()))
+		return
+	}
+
+	// Query facility
+	r, err := h.manager.QueryFacilityFrom(p.Name, first, p.Days, resolution)
+	if err != nil {
+		slog.Error("Unable to execute query", "FacilityName", p.Name, "First", first, "Days", p.Days, "Resolution", resolution, "err", err)
+		h.responses.SendResponse(c, a, response.NewErrorResponse(message.Header.MessageId, response.StatusBadRequest, err.Error()))
+		return
+	}
+	slog.Info("Successfully queried facility", "FacilityName", p.Name, "First", first, "Days", p.Days, "Resolution", resolution, "Res", r)
+	h.responses.SendResponse(c, a, response.NewResponse(
+		response.WithOriginalMessageId(message.Header.MessageId),
+		response.WithStatusCode(response.StatusOk),
+		response.WithPayloadBytes(r),
+	))
+}
@@ -0,0 +1,114 @@
+package handle_requests
+
+import (
+	"encoding/hex"
+	"errors"
+	"net"
+	"server/internal/bookings"
+	"server/internal/network"
+	"server/internal/protocol"
+	"server/internal/ratelimit"
+	"server/internal/rpc/request"
+	"server/internal/rpc/response"
+	"server/internal/vars"
+	"sync"
+	"time"
+)
+
+// Handler executes RPC requests against the state owned by a single server instance.
+type Handler struct {
+	background sync.WaitGroup // Requests that continue in the background after being handled (e.g. monitoring)
+
+	env       *vars.StaticEnvStruct
+	manager   *bookings.Manager
+	responses *response.History
+	limiter   *ratelimit.Limiter
+}
+
+func NewHandler(env *vars.StaticEnvStruct, manager *bookings.Manager, responses *response.History) *Handler {
+	return &Handler{
+		env:       env,
+		manager:   manager,
+		responses: responses,
+		limiter:   ratelimit.NewLimiter(env),
+	}
+}
+
+// slot returns the granularity of minute-based bookings.
+func (h *Handler) slot() time.Duration {
+	return time.Duration(h.env.BookingSlotMinutes) * time.Minute
+}
+
+// caller returns the caller of a request and the payload of the request, splitting off the idempotency key and
+// principal of payloads made with request.WithIdempotencyKey and request.WithPrincipal.
+func (h *Handler) caller(a *net.UDPAddr, message *protocol.Message) (bookings.Caller, []byte, error) {
+	key, payload, err := request.SplitIdempotencyKey(message.Payload[1:])
+	if err != nil {
+		return bookings.Caller{}, nil, err
+	}
+	principal, payload, err := request.SplitPrincipal(payload)
+	if err != nil {
+		return bookings.Caller{}, nil, err
+	}
+
+	c := anonymousCaller(a, message)
+	c.Principal = principal
+	c.Admin = principal != "" && principal == h.env.AdminPrincipal
+	c.IdempotencyKey = key
+	c.IdempotencyTTL = time.Duration(h.env.IdempotencyTTL) * time.Millisecond
+	return c, payload, nil
+}
+
+// anonymousMethods are the methods with unversioned payloads, which cannot supply a principal.
+var anonymousMethods = map[request.MethodIdentifier]bool{
+	request.MethodIdentifierFacilityCreate:        true,
+	request.MethodIdentifierFacilityQuery:         true,
+	request.MethodIdentifierFacilityMonitor:       true,
+	request.MethodIdentifierFacilityDelete:        true,
+	request.MethodIdentifierFacilityQueryV2:       true,
+	request.MethodIdentifierFacilityQueryCapacity: true,
+	request.MethodIdentifierFacilityFreeSlots:     true,
+	request.MethodIdentifierFacilityCommonFree:    true,
+	request.MethodIdentifierFacilityList:          true,
+	request.MethodIdentifierBookingMake:           true,
+	request.MethodIdentifierBookingUpdate:         true,
+	request.MethodIdentifierBookingDelete:         true,
+}
+
+// limitKeys returns the keys a request is rate limited by: the address of the client, and the principal supplied with
+// the payload, if any. As principals are chosen by clients, they only add to the limit of the address, so that a
+// principal shares its limit across addresses without an address evading its own limit.
+func limitKeys(a *net.UDPAddr, method request.MethodIdentifier, message *protocol.Message) []string {
+	keys := []string{network.AddrKey(a)}
+	if !anonymousMethods[method] {
+		// Malformed payloads are rejected by the method, and limited by address alone until then
+		if _, payload, err := request.SplitIdempotencyKey(message.Payload[1:]); err == nil {
+			if principal, _, err := request.SplitPrincipal(payload); err == nil && principal != "" {
+				keys = append(keys, "principal:"+principal)
+			}
+		}
+	}
+	return keys
+}
+
+// anonymousCaller returns the caller of a request that cannot supply a principal, e.g. with an unversioned payload.
+func anonymousCaller(a *net.UDPAddr, message *protocol.Message) bookings.Caller {
+	return bookings.Caller{
+		Address:   a.String(),
+		RequestId: hex.EncodeToString(message.Header.MessageId[:]),
+	}
+}
+
+// errorStatus returns the status code of a response to a request that failed with err.
+func errorStatus(err error) response.StatusCode {
+	if errors.Is(err, bookings.ErrForbidden) {
+		return response.StatusForbidden
+	}
+	return response.StatusBadRequest
+}
+
+// WaitBackground blocks until all requests running in the background have completed.
+// Must only be called once no new requests are being handled.
+func (h *Handler) WaitBackground() {
+	h.background.Wait()
+}
@@ -6,60 +6,135 @@ import (
 	"server/internal/protocol"
 	"server/internal/rpc/request"
 	"server/internal/rpc/response"
-	"server/internal/vars"
 )
 
-func Sort(c *net.UDPConn, a *net.UDPAddr, m *protocol.Message) {
-
-	h := response.GetResponseHistoryInstance()
+func (h *Handler) Sort(c *net.UDPConn, a *net.UDPAddr, m *protocol.Message) {
 
 	// Check if message has been processed or is processing
-	if done, exists := h.Check(m.Header.MessageId); exists && vars.GetStaticEnv().EnableDuplicateFiltering {
+	if done, exists := h.responses.Check(m.Header.MessageId); exists && h.env.EnableDuplicateFiltering {
 		if !done {
 			slog.Info("Request is supposed to invoke a processes that is still running, ignoring duplicate")
 			return
 		}
 
 		slog.Info("Request received, but request has already been sent, resending response")
-		r, err := h.GetResponse(m.Header.MessageId)
+		r, err := h.responses.GetResponse(m.Header.MessageId)
 		if err != nil {
 			slog.Error("Unable to retrieve historical response", "err", err)
 			return
 		}
-		response.SendResponse(c, a, r)
+		h.responses.SendResponse(c, a, r)
 		return
 	}
 
-	// Set processing here as only requests will have message responses
-	h.SetProcessing(m.Header.MessageId)
-
 	var req request.Request
 	if err := req.UnmarshalBinary(m.Payload); err != nil {
+		h.responses.SetProcessing(m.Header.MessageId, false)
 		slog.Error("Unable to determine target method from message", "MessageId", m.Header.MessageId)
 		return
 	}
 
+	// Set processing here as only requests will have message responses; only changes need to survive a restart
+	h.responses.SetProcessing(m.Header.MessageId, !req.MethodIdentifier.ReadOnly())
+
+	// Duplicates have been filtered above, therefore retransmissions of a request do not count towards the limit
+	keys := limitKeys(a, req.MethodIdentifier, m)
+	if ok, retryAfter := h.limiter.Allow(req.MethodIdentifier.String(), keys...); !ok {
+		slog.Warn("Client has been rate limited", "Client", a.String(), "Keys", keys, "Method", req.MethodIdentifier, "RetryAfter", retryAfter)
+		h.responses.SendResponse(c, a, response.NewTooManyRequestsResponse(m.Header.MessageId, retryAfter))
+		return
+	}
+
 	switch req.MethodIdentifier {
 	case request.MethodIdentifierFacilityCreate:
-		FacilityCreate(c, a, m)
+		h.FacilityCreate(c, a, m)
 		break
 	case request.MethodIdentifierFacilityQuery:
-		FacilityQuery(c, a, m)
+		h.FacilityQuery(c, a, m)
 		break
 	case request.MethodIdentifierFacilityMonitor:
-		FacilityMonitor(c, a, m)
+		h.FacilityMonitor(c, a, m)
 		break
 	case request.MethodIdentifierFacilityDelete:
-		FacilityDelete(c, a, m)
+		h.FacilityDelete(c, a, m)
 		break
 	case request.MethodIdentifierBookingMake:
-		BookingMake(c, a, m)
+		h.BookingMake(c, a, m)
 		break
 	case request.MethodIdentifierBookingUpdate:
-		BookingUpdate(c, a, m)
+		h.BookingUpdate(c, a, m)
 		break
 	case request.MethodIdentifierBookingDelete:
-		BookingDelete(c, a, m)
+		h.BookingDelete(c, a, m)
+		break
+	case request.MethodIdentifierFacilityQueryV2:
+		h.FacilityQueryV2(c, a, m)
+		break
+	case request.MethodIdentifierFacilityCreateV2:
+		h.FacilityCreateV2(c, a, m)
+		break
+	case request.MethodIdentifierFacilityQueryCapacity:
+		h.FacilityQueryCapacity(c, a, m)
+		break
+	case request.MethodIdentifierFacilityCreateV3:
+		h.FacilityCreateV3(c, a, m)
+		break
+	case request.MethodIdentifierFacilityUpdate:
+		h.FacilityUpdate(c, a, m)
+		break
+	case request.MethodIdentifierFacilityFreeSlots:
+		h.FacilityFreeSlots(c, a, m)
+		break
+	case request.MethodIdentifierFacilityCommonFree:
+		h.FacilityCommonFree(c, a, m)
+		break
+	case request.MethodIdentifierFacilityDeleteV2:
+		h.FacilityDeleteV2(c, a, m)
+		break
+	case request.MethodIdentifierFacilityList:
+		h.FacilityList(c, a, m)
+		break
+	case request.MethodIdentifierBookingMakeV2:
+		h.BookingMakeV2(c, a, m)
+		break
+	case request.MethodIdentifierBookingUpdateV2:
+		h.BookingUpdateV2(c, a, m)
+		break
+	case request.MethodIdentifierSeriesMake:
+		h.SeriesMake(c, a, m)
+		break
+	case request.MethodIdentifierSeriesUpdate:
+		h.SeriesUpdate(c, a, m)
+		break
+	case request.MethodIdentifierSeriesDelete:
+		h.SeriesDelete(c, a, m)
+		break
+	case request.MethodIdentifierBookingDeleteV2:
+		h.BookingDeleteV2(c, a, m)
+		break
+	case request.MethodIdentifierWaitlistJoin:
+		h.WaitlistJoin(c, a, m)
+		break
+	case request.MethodIdentifierWaitlistLeave:
+		h.WaitlistLeave(c, a, m)
+		break
+	case request.MethodIdentifierBookingHold:
+		h.BookingHold(c, a, m)
+		break
+	case request.MethodIdentifierBookingConfirm:
+		h.BookingConfirm(c, a, m)
+		break
+	case request.MethodIdentifierBookingMakeMulti:
+		h.BookingMakeMulti(c, a, m)
+		break
+	case request.MethodIdentifierBookingResize:
+		h.BookingResize(c, a, m)
+		break
+	case request.MethodIdentifierBookingList:
+		h.BookingList(c, a, m)
+		break
+	case request.MethodIdentifierAuditQuery:
+		h.AuditQuery(c, a, m)
 		break
 	default:
 		slog.Error("Request type not supported", "RequestType", req.MethodIdentifier)
@@ -0,0 +1,60 @@
+package handle_requests
+
+import (
+	"log/slog"
+	"net"
+	"server/internal/protocol"
+	"server/internal/rpc/request"
+	"server/internal/rpc/response"
+)
+
+func (h *Handler) WaitlistJoin(c *net.UDPConn, a *net.UDPAddr, message *protocol.Message) {
+
+	// Get the caller, who may have supplied a principal with the payload
+	caller, payload, err := h.caller(a, message)
+	if err != nil {
+		slog.Error("Unable to determine caller", "err", err)
+		h.responses.SendResponse(c, a, response.NewErrorResponse(message.Header.MessageId, response.StatusBadRequest, err.Error()))
+		return
+	}
+
+	// Get message payload unmarshalled
+	var p request.BookingMakePayloadV2
+	if err := p.UnmarshalBinary(payload); err != nil {
+		slog.Error("Unable to unmarshall BookingMakePayloadV2", "err", err)
+		h.responses.SendResponse(c, a, response.NewErrorResponse(message.Header.MessageId, response.StatusBadRequest, err.Error()))
+		return
+	}
+
+	booking, err := p.GetBooking(h.slot())
+	if err != nil {
+		slog.Error("Unable to create instance of booking", "err", err)
+		h.responses.SendResponse(c, a, response.NewErrorResponse(message.Header.MessageId, response.StatusBadRequest, err.Error()))
+		return
+	}
+
+	booking.Owner = caller.Principal
+
+	// Waitlisted bookings always have a 64 bit Id
+	var booked bool
+	if booking.Id, booked, err = h.manager.JoinWaitlistAs(caller, p.Name, booking); err != nil {
+		slog.Error("Unable to join waitlist", "err", err)
+		h.responses.SendResponse(c, a, response.NewErrorResponse(message.Header.MessageId, response.StatusBadRequest, err.Error()))
+		return
+	}
+
+	// Bookings that are still waiting are accepted rather than ok
+	status := response.StatusAccepted
+	if booked {
+		status = response.StatusOk
+	}
+
+	res := response.NewResponse(
+		response.WithStatusCode(status),
+		response.WithOriginalMessageId(message.Header.MessageId),
+		response.WithPayloadBytes(booking.GetWideIdAsBytes()),
+	)
+
+	slog.Info("Successfully joined waitlist", "Booking", p, "Booked", booked)
+	h.responses.SendResponse(c, a, res)
+}
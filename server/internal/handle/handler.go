@@ -0,0 +1,92 @@
+package handle
+
+import (
+	"context"
+	"server/internal/handle/handle_requests"
+	"server/internal/network"
+	"server/internal/rpc/response"
+	"server/internal/vars"
+	"sync"
+)
+
+// Handler processes incoming packets for a single server instance.
+type Handler struct {
+	mu       sync.Mutex
+	draining bool
+	inflight sync.WaitGroup
+
+	env       *vars.StaticEnvStruct
+	sender    *network.SendHistory
+	responses *response.History
+	assembler *MessageAssembler
+	requests  *handle_requests.Handler
+}
+
+func NewHandler(
+	env *vars.StaticEnvStruct,
+	sender *network.SendHistory,
+	responses *response.History,
+	requests *handle_requests.Handler,
+) *Handler {
+	h := &Handler{
+		env:       env,
+		sender:    sender,
+		responses: responses,
+		requests:  requests,
+	}
+	h.assembler = NewMessageAssembler(env, sender, responses, h.IncomingMessage)
+	return h
+}
+
+// Run executes the background routines of the Handler until ctx is cancelled.
+func (h *Handler) Run(ctx context.Context) {
+	h.assembler.Run(ctx)
+}
+
+// Drain stops the Handler from accepting new requests. Acknowledgements and resend requests are still handled, so
+// that pending responses can be flushed.
+func (h *Handler) Drain() {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	h.draining = true
+}
+
+// IsDraining returns true if the Handler no longer accepts new requests.
+func (h *Handler) IsDraining() bool {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	return h.draining
+}
+
+// Wait blocks until all in-flight requests have been handled, or ctx is done.
+func (h *Handler) Wait(ctx context.Context) error {
+	done := make(chan struct{})
+	go func() {
+		h.inflight.Wait()
+		h.requests.WaitBackground() // Safe as no new requests can be started once in-flight requests are done
+		close(done)
+	}()
+
+	select {
+	case <-done:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// beginRequest marks a request as in-flight, returns false if the Handler is draining and the request must be dropped.
+// Every successful call must be followed by a call to endRequest.
+func (h *Handler) beginRequest() bool {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	if h.draining {
+		return false
+	}
+	h.inflight.Add(1)
+	return true
+}
+
+func (h *Handler) endRequest() {
+	h.inflight.Done()
+}
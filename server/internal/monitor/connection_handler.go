@@ -58,12 +58,12 @@ func (c *ConnectionHandler) RemoveClients(clients []*net.TCPConn) {
 }
 
 func (c *ConnectionHandler) CloseAndRemoveClients() {
+	c.Lock()
+	defer c.Unlock()
 	for _, client := range c.Clients {
-		c.Lock()
 		_ = client.Close()
-		c.Unlock()
-		c.RemoveClient(client)
 	}
+	c.Clients = make([]*net.TCPConn, 0)
 }
 
 func (c *ConnectionHandler) SendMessage(s string) {
@@ -0,0 +1,65 @@
+package monitor
+
+import (
+	"server/internal/bookings"
+	"server/internal/store"
+	"sync"
+)
+
+var (
+	consoleManager   *bookings.Manager
+	consoleManagerMu sync.RWMutex
+
+	consoleStore   *store.Store
+	consoleStoreMu sync.RWMutex
+
+	shutdownFunc   func()
+	shutdownFuncMu sync.RWMutex
+)
+
+// AttachManager sets the bookings.Manager that console commands (e.g. /records, /reset) operate on.
+// Only one Manager can be attached at a time; attaching a new Manager replaces the previous one.
+func AttachManager(m *bookings.Manager) {
+	consoleManagerMu.Lock()
+	defer consoleManagerMu.Unlock()
+	consoleManager = m
+}
+
+// getAttachedManager returns the currently attached manager, nil if none has been attached.
+func getAttachedManager() *bookings.Manager {
+	consoleManagerMu.RLock()
+	defer consoleManagerMu.RUnlock()
+	return consoleManager
+}
+
+// AttachStore sets the store.Store that console commands (e.g. /snapshot) operate on, nil if persistence is disabled.
+func AttachStore(s *store.Store) {
+	consoleStoreMu.Lock()
+	defer consoleStoreMu.Unlock()
+	consoleStore = s
+}
+
+// getAttachedStore returns the currently attached store, nil if none has been attached.
+func getAttachedStore() *store.Store {
+	consoleStoreMu.RLock()
+	defer consoleStoreMu.RUnlock()
+	return consoleStore
+}
+
+// OnShutdown registers f to be called when a shutdown is requested from the console (i.e. /shutdown).
+func OnShutdown(f func()) {
+	shutdownFuncMu.Lock()
+	defer shutdownFuncMu.Unlock()
+	shutdownFunc = f
+}
+
+// triggerShutdown calls the registered shutdown function, returns false if none has been registered.
+func triggerShutdown() bool {
+	shutdownFuncMu.RLock()
+	defer shutdownFuncMu.RUnlock()
+	if shutdownFunc == nil {
+		return false
+	}
+	go shutdownFunc()
+	return true
+}
@@ -11,6 +11,7 @@ import (
 	"log/slog"
 	"os"
 	"server/internal/bookings"
+	"server/internal/rpc/request"
 	"server/internal/vars"
 	"strconv"
 	"strings"
@@ -29,6 +30,20 @@ var (
 	envMessageAssemblerIntervals int
 	envResponseTTL               int
 	envResponseIntervals         int
+	envShutdownGracePeriod       int
+	envRateLimitRate             float64
+	envRateLimitBurst            int
+	envRateLimitMethod           string
+	envClearRateLimitMethod      string
+	envBookingSlotMinutes        int
+	envAdminPrincipal            string
+	envHoldTTL                   int
+	envIdempotencyTTL            int
+	recordsTimezone              string
+	auditFacility                string
+	auditBookingId               uint64
+	auditSince                   time.Duration
+	auditLimit                   int
 
 	flagEnableDuplicateFiltering  string = "enable-duplicate-filtering"
 	flagDisableDuplicateFiltering string = "disable-duplicate-filtering"
@@ -38,6 +53,20 @@ var (
 	flagMessageAssemblerIntervals string = "message-assembler-intervals"
 	flagResponseTTL               string = "response-ttl"
 	flagResponseIntervals         string = "response-intervals"
+	flagShutdownGracePeriod       string = "shutdown-grace-period"
+	flagRateLimitRate             string = "rate-limit-rate"
+	flagRateLimitBurst            string = "rate-limit-burst"
+	flagRateLimitMethod           string = "rate-limit-method"
+	flagClearRateLimitMethod      string = "clear-rate-limit-method"
+	flagBookingSlotMinutes        string = "booking-slot-minutes"
+	flagAdminPrincipal            string = "admin-principal"
+	flagHoldTTL                   string = "hold-ttl"
+	flagIdempotencyTTL            string = "idempotency-ttl"
+	flagTimezone                  string = "timezone"
+	flagFacility                  string = "facility"
+	flagBookingId                 string = "booking-id"
+	flagSince                     string = "since"
+	flagLimit                     string = "limit"
 )
 
 var (
@@ -67,7 +96,7 @@ func init() {
 func register() {
 	// Register command hierarchy
 	rootCmd.SetHelpCommand(helpCmd)
-	rootCmd.AddCommand(envRootCmd, recordsCmd, resetRootCmd, nukeRootCmd, networkCmd)
+	rootCmd.AddCommand(envRootCmd, recordsCmd, auditCmd, resetRootCmd, nukeRootCmd, shutdownRootCmd, networkCmd, snapshotRootCmd)
 
 	// Add subcommands for env
 	envRootCmd.AddCommand(envShowCmd, envSetCmd)
@@ -79,9 +108,28 @@ func register() {
 	envSetCmd.Flags().IntVar(&envMessageAssemblerIntervals, flagMessageAssemblerIntervals, 0, "Set message assembler intervals (ms)")
 	envSetCmd.Flags().IntVar(&envResponseTTL, flagResponseTTL, 0, "Set response TTL (ms)")
 	envSetCmd.Flags().IntVar(&envResponseIntervals, flagResponseIntervals, 0, "Set response intervals (ms)")
+	envSetCmd.Flags().IntVar(&envShutdownGracePeriod, flagShutdownGracePeriod, 0, "Set shutdown grace period (ms)")
+	envSetCmd.Flags().Float64Var(&envRateLimitRate, flagRateLimitRate, 0, "Set requests per second allowed per client (0 disables)")
+	envSetCmd.Flags().IntVar(&envRateLimitBurst, flagRateLimitBurst, 0, "Set requests a client may send at once")
+	envSetCmd.Flags().StringVar(&envRateLimitMethod, flagRateLimitMethod, "", "Set rate limit of a method, as <method>=<rate>:<burst>")
+	envSetCmd.Flags().StringVar(&envClearRateLimitMethod, flagClearRateLimitMethod, "", "Clear rate limit of a method")
+	envSetCmd.Flags().IntVar(&envBookingSlotMinutes, flagBookingSlotMinutes, 0, "Set granularity of minute-based bookings (minutes)")
+	envSetCmd.Flags().StringVar(&envAdminPrincipal, flagAdminPrincipal, "", "Set principal that may change any booking (empty for none)")
+	envSetCmd.Flags().IntVar(&envHoldTTL, flagHoldTTL, 0, "Set time a held booking is kept until it must be confirmed (ms)")
+	envSetCmd.Flags().IntVar(&envIdempotencyTTL, flagIdempotencyTTL, 0, "Set time the outcome of a request with an idempotency key is remembered (ms)")
+
+	recordsCmd.Flags().StringVar(&recordsTimezone, flagTimezone, "", "Show times in a timezone or UTC offset, e.g. Asia/Singapore or UTC+8 (default: timezone of each facility)")
+
+	auditCmd.Flags().StringVar(&auditFacility, flagFacility, "", "Only show changes to a facility")
+	auditCmd.Flags().Uint64Var(&auditBookingId, flagBookingId, 0, "Only show changes to a booking")
+	auditCmd.Flags().DurationVar(&auditSince, flagSince, 0, "Only show changes made within a duration, e.g. 1h")
+	auditCmd.Flags().IntVar(&auditLimit, flagLimit, 50, "Show at most a number of the latest changes (0 for all)")
 
 	// Add subcommands for reset
 	resetRootCmd.AddCommand(resetAllCmd, resetRecordsCmd, resetNetCmd)
+
+	// Add subcommands for snapshot
+	snapshotRootCmd.AddCommand(snapshotTakeCmd, snapshotInfoCmd)
 }
 
 func ExecuteUserCommand(line string) string {
@@ -107,11 +155,17 @@ func ExecuteUserCommand(line string) string {
 			envRootCmd,
 			envShowCmd,
 			envSetCmd,
+			recordsCmd,
+			auditCmd,
 			resetRootCmd,
 			resetAllCmd,
 			resetRecordsCmd,
 			resetNetCmd,
 			nukeRootCmd,
+			shutdownRootCmd,
+			snapshotRootCmd,
+			snapshotTakeCmd,
+			snapshotInfoCmd,
 		} {
 			reset(c)
 		}
@@ -169,27 +223,67 @@ var helpCmd = &cobra.Command{
 	},
 }
 
+// recordsTimeFormat shows times of records together with their UTC offset, as they may be in different timezones.
+const recordsTimeFormat = "2006-01-02 15:04:05 -07:00"
+
 var recordsCmd = &cobra.Command{
 	Use:   "records",
 	Short: "Show all current facility and booking records in memory",
 	Run: func(cmd *cobra.Command, args []string) {
 
-		singaporeTimeZone := time.FixedZone("UTC+8", 8*60*60)
+		// Times are shown in the timezone of each facility, unless a timezone is given
+		var timezone *time.Location
+		if cmd.Flags().Changed(flagTimezone) {
+			loc, err := bookings.ParseTimezone(recordsTimezone)
+			if err != nil {
+				_, _ = fmt.Fprintf(cmd.OutOrStdout(), "Invalid %s: %v", flagTimezone, err)
+				return
+			}
+			timezone = loc
+		}
 
-		facilitiesTable := newTable().Headers("NAME", "NO. BOOKINGS")
-		bookingTable := newTable().Headers("FACILITY", "BOOKING ID", "START", "END")
+		facilitiesTable := newTable().Headers("NAME", "CAPACITY", "LOCATION", "TIMEZONE", "NO. BOOKINGS", "NO. SERIES", "NO. WAITING")
+		bookingTable := newTable().Headers("FACILITY", "BOOKING ID", "SERIES ID", "OWNER", "START", "END", "HELD UNTIL")
 
-		records := bookings.GetManager().GetDeepCopyOfRecords()
+		manager := getAttachedManager()
+		if manager == nil {
+			_, _ = fmt.Fprintf(cmd.OutOrStdout(), "No server attached to console")
+			return
+		}
+		records := manager.GetDeepCopyOfRecords()
 
 		for fName, f := range records {
-			facilitiesTable = facilitiesTable.Row(string(fName), fmt.Sprintf("%v", len(f.Bookings)))
+			facilitiesTable = facilitiesTable.Row(string(fName), strconv.Itoa(f.Capacity), f.Info.Location, f.Info.Timezone, fmt.Sprintf("%v", len(f.Bookings)), fmt.Sprintf("%v", len(f.Series)), fmt.Sprintf("%v", len(f.Waitlist)))
+
+			loc := timezone
+			if loc == nil {
+				var err error
+				if loc, err = bookings.ParseTimezone(f.Info.Timezone); err != nil {
+					loc = time.Local
+				}
+			}
 
 			for _, b := range f.Bookings {
+				seriesId := "-"
+				if b.SeriesId != 0 {
+					seriesId = strconv.Itoa(int(b.SeriesId))
+				}
+				owner := "-"
+				if b.Owner != "" {
+					owner = b.Owner
+				}
+				heldUntil := "-"
+				if b.IsHeld() {
+					heldUntil = b.HeldUntil.In(loc).Format(recordsTimeFormat)
+				}
 				bookingTable = bookingTable.Row(
 					string(fName),
-					strconv.Itoa(int(b.Id)),
-					b.Start.In(singaporeTimeZone).Format("2006-01-02 15:04:05"),
-					b.End.In(singaporeTimeZone).Format("2006-01-02 15:04:05"),
+					strconv.FormatUint(b.Id, 10),
+					seriesId,
+					owner,
+					b.Start.In(loc).Format(recordsTimeFormat),
+					b.End.In(loc).Format(recordsTimeFormat),
+					heldUntil,
 				)
 			}
 		}
@@ -201,6 +295,114 @@ var recordsCmd = &cobra.Command{
 	},
 }
 
+var auditCmd = &cobra.Command{
+	Use:   "audit",
+	Short: "Show the latest changes made to facilities and bookings, and who requested them",
+	Run: func(cmd *cobra.Command, args []string) {
+		manager := getAttachedManager()
+		if manager == nil {
+			_, _ = fmt.Fprintf(cmd.OutOrStdout(), "No server attached to console")
+			return
+		}
+
+		// Flags keep their values across commands, so only those given with this command are used
+		var filter bookings.AuditFilter
+		if cmd.Flags().Changed(flagFacility) {
+			filter.Facility = bookings.FacilityName(auditFacility)
+		}
+		if cmd.Flags().Changed(flagBookingId) {
+			filter.BookingId = auditBookingId
+		}
+		if cmd.Flags().Changed(flagSince) {
+			filter.From = time.Now().Add(-auditSince)
+		}
+		limit := 50
+		if cmd.Flags().Changed(flagLimit) {
+			limit = auditLimit
+		}
+
+		entries, err := manager.QueryAudit(filter, 0)
+		if err != nil {
+			_, _ = fmt.Fprintf(cmd.OutOrStdout(), "Unable to query audit log: %v", err)
+			return
+		}
+		if limit > 0 && len(entries) > limit {
+			entries = entries[len(entries)-limit:]
+		}
+
+		t := newTable().Headers("SEQ", "TIME", "TYPE", "FACILITY", "BOOKING ID", "ACTOR", "ADDRESS", "REQUEST ID", "BEFORE", "AFTER")
+		for _, e := range entries {
+			actor := "-"
+			switch {
+			case e.Actor != "":
+				actor = e.Actor
+			case e.Admin:
+				actor = "(system)"
+			}
+			bookingId := "-"
+			if e.BookingId != 0 {
+				bookingId = strconv.FormatUint(e.BookingId, 10)
+			}
+			t = t.Row(
+				strconv.FormatUint(e.Seq, 10),
+				e.Time.Format(recordsTimeFormat),
+				e.Type.String(),
+				string(e.Facility),
+				bookingId,
+				actor,
+				orDash(e.Address),
+				orDash(e.RequestId),
+				formatAuditState(e.Before),
+				formatAuditState(e.After),
+			)
+		}
+
+		_, _ = fmt.Fprintf(cmd.OutOrStdout(), t.String())
+	},
+}
+
+// formatAuditState shows the state of a booking or facility of an audit entry.
+func formatAuditState(s *bookings.AuditState) string {
+	switch {
+	case s == nil:
+		return "-"
+	case s.Start.IsZero() && s.Info != nil:
+		return fmt.Sprintf("capacity %d, %s", s.Capacity, formatFacilityInfo(*s.Info))
+	case s.Start.IsZero():
+		return fmt.Sprintf("capacity %d", s.Capacity)
+	}
+	return s.Start.Format(time.DateTime) + " to " + s.End.Format(time.DateTime)
+}
+
+// formatFacilityInfo shows the timezone and opening hours of a facility.
+func formatFacilityInfo(info bookings.FacilityInfo) string {
+	timezone := info.Timezone
+	if timezone == "" {
+		timezone = "server timezone"
+	}
+	if len(info.Hours) == 0 {
+		return timezone + ", open all day"
+	}
+
+	hours := make([]string, len(info.Hours))
+	for day, h := range info.Hours {
+		hours[day] = fmt.Sprintf("%s %s-%s", time.Weekday(day).String()[:3], formatTimeOfDay(h.Open), formatTimeOfDay(h.Close))
+	}
+	return timezone + ", " + strings.Join(hours, " ")
+}
+
+// formatTimeOfDay shows a duration after midnight as a time of day, e.g. 09:30.
+func formatTimeOfDay(d time.Duration) string {
+	return fmt.Sprintf("%02d:%02d", int(d/time.Hour), int(d%time.Hour/time.Minute))
+}
+
+func orDash(s string) string {
+	if s == "" {
+		return "-"
+	}
+	return s
+}
+
 var networkCmd = &cobra.Command{
 	Use:   "network",
 	Short: "Show network statistics",
@@ -218,16 +420,18 @@ var networkCmd = &cobra.Command{
 		}
 
 		t := newTable().
-			Headers("DIRECTION", "EXPECTED", "DROPPED").
+			Headers("DIRECTION", "EXPECTED", "DROPPED", "OVERFLOW").
 			Row(
 				"IN",
 				strconv.Itoa(stats.packetInExpected),
 				fmt.Sprintf("%d\t(%.2f PERCENT)", stats.packetInDropped, inDropPercentage),
+				strconv.Itoa(stats.packetInOverflow),
 			).
 			Row(
 				"OUT",
 				strconv.Itoa(stats.packetOutExpected),
 				fmt.Sprintf("%d\t(%.2f PERCENT)", stats.packetOutDropped, outDropPercentage),
+				"-",
 			)
 		_, _ = fmt.Fprintf(cmd.OutOrStdout(), t.String())
 	},
@@ -249,10 +453,19 @@ var envShowCmd = &cobra.Command{
 	Run: func(cmd *cobra.Command, args []string) {
 
 		envVars := vars.GetStaticEnvCopy()
+		methodRates, methodBursts := envVars.RateLimitMethods()
+
+		// The admin principal acts as a shared secret and is not shown
+		adminPrincipal := "(unset)"
+		if envVars.AdminPrincipal != "" {
+			adminPrincipal = "(set)"
+		}
 
 		t := newTable()
 		t = t.Headers("ENV VAR", "VALUE")
 		t = t.Rows([][]string{
+			{"ServerBindAddresses", fmt.Sprintf("%v", envVars.ServerBindAddresses)},
+			{"ServerSockets", fmt.Sprintf("%v", envVars.ServerSockets)},
 			{"EnableDuplicateFiltering", fmt.Sprintf("%v", envVars.EnableDuplicateFiltering)},
 			{"PacketDropRate", fmt.Sprintf("%v", envVars.PacketDropRate)},
 			{"PacketReceiveTimeout", fmt.Sprintf("%v", envVars.PacketReceiveTimeout)},
@@ -260,6 +473,22 @@ var envShowCmd = &cobra.Command{
 			{"MessageAssemblerIntervals", fmt.Sprintf("%v", envVars.MessageAssemblerIntervals)},
 			{"ResponseTTL", fmt.Sprintf("%v", envVars.ResponseTTL)},
 			{"ResponseIntervals", fmt.Sprintf("%v", envVars.ResponseIntervals)},
+			{"ShutdownGracePeriod", fmt.Sprintf("%v", envVars.ShutdownGracePeriod)},
+			{"DataDir", envVars.DataDir},
+			{"SnapshotInterval", fmt.Sprintf("%v", envVars.SnapshotInterval)},
+			{"PersistResponses", fmt.Sprintf("%v", envVars.PersistResponses)},
+			{"WorkerPoolSize", fmt.Sprintf("%v", envVars.WorkerPoolSize)},
+			{"WorkerQueueSize", fmt.Sprintf("%v", envVars.WorkerQueueSize)},
+			{"WorkerFairQueuing", fmt.Sprintf("%v", envVars.WorkerFairQueuing)},
+			{"WorkerClientQueueSize", fmt.Sprintf("%v", envVars.WorkerClientQueueSize)},
+			{"RateLimitRate", fmt.Sprintf("%v", envVars.RateLimitRate)},
+			{"RateLimitBurst", fmt.Sprintf("%v", envVars.RateLimitBurst)},
+			{"RateLimitMethodRates", fmt.Sprintf("%v", methodRates)},
+			{"RateLimitMethodBursts", fmt.Sprintf("%v", methodBursts)},
+			{"BookingSlotMinutes", fmt.Sprintf("%v", envVars.BookingSlotMinutes)},
+			{"AdminPrincipal", adminPrincipal},
+			{"HoldTTL", fmt.Sprintf("%v", envVars.HoldTTL)},
+			{"IdempotencyTTL", fmt.Sprintf("%v", envVars.IdempotencyTTL)},
 		}...)
 
 		_, err := fmt.Fprintf(cmd.OutOrStdout(), t.String())
@@ -346,6 +575,53 @@ var envSetCmd = &cobra.Command{
 				if err := vars.SetResponseIntervals(val); err != nil {
 					sendErrToBuffer(err)
 				}
+			case "shutdown-grace-period":
+				val, err := strconv.Atoi(f.Value.String())
+				if err != nil {
+					sendErrToBuffer(err)
+				}
+				if err := vars.SetShutdownGracePeriod(val); err != nil {
+					sendErrToBuffer(err)
+				}
+			case "rate-limit-rate":
+				if err := vars.SetRateLimitRate(envRateLimitRate); err != nil {
+					sendErrToBuffer(err)
+				}
+			case "rate-limit-burst":
+				if err := vars.SetRateLimitBurst(envRateLimitBurst); err != nil {
+					sendErrToBuffer(err)
+				}
+			case "rate-limit-method":
+				method, rate, burst, err := parseRateLimitMethod(envRateLimitMethod)
+				if err != nil {
+					sendErrToBuffer(err)
+					return
+				}
+				if err := vars.SetRateLimitMethod(method, rate, burst); err != nil {
+					sendErrToBuffer(err)
+				}
+			case "clear-rate-limit-method":
+				if _, err := request.ParseMethodIdentifier(envClearRateLimitMethod); err != nil {
+					sendErrToBuffer(err)
+					return
+				}
+				if err := vars.ClearRateLimitMethod(envClearRateLimitMethod); err != nil {
+					sendErrToBuffer(err)
+				}
+			case "booking-slot-minutes":
+				if err := vars.SetBookingSlotMinutes(envBookingSlotMinutes); err != nil {
+					sendErrToBuffer(err)
+				}
+			case "admin-principal":
+				vars.SetAdminPrincipal(envAdminPrincipal)
+			case "hold-ttl":
+				if err := vars.SetHoldTTL(envHoldTTL); err != nil {
+					sendErrToBuffer(err)
+				}
+			case "idempotency-ttl":
+				if err := vars.SetIdempotencyTTL(envIdempotencyTTL); err != nil {
+					sendErrToBuffer(err)
+				}
 			default:
 				sendErrToBuffer(fmt.Errorf("%s flag not supposed by envSetCmd", f.Name))
 			}
@@ -353,6 +629,32 @@ var envSetCmd = &cobra.Command{
 	},
 }
 
+// parseRateLimitMethod parses a method's rate limit in the form <method>=<rate>:<burst>, e.g. "BookingMake=5:10".
+func parseRateLimitMethod(val string) (string, float64, int, error) {
+	method, limit, ok := strings.Cut(val, "=")
+	if !ok {
+		return "", 0, 0, fmt.Errorf("rate limit must be in the form <method>=<rate>:<burst>")
+	}
+	if _, err := request.ParseMethodIdentifier(method); err != nil {
+		return "", 0, 0, err
+	}
+
+	rateStr, burstStr, ok := strings.Cut(limit, ":")
+	if !ok {
+		return "", 0, 0, fmt.Errorf("rate limit must be in the form <method>=<rate>:<burst>")
+	}
+	rate, err := strconv.ParseFloat(rateStr, 64)
+	if err != nil {
+		return "", 0, 0, err
+	}
+	burst, err := strconv.Atoi(burstStr)
+	if err != nil {
+		return "", 0, 0, err
+	}
+
+	return method, rate, burst, nil
+}
+
 var resetRootCmd = &cobra.Command{
 	Use:   "reset [domain]",
 	Short: "Resets the specified domain",
@@ -367,7 +669,9 @@ var resetAllCmd = &cobra.Command{
 	Use:   "all",
 	Short: "Resets bookings, facilities, and network stats",
 	Run: func(cmd *cobra.Command, args []string) {
-		bookings.GetMonitor().Reset()
+		if manager := getAttachedManager(); manager != nil {
+			manager.Reset()
+		}
 		resetNetworkMonitor()
 	},
 }
@@ -376,7 +680,9 @@ var resetRecordsCmd = &cobra.Command{
 	Use:   "records",
 	Short: "Resets bookings and facilities",
 	Run: func(cmd *cobra.Command, args []string) {
-		bookings.GetMonitor().Reset()
+		if manager := getAttachedManager(); manager != nil {
+			manager.Reset()
+		}
 	},
 }
 
@@ -396,3 +702,74 @@ var nukeRootCmd = &cobra.Command{
 		os.Exit(1)
 	},
 }
+
+var snapshotRootCmd = &cobra.Command{
+	Use:   "snapshot [action]",
+	Short: "Takes and inspects snapshots of bookings and facilities",
+	Run: func(cmd *cobra.Command, args []string) {
+		if err := cmd.Help(); err != nil {
+			return
+		}
+	},
+}
+
+var snapshotTakeCmd = &cobra.Command{
+	Use:   "take",
+	Short: "Takes a snapshot of bookings and facilities, truncating the write-ahead log",
+	Run: func(cmd *cobra.Command, args []string) {
+		manager := getAttachedManager()
+		if manager == nil || getAttachedStore() == nil {
+			_, _ = fmt.Fprintf(cmd.OutOrStdout(), "Persistence is disabled, set DATA_DIR to enable it")
+			return
+		}
+		if err := manager.Snapshot(); err != nil {
+			_, _ = fmt.Fprintf(cmd.OutOrStdout(), "Unable to take snapshot: %v", err)
+			return
+		}
+		_, _ = fmt.Fprintf(cmd.OutOrStdout(), "Snapshot has been taken")
+	},
+}
+
+var snapshotInfoCmd = &cobra.Command{
+	Use:   "info",
+	Short: "Prints the state of the latest snapshot and the write-ahead log",
+	Run: func(cmd *cobra.Command, args []string) {
+		st := getAttachedStore()
+		if st == nil {
+			_, _ = fmt.Fprintf(cmd.OutOrStdout(), "Persistence is disabled, set DATA_DIR to enable it")
+			return
+		}
+
+		info := st.Info()
+		snapshotTime := "-"
+		if !info.SnapshotTime.IsZero() {
+			snapshotTime = info.SnapshotTime.Format(time.DateTime)
+		}
+
+		t := newTable()
+		t = t.Headers("PROPERTY", "VALUE")
+		t = t.Rows([][]string{
+			{"DataDir", info.Dir},
+			{"LastSequence", fmt.Sprintf("%d", info.Seq)},
+			{"SnapshotSequence", fmt.Sprintf("%d", info.SnapshotSeq)},
+			{"SnapshotTime", snapshotTime},
+			{"WalRecords", fmt.Sprintf("%d", info.WalRecords)},
+			{"WalBytes", fmt.Sprintf("%d", info.WalBytes)},
+		}...)
+
+		_, _ = fmt.Fprintf(cmd.OutOrStdout(), t.String())
+	},
+}
+
+var shutdownRootCmd = &cobra.Command{
+	Use:   "shutdown",
+	Short: "Gracefully shuts down the server, draining requests and flushing responses before exiting",
+	Run: func(cmd *cobra.Command, args []string) {
+		if !triggerShutdown() {
+			_, _ = fmt.Fprintf(cmd.OutOrStdout(), "No shutdown handler registered")
+			return
+		}
+		slog.Warn("Graceful shutdown triggered from `handle_client_cmd.shutdownRootCmd`")
+		_, _ = fmt.Fprintf(cmd.OutOrStdout(), "Shutting down server")
+	},
+}
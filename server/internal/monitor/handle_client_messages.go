@@ -1,6 +1,7 @@
 package monitor
 
 import (
+	"errors"
 	"fmt"
 	"io"
 	"log"
@@ -21,6 +22,9 @@ func HandleClientMessages(c *net.TCPConn) {
 				log.Printf("[CLIENT] Connection from %s closed by the client", c.RemoteAddr().String())
 				return // Exit the loop (and function) if the connection is closed.
 			}
+			if errors.Is(err, net.ErrClosed) {
+				return // Connection was closed by the server (e.g. on shutdown)
+			}
 			// Handle other potential errors.
 			log.Printf("[CLIENT] Error reading from %s: %v", c.RemoteAddr().String(), err)
 			continue
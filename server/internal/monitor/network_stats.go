@@ -9,6 +9,7 @@ type networkStats struct {
 	mu                sync.RWMutex
 	packetInExpected  int // Total number of packets that the server actual receives
 	packetInDropped   int // Number of inbound packets that have been dropped
+	packetInOverflow  int // Number of inbound packets that have been dropped due to the worker queue being full
 	packetOutExpected int // Total number of packets that the server supposed to send out
 	packetOutDropped  int // Number of outbound packets that have been dropped
 }
@@ -24,6 +25,7 @@ func init() {
 			mu:                sync.RWMutex{},
 			packetInExpected:  0,
 			packetInDropped:   0,
+			packetInOverflow:  0,
 			packetOutExpected: 0,
 			packetOutDropped:  0,
 		}
@@ -42,6 +44,12 @@ func MarkPacketInDropped() {
 	n.packetInDropped++
 }
 
+func MarkPacketInOverflow() {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	n.packetInOverflow++
+}
+
 func MarkPacketOut() {
 	n.mu.Lock()
 	defer n.mu.Unlock()
@@ -60,6 +68,7 @@ func resetNetworkMonitor() {
 	slog.Warn("Resetting network monitor")
 	n.packetInExpected = 0
 	n.packetInDropped = 0
+	n.packetInOverflow = 0
 	n.packetOutExpected = 0
 	n.packetOutDropped = 0
 }
@@ -69,6 +78,7 @@ func getNetworkStats() networkStats {
 		mu:                sync.RWMutex{},
 		packetInExpected:  n.packetInExpected,
 		packetInDropped:   n.packetInDropped,
+		packetInOverflow:  n.packetInOverflow,
 		packetOutExpected: n.packetOutExpected,
 		packetOutDropped:  n.packetOutDropped,
 	}
@@ -1,12 +1,16 @@
 package monitor
 
 import (
+	"context"
+	"errors"
 	"fmt"
 	"log/slog"
 	"net"
 )
 
-func Serve(port int) {
+// Serve runs the logging server on the given port until ctx is cancelled, after which all connected clients are
+// disconnected.
+func Serve(ctx context.Context, port int) {
 
 	// Create server
 	ln, err := net.Listen("tcp", fmt.Sprintf(":%d", port))
@@ -18,10 +22,19 @@ func Serve(port int) {
 	handler := GetConnectionHandler()
 	defer handler.CloseAndRemoveClients()
 
+	// Stop accepting new connections once ctx is cancelled
+	go func() {
+		<-ctx.Done()
+		_ = ln.Close()
+	}()
+
 	go func() {
 		for {
 			conn, err := ln.Accept()
 			if err != nil {
+				if errors.Is(err, net.ErrClosed) {
+					return
+				}
 				slog.Error("Unable to accept new logging connection", "err", err)
 				continue
 			}
@@ -31,9 +44,14 @@ func Serve(port int) {
 		}
 	}()
 
-	for m := range GetMessageQueue() {
-		handler.SendMessage(m)
-		SendToMatterMost(m)
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case m := <-GetMessageQueue():
+			handler.SendMessage(m)
+			SendToMatterMost(m)
+		}
 	}
 
 }
@@ -0,0 +1,14 @@
+package network
+
+import (
+	"net"
+	"net/netip"
+)
+
+// AddrKey returns a canonical string for a, for use as a map key. IPv4 addresses received on a dual-stack socket are
+// IPv4-mapped IPv6 addresses (::ffff:a.b.c.d); these are unmapped so that a client has the same key regardless of the
+// socket it was received on.
+func AddrKey(a *net.UDPAddr) string {
+	ap := a.AddrPort()
+	return netip.AddrPortFrom(ap.Addr().Unmap(), ap.Port()).String()
+}
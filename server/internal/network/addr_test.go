@@ -0,0 +1,24 @@
+package network
+
+import (
+	"net"
+	"testing"
+)
+
+func TestAddrKey(t *testing.T) {
+	tests := []struct {
+		addr     *net.UDPAddr
+		expected string
+	}{
+		{&net.UDPAddr{IP: net.ParseIP("127.0.0.1").To4(), Port: 80}, "127.0.0.1:80"},
+		{&net.UDPAddr{IP: net.ParseIP("::ffff:127.0.0.1"), Port: 80}, "127.0.0.1:80"},
+		{&net.UDPAddr{IP: net.ParseIP("::1"), Port: 80}, "[::1]:80"},
+		{&net.UDPAddr{IP: net.ParseIP("fe80::1"), Port: 80, Zone: "eth0"}, "[fe80::1%eth0]:80"},
+	}
+
+	for _, tt := range tests {
+		if key := AddrKey(tt.addr); key != tt.expected {
+			t.Errorf("E: %s, R: %s", tt.expected, key)
+		}
+	}
+}
@@ -1,6 +1,7 @@
 package network
 
 import (
+	"context"
 	"errors"
 	"log/slog"
 	"net"
@@ -30,10 +31,10 @@ func NewSendHistoryRecord(c *net.UDPConn, a *net.UDPAddr, p *protocol.Packet) *S
 	}
 }
 
-func (s *SendHistoryRecord) ResendPacket() {
+func (s *SendHistoryRecord) ResendPacket(h *SendHistory) {
 	packet := s.GetPacket()
 	slog.Info("Resending packet", "Type", packet.Header.MessageType, "Id", packet.Header.MessageId)
-	if err := SendPacket(s.Conn, s.Addr, packet); err != nil {
+	if err := h.SendPacket(s.Conn, s.Addr, packet); err != nil {
 		slog.Error("Unable to resend historical packet", "err", err)
 	}
 }
@@ -45,43 +46,46 @@ func (s *SendHistoryRecord) GetPacket() *protocol.Packet {
 	return s.Packet
 }
 
-func (s *SendHistoryRecord) GetTime() *time.Time {
+// GetTime returns a copy of the time the packet was last sent, since the record may be updated concurrently by a resend.
+func (s *SendHistoryRecord) GetTime() time.Time {
 	s.RLock()
 	defer s.RUnlock()
-	return &s.Updated
+	return s.Updated
 }
 
-func (s *SendHistoryRecord) GetCreateTime() *time.Time {
+func (s *SendHistoryRecord) GetCreateTime() time.Time {
 	s.RLock()
 	defer s.RUnlock()
-	return &s.Created
+	return s.Created
 }
 
 // SendHistory is responsible for keeping track of all previously sent messages that require acknowledgement.
 type SendHistory struct {
 	sync.RWMutex
+	env      *vars.StaticEnvStruct
 	messages map[protocol.PacketIdent]*SendHistoryRecord
 }
 
-var instance *SendHistory
-var once sync.Once
+func NewSendHistory(env *vars.StaticEnvStruct) *SendHistory {
+	return &SendHistory{
+		env:      env,
+		messages: make(map[protocol.PacketIdent]*SendHistoryRecord),
+	}
+}
 
-func GetSendHistoryInstance() *SendHistory {
-	once.Do(func() {
-		instance = &SendHistory{
-			messages: make(map[protocol.PacketIdent]*SendHistoryRecord),
+// Run periodically resends outdated packets until ctx is cancelled.
+func (h *SendHistory) Run(ctx context.Context) {
+	t := time.NewTicker(time.Duration(h.env.PacketReceiveTimeout) * time.Millisecond)
+	defer t.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-t.C:
+			h.ResendUnAckPackets()
 		}
-
-		// Create ticker to request outdated packets
-		t := time.NewTicker(time.Duration(vars.GetStaticEnv().PacketReceiveTimeout) * time.Millisecond)
-		go func() {
-			defer t.Stop()
-			for range t.C {
-				instance.ResendUnAckPackets()
-			}
-		}()
-	})
-	return instance
+	}
 }
 
 func (h *SendHistory) ResendUnAckPackets() {
@@ -93,8 +97,8 @@ func (h *SendHistory) ResendUnAckPackets() {
 	}
 
 	slog.Debug("Resending unacknowledged packets")
-	historyCutoff := time.Now().Add(-time.Duration(vars.GetStaticEnv().PacketTTL) * time.Millisecond)
-	cutOffTime := time.Now().Add(-time.Duration(vars.GetStaticEnv().PacketReceiveTimeout) * time.Millisecond)
+	historyCutoff := time.Now().Add(-time.Duration(h.env.PacketTTL) * time.Millisecond)
+	cutOffTime := time.Now().Add(-time.Duration(h.env.PacketReceiveTimeout) * time.Millisecond)
 
 	for ident, p := range h.messages {
 
@@ -107,7 +111,7 @@ func (h *SendHistory) ResendUnAckPackets() {
 
 		// Check if update time has been more than timeout and requires an ack
 		if p.GetTime().Before(cutOffTime) && p.Packet.Header.Flags.AckRequired() {
-			go p.ResendPacket()
+			go p.ResendPacket(h)
 		}
 	}
 
@@ -140,3 +144,35 @@ func (h *SendHistory) Get(i protocol.PacketIdent) (*protocol.Packet, error) {
 		return p.GetPacket(), nil
 	}
 }
+
+// Flush blocks until all sent packets that require acknowledgement have been acknowledged or expired, or ctx is done.
+// Unacknowledged packets continue to be resent while Run is active.
+func (h *SendHistory) Flush(ctx context.Context) error {
+	t := time.NewTicker(time.Duration(h.env.PacketReceiveTimeout) * time.Millisecond)
+	defer t.Stop()
+
+	for {
+		if h.Pending() == 0 {
+			return nil
+		}
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-t.C:
+		}
+	}
+}
+
+// Pending returns the number of sent packets that are still waiting for an acknowledgement.
+func (h *SendHistory) Pending() int {
+	h.RLock()
+	defer h.RUnlock()
+
+	count := 0
+	for _, p := range h.messages {
+		if p.Packet.Header.Flags.AckRequired() {
+			count++
+		}
+	}
+	return count
+}
@@ -8,15 +8,15 @@ import (
 	"server/internal/protocol"
 )
 
-// SendPacket is responsible for sending the packet to the given address.
-func SendPacket(c *net.UDPConn, a *net.UDPAddr, p *protocol.Packet) error {
+// SendPacket is responsible for sending the packet to the given address, and recording it in the SendHistory.
+func (h *SendHistory) SendPacket(c *net.UDPConn, a *net.UDPAddr, p *protocol.Packet) error {
 
 	monitor.MarkPacketOut()
 
-	GetSendHistoryInstance().Append(c, a, p)
+	h.Append(c, a, p)
 
 	// Chance event to drop sent packet
-	if chance.DropPacket() {
+	if chance.DropPacket(h.env.PacketDropRate) {
 		monitor.MarkPacketOutDropped()
 		slog.Info("[OUT:DROPPED] Dropping packet, simulated network error", "target", a.String(), "packet_type", p.Header.MessageType)
 		return nil
@@ -0,0 +1,148 @@
+package pools
+
+import (
+	"sync"
+)
+
+// WorkerPool executes jobs on a fixed number of workers, with a bounded queue of pending jobs.
+//
+// Jobs are submitted with a key (e.g. the client's address). When fair queuing is enabled, each key has its own
+// bounded queue and workers take jobs from the queues in a round-robin fashion, so that a single noisy key cannot
+// starve the others. Otherwise, all jobs share a single FIFO queue.
+type WorkerPool struct {
+	mu   sync.Mutex
+	cond *sync.Cond
+	wg   sync.WaitGroup
+
+	fair        bool
+	queueSize   int // Maximum number of jobs queued in total
+	clientLimit int // Maximum number of jobs queued per key, only applicable with fair queuing
+
+	queues map[string][]func()
+	order  []string // Round-robin order of keys with pending jobs
+	size   int      // Number of jobs currently queued
+	closed bool
+}
+
+type WorkerPoolOption func(*WorkerPool)
+
+// WorkerPoolWithFairQueuing enables per-key fair queuing, with at most clientLimit jobs queued per key.
+func WorkerPoolWithFairQueuing(clientLimit int) WorkerPoolOption {
+	return func(p *WorkerPool) {
+		p.fair = true
+		p.clientLimit = clientLimit
+	}
+}
+
+// NewWorkerPool creates a WorkerPool and starts its workers. The number of workers, the queue size and the queue size
+// per key are at least 1, as a pool without workers or queues would drop every job.
+func NewWorkerPool(workers int, queueSize int, opts ...WorkerPoolOption) *WorkerPool {
+	p := &WorkerPool{
+		queueSize: queueSize,
+		queues:    make(map[string][]func()),
+	}
+	p.cond = sync.NewCond(&p.mu)
+	for _, o := range opts {
+		o(p)
+	}
+
+	if workers < 1 {
+		workers = 1
+	}
+	p.queueSize = max(p.queueSize, 1)
+	p.clientLimit = max(p.clientLimit, 1)
+
+	p.wg.Add(workers)
+	for i := 0; i < workers; i++ {
+		go p.work()
+	}
+
+	return p
+}
+
+// Submit queues job to be executed by a worker.
+// Returns false if the job was dropped, due to the queue (or the key's queue) being full, or the pool being closed.
+func (p *WorkerPool) Submit(key string, job func()) bool {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if p.closed || p.size >= p.queueSize {
+		return false
+	}
+
+	if !p.fair {
+		key = ""
+	}
+
+	q, exists := p.queues[key]
+	if p.fair && len(q) >= p.clientLimit {
+		return false
+	}
+	if !exists || len(q) == 0 {
+		p.order = append(p.order, key)
+	}
+	p.queues[key] = append(q, job)
+	p.size++
+
+	p.cond.Signal()
+	return true
+}
+
+// Len returns the number of jobs waiting to be executed.
+func (p *WorkerPool) Len() int {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return p.size
+}
+
+// Close stops the workers once their current jobs are completed. Jobs that are still queued are discarded.
+func (p *WorkerPool) Close() {
+	p.mu.Lock()
+	p.closed = true
+	p.queues = make(map[string][]func())
+	p.order = nil
+	p.size = 0
+	p.cond.Broadcast()
+	p.mu.Unlock()
+
+	p.wg.Wait()
+}
+
+func (p *WorkerPool) work() {
+	defer p.wg.Done()
+
+	for {
+		p.mu.Lock()
+		for p.size == 0 && !p.closed {
+			p.cond.Wait()
+		}
+		if p.closed {
+			p.mu.Unlock()
+			return
+		}
+		job := p.next()
+		p.mu.Unlock()
+
+		job()
+	}
+}
+
+// next pops the next job in round-robin order. Must be called with the lock held, and at least one job queued.
+func (p *WorkerPool) next() func() {
+	key := p.order[0]
+	p.order = p.order[1:]
+
+	q := p.queues[key]
+	job := q[0]
+	q[0] = nil // Allow job to be garbage collected
+
+	if len(q) > 1 {
+		p.queues[key] = q[1:]
+		p.order = append(p.order, key) // Key goes to the back of the line
+	} else {
+		delete(p.queues, key)
+	}
+	p.size--
+
+	return job
+}
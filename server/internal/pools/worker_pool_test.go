@@ -0,0 +1,99 @@
+package pools
+
+import (
+	"slices"
+	"sync"
+	"testing"
+)
+
+func TestWorkerPool_Submit_overflow(t *testing.T) {
+
+	// Block the only worker, so that jobs accumulate in the queue
+	release := make(chan struct{})
+	started := make(chan struct{})
+
+	p := NewWorkerPool(1, 2)
+	defer p.Close()
+
+	if !p.Submit("a", func() { close(started); <-release }) {
+		t.Fatal("Expected blocking job to be accepted")
+	}
+	<-started
+
+	if !p.Submit("a", func() {}) || !p.Submit("b", func() {}) {
+		t.Error("Expected jobs within queue size to be accepted")
+	}
+	if p.Submit("c", func() {}) {
+		t.Error("Expected job to be dropped when queue is full")
+	}
+	if p.Len() != 2 {
+		t.Errorf("Expected 2 queued jobs, got %d", p.Len())
+	}
+
+	close(release)
+}
+
+func TestWorkerPool_Submit_fairQueuing(t *testing.T) {
+
+	release := make(chan struct{})
+	started := make(chan struct{})
+
+	p := NewWorkerPool(1, 16, WorkerPoolWithFairQueuing(3))
+	defer p.Close()
+
+	p.Submit("blocker", func() { close(started); <-release })
+	<-started
+
+	mu := sync.Mutex{}
+	var order []string
+	done := sync.WaitGroup{}
+
+	submit := func(key string) bool {
+		done.Add(1)
+		ok := p.Submit(key, func() {
+			defer done.Done()
+			mu.Lock()
+			order = append(order, key)
+			mu.Unlock()
+		})
+		if !ok {
+			done.Done()
+		}
+		return ok
+	}
+
+	// Noisy client is capped at its own limit, without affecting other clients
+	for i := 0; i < 5; i++ {
+		submit("noisy")
+	}
+	if !submit("quiet") {
+		t.Error("Expected quiet client's job to be accepted")
+	}
+	if p.Len() != 4 {
+		t.Errorf("Expected 4 queued jobs, got %d", p.Len())
+	}
+
+	close(release)
+	done.Wait()
+
+	expected := []string{"noisy", "quiet", "noisy", "noisy"}
+	if !slices.Equal(order, expected) {
+		t.Logf("E: %v", expected)
+		t.Logf("R: %v", order)
+		t.Error("Jobs were not executed in round-robin order")
+	}
+}
+
+func TestWorkerPool_Submit_emptyQueue(t *testing.T) {
+
+	// Queue sizes below 1 would otherwise drop every job
+	for _, p := range []*WorkerPool{NewWorkerPool(0, 0), NewWorkerPool(1, 16, WorkerPoolWithFairQueuing(0))} {
+		done := make(chan struct{})
+		if !p.Submit("a", func() { close(done) }) {
+			t.Error("Expected job to be accepted")
+		} else {
+			<-done
+		}
+		p.Close()
+	}
+}
@@ -0,0 +1,137 @@
+package ratelimit
+
+import (
+	"math"
+	"server/internal/vars"
+	"sync"
+	"time"
+)
+
+// pruneInterval is the minimum time between removals of idle buckets.
+const pruneInterval = time.Minute
+
+// bucket is a token bucket, refilled at a constant rate up to its burst size. Each request consumes one token.
+type bucket struct {
+	tokens float64
+	last   time.Time
+}
+
+// refill adds the tokens accumulated since the bucket was last updated.
+func (b *bucket) refill(now time.Time, rate float64, burst int) {
+	b.tokens = math.Min(float64(burst), b.tokens+now.Sub(b.last).Seconds()*rate)
+	b.last = now
+}
+
+// wait returns the time until the bucket has a token available.
+func (b *bucket) wait(rate float64) time.Duration {
+	if b.tokens >= 1 {
+		return 0
+	}
+	return time.Duration((1 - b.tokens) / rate * float64(time.Second))
+}
+
+type bucketKey struct {
+	client string
+	method string // Empty for the client's global bucket
+}
+
+// Limiter rate limits requests per client with token buckets. Every client has a global bucket shared by all methods,
+// as well as a bucket per method that has its own limit configured. A request may be charged to several clients (e.g.
+// its address and its principal), and is only allowed if all of their buckets have a token available.
+//
+// Limits are read from the configuration on every request, so that changes (e.g. through the console) apply
+// immediately.
+type Limiter struct {
+	mu      sync.Mutex
+	env     *vars.StaticEnvStruct
+	buckets map[bucketKey]*bucket
+	pruned  time.Time
+	now     func() time.Time
+}
+
+func NewLimiter(env *vars.StaticEnvStruct) *Limiter {
+	return &Limiter{
+		env:     env,
+		buckets: make(map[bucketKey]*bucket),
+		pruned:  time.Now(),
+		now:     time.Now,
+	}
+}
+
+// Allow consumes a token for a request to method from each of clients. If the request is not allowed, the time until
+// it would be allowed is returned.
+func (l *Limiter) Allow(method string, clients ...string) (bool, time.Duration) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	now := l.now()
+	l.prune(now)
+
+	type limited struct {
+		b     *bucket
+		rate  float64
+		burst int
+	}
+	var applicable []limited
+
+	for _, client := range clients {
+		if rate, burst := l.env.RateLimitRate, l.env.RateLimitBurst; rate > 0 {
+			applicable = append(applicable, limited{l.get(bucketKey{client: client}, now, burst), rate, burst})
+		}
+		if rate, burst, ok := l.env.RateLimitMethod(method); ok && rate > 0 {
+			applicable = append(applicable, limited{l.get(bucketKey{client: client, method: method}, now, burst), rate, burst})
+		}
+	}
+
+	// Tokens are only consumed if the request is allowed by every bucket
+	var retryAfter time.Duration
+	for _, a := range applicable {
+		a.b.refill(now, a.rate, a.burst)
+		retryAfter = max(retryAfter, a.b.wait(a.rate))
+	}
+	if retryAfter > 0 {
+		return false, retryAfter
+	}
+	for _, a := range applicable {
+		a.b.tokens--
+	}
+
+	return true, 0
+}
+
+// Reset removes all buckets, allowing every client to burst again.
+func (l *Limiter) Reset() {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.buckets = make(map[bucketKey]*bucket)
+}
+
+// get returns the bucket for key, creating a full bucket if it does not exist. Must be called with the lock held.
+func (l *Limiter) get(key bucketKey, now time.Time, burst int) *bucket {
+	b, exists := l.buckets[key]
+	if !exists {
+		b = &bucket{tokens: float64(burst), last: now}
+		l.buckets[key] = b
+	}
+	return b
+}
+
+// prune removes buckets that would have been refilled by now, as they are equivalent to new buckets.
+// Must be called with the lock held.
+func (l *Limiter) prune(now time.Time) {
+	if now.Sub(l.pruned) < pruneInterval {
+		return
+	}
+	l.pruned = now
+
+	for key, b := range l.buckets {
+		rate := l.env.RateLimitRate
+		burst := l.env.RateLimitBurst
+		if key.method != "" {
+			rate, burst, _ = l.env.RateLimitMethod(key.method)
+		}
+		if rate <= 0 || b.tokens+now.Sub(b.last).Seconds()*rate >= float64(burst) {
+			delete(l.buckets, key)
+		}
+	}
+}
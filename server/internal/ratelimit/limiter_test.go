@@ -0,0 +1,135 @@
+package ratelimit
+
+import (
+	"server/internal/vars"
+	"testing"
+	"time"
+)
+
+func newTestLimiter(env *vars.StaticEnvStruct) (*Limiter, *time.Time) {
+	now := time.Now()
+	l := NewLimiter(env)
+	l.now = func() time.Time { return now }
+	return l, &now
+}
+
+func TestLimiter_Allow_burstAndRefill(t *testing.T) {
+	env := vars.GetStaticEnvCopy()
+	env.RateLimitRate = 2
+	env.RateLimitBurst = 3
+
+	l, now := newTestLimiter(&env)
+
+	for i := 0; i < 3; i++ {
+		if ok, _ := l.Allow("FacilityCreate", "a"); !ok {
+			t.Fatalf("Expected request %d within burst to be allowed", i)
+		}
+	}
+
+	ok, retryAfter := l.Allow("FacilityCreate", "a")
+	if ok {
+		t.Fatal("Expected request beyond burst to be limited")
+	}
+	if retryAfter != 500*time.Millisecond {
+		t.Errorf("Expected retry after 500ms, got %v", retryAfter)
+	}
+
+	// Other clients have their own buckets
+	if ok, _ := l.Allow("FacilityCreate", "b"); !ok {
+		t.Error("Expected request from another client to be allowed")
+	}
+
+	*now = now.Add(retryAfter)
+	if ok, _ := l.Allow("FacilityCreate", "a"); !ok {
+		t.Error("Expected request to be allowed after retry after has passed")
+	}
+}
+
+func TestLimiter_Allow_perMethod(t *testing.T) {
+	env := vars.GetStaticEnvCopy()
+	env.RateLimitRate = 0 // Only the method is limited
+	env.RateLimitMethodRates = map[string]float64{"BookingMake": 1}
+	env.RateLimitMethodBursts = map[string]int{"BookingMake": 1}
+
+	l, _ := newTestLimiter(&env)
+
+	if ok, _ := l.Allow("BookingMake", "a"); !ok {
+		t.Fatal("Expected first request to be allowed")
+	}
+	if ok, retryAfter := l.Allow("BookingMake", "a"); ok || retryAfter != time.Second {
+		t.Errorf("Expected second request to be limited for 1s, got %v, %v", ok, retryAfter)
+	}
+
+	for i := 0; i < 100; i++ {
+		if ok, _ := l.Allow("FacilityQuery", "a"); !ok {
+			t.Fatal("Expected methods without limits to be allowed")
+		}
+	}
+}
+
+func TestLimiter_Allow_limitedDoesNotConsume(t *testing.T) {
+	env := vars.GetStaticEnvCopy()
+	env.RateLimitRate = 1
+	env.RateLimitBurst = 2
+	env.RateLimitMethodRates = map[string]float64{"BookingMake": 1}
+	env.RateLimitMethodBursts = map[string]int{"BookingMake": 1}
+
+	l, _ := newTestLimiter(&env)
+
+	l.Allow("BookingMake", "a")
+	if ok, _ := l.Allow("BookingMake", "a"); ok {
+		t.Fatal("Expected method limit to be reached")
+	}
+
+	// The global bucket must not have been charged for the limited request
+	if ok, _ := l.Allow("FacilityQuery", "a"); !ok {
+		t.Error("Expected global bucket to have a token left")
+	}
+}
+
+func TestLimiter_Allow_severalClients(t *testing.T) {
+	env := vars.GetStaticEnvCopy()
+	env.RateLimitRate = 1
+	env.RateLimitBurst = 1
+
+	l, _ := newTestLimiter(&env)
+
+	if ok, _ := l.Allow("FacilityCreate", "a", "alice"); !ok {
+		t.Fatal("Expected first request to be allowed")
+	}
+
+	// Every client the request is charged to must have a token available
+	if ok, _ := l.Allow("FacilityCreate", "a", "bob"); ok {
+		t.Error("Expected request from the same address to be limited")
+	}
+	if ok, _ := l.Allow("FacilityCreate", "b", "alice"); ok {
+		t.Error("Expected request from the same principal to be limited")
+	}
+
+	// Limited requests do not consume tokens of the other clients
+	if ok, _ := l.Allow("FacilityCreate", "b", "bob"); !ok {
+		t.Error("Expected request from other clients to be allowed")
+	}
+}
+
+func TestLimiter_Allow_concurrentMethodChanges(t *testing.T) {
+	l := NewLimiter(vars.GetStaticEnv())
+	defer func() { _ = vars.ClearRateLimitMethod("BookingMake") }()
+
+	// Run with -race: methods are limited while their limits are changed through the console
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		for i := 0; i < 100; i++ {
+			if err := vars.SetRateLimitMethod("BookingMake", float64(i+1), 1); err != nil {
+				t.Error(err)
+				return
+			}
+		}
+	}()
+	for i := 0; i < 100; i++ {
+		l.Allow("BookingMake", "a")
+		l.prune(time.Now())
+	}
+	<-done
+}
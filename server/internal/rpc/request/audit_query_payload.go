@@ -0,0 +1,61 @@
+package request
+
+import (
+	"encoding/binary"
+	"fmt"
+	"server/internal/bookings"
+	"time"
+)
+
+// AuditQueryPayload selects entries of the audit log (see bookings.AuditFilter), encoded as:
+//
+//	[version uint8][from uint32][to uint32][booking id uint64][after seq uint64][facility name]
+//
+// where from and to are minutes since the Unix epoch, 0 for an unbounded time range. A booking id of 0 and an empty
+// facility name match every entry, and only entries following the entry with sequence after seq are selected.
+type AuditQueryPayload struct {
+	Filter bookings.AuditFilter
+}
+
+func NewAuditQueryPayload(filter bookings.AuditFilter) *AuditQueryPayload {
+	return &AuditQueryPayload{Filter: filter}
+}
+
+func (a *AuditQueryPayload) MarshalBinary() ([]byte, error) {
+	data := make([]byte, 25, 25+len(a.Filter.Facility))
+	data[0] = byte(PayloadVersion2)
+	if !a.Filter.From.IsZero() {
+		binary.BigEndian.PutUint32(data[1:5], uint32(a.Filter.From.Unix()/60))
+	}
+	if !a.Filter.To.IsZero() {
+		binary.BigEndian.PutUint32(data[5:9], uint32(a.Filter.To.Unix()/60))
+	}
+	binary.BigEndian.PutUint64(data[9:17], a.Filter.BookingId)
+	binary.BigEndian.PutUint64(data[17:25], a.Filter.AfterSeq)
+	return append(data, a.Filter.Facility...), nil
+}
+
+func (a *AuditQueryPayload) UnmarshalBinary(data []byte) error {
+	if err := checkVersion(data, PayloadVersion2); err != nil {
+		return err
+	}
+	if len(data) < 25 {
+		return fmt.Errorf("payload for AuditQueryPayload must be at least 25 bytes, received: %d", len(data))
+	}
+
+	unixTime := time.Unix(0, 0)
+
+	a.Filter = bookings.AuditFilter{
+		Facility:  bookings.FacilityName(data[25:]),
+		BookingId: binary.BigEndian.Uint64(data[9:17]),
+		AfterSeq:  binary.BigEndian.Uint64(data[17:25]),
+	}
+	if from := binary.BigEndian.Uint32(data[1:5]); from != 0 {
+		a.Filter.From = unixTime.Add(time.Duration(from) * time.Minute)
+	}
+	if to := binary.BigEndian.Uint32(data[5:9]); to != 0 {
+		a.Filter.To = unixTime.Add(time.Duration(to) * time.Minute)
+	}
+
+	return nil
+}
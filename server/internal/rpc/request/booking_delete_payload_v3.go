@@ -0,0 +1,39 @@
+package request
+
+import (
+	"encoding/binary"
+	"fmt"
+)
+
+// BookingDeletePayloadV3 is a BookingDeletePayload with a 64 bit Id, encoded as:
+//
+//	[version uint8][id uint64]
+type BookingDeletePayloadV3 struct {
+	Id uint64
+}
+
+func NewBookingDeletePayloadV3(id uint64) *BookingDeletePayloadV3 {
+	return &BookingDeletePayloadV3{
+		Id: id,
+	}
+}
+
+func (b *BookingDeletePayloadV3) MarshalBinary() ([]byte, error) {
+	data := make([]byte, 9)
+	data[0] = byte(PayloadVersion3)
+	binary.BigEndian.PutUint64(data[1:9], b.Id)
+	return data, nil
+}
+
+func (b *BookingDeletePayloadV3) UnmarshalBinary(data []byte) error {
+	if err := checkVersion(data, PayloadVersion3); err != nil {
+		return err
+	}
+	if len(data) != 9 {
+		return fmt.Errorf("payload for BookingDeletePayloadV3 must be 9 bytes, received: %d", len(data))
+	}
+
+	b.Id = binary.BigEndian.Uint64(data[1:9])
+
+	return nil
+}
@@ -0,0 +1,68 @@
+package request
+
+import (
+	"encoding/binary"
+	"fmt"
+	"server/internal/bookings"
+	"time"
+)
+
+// BookingListPayload lists the bookings of a facility within a time window, encoded as:
+//
+//	[version uint8][start uint32][end uint32][limit uint8][after start uint32][after id uint64][name]
+//
+// where start and end bound the window in minutes since the Unix epoch, and after start and after id are the cursor of
+// the last booking of the previous page, 0 to list from the first booking. A limit of 0 lists as many bookings as fit
+// within the response.
+type BookingListPayload struct {
+	Name  bookings.FacilityName
+	Start time.Time
+	End   time.Time
+	After bookings.BookingCursor
+	Limit int
+}
+
+func NewBookingListPayload(name string, start time.Time, end time.Time, after bookings.BookingCursor, limit int) *BookingListPayload {
+	return &BookingListPayload{
+		Name:  bookings.FacilityName(name),
+		Start: start,
+		End:   end,
+		After: after,
+		Limit: limit,
+	}
+}
+
+func (b *BookingListPayload) MarshalBinary() ([]byte, error) {
+	if b.Limit < 0 || b.Limit > 0xFF {
+		return nil, fmt.Errorf("limit must be between 0 and %d, received: %d", 0xFF, b.Limit)
+	}
+
+	data := make([]byte, 22, 22+len(b.Name))
+	data[0] = byte(PayloadVersion2)
+	binary.BigEndian.PutUint32(data[1:5], uint32(b.Start.Unix()/60))
+	binary.BigEndian.PutUint32(data[5:9], uint32(b.End.Unix()/60))
+	data[9] = byte(b.Limit)
+	binary.BigEndian.PutUint32(data[10:14], encodeOptionalMinutes(b.After.Start))
+	binary.BigEndian.PutUint64(data[14:22], b.After.Id)
+	return append(data, b.Name...), nil
+}
+
+func (b *BookingListPayload) UnmarshalBinary(data []byte) error {
+	if err := checkVersion(data, PayloadVersion2); err != nil {
+		return err
+	}
+	if len(data) < 22 {
+		return fmt.Errorf("payload for BookingListPayload must be at least 22 bytes, received: %d", len(data))
+	}
+
+	unixTime := time.Unix(0, 0)
+
+	b.Start = unixTime.Add(time.Duration(binary.BigEndian.Uint32(data[1:5])) * time.Minute)
+	b.End = unixTime.Add(time.Duration(binary.BigEndian.Uint32(data[5:9])) * time.Minute)
+	b.Limit = int(data[9])
+	b.After.Start = decodeOptionalMinutes(binary.BigEndian.Uint32(data[10:14]))
+	b.After.Id = binary.BigEndian.Uint64(data[14:22])
+	b.Name = bookings.FacilityName(data[22:])
+
+	return nil
+}
@@ -0,0 +1,80 @@
+package request
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"server/internal/bookings"
+	"time"
+)
+
+// BookingMakeMultiPayload makes several bookings together, which are made with 64 bit Ids, encoded as:
+//
+//	[version uint8][count uint8]([start uint32][end uint32][name length uint8][name])...
+//
+// where start and end are minutes since the Unix epoch.
+type BookingMakeMultiPayload struct {
+	Items []BookingMakePayloadV2
+}
+
+func NewBookingMakeMultiPayload(items ...*BookingMakePayloadV2) *BookingMakeMultiPayload {
+	p := &BookingMakeMultiPayload{}
+	for _, item := range items {
+		p.Items = append(p.Items, *item)
+	}
+	return p
+}
+
+func (b *BookingMakeMultiPayload) MarshalBinary() ([]byte, error) {
+	if len(b.Items) == 0 || len(b.Items) > 0xFF {
+		return nil, fmt.Errorf("between 1 and %d bookings must be made", 0xFF)
+	}
+
+	data := []byte{byte(PayloadVersion3), byte(len(b.Items))}
+	for _, item := range b.Items {
+		data = binary.BigEndian.AppendUint32(data, uint32(item.Start.Unix()/60))
+		data = binary.BigEndian.AppendUint32(data, uint32(item.End.Unix()/60))
+
+		var err error
+		if data, err = appendString(data, "name", string(item.Name)); err != nil {
+			return nil, err
+		}
+	}
+	return data, nil
+}
+
+func (b *BookingMakeMultiPayload) UnmarshalBinary(data []byte) error {
+	if err := checkVersion(data, PayloadVersion3); err != nil {
+		return err
+	}
+	if len(data) < 2 || data[1] == 0 {
+		return errors.New("payload for BookingMakeMultiPayload must hold at least 1 booking")
+	}
+
+	unixTime := time.Unix(0, 0)
+
+	b.Items = make([]BookingMakePayloadV2, data[1])
+	data = data[2:]
+	for i := range b.Items {
+		if len(data) < 8 {
+			return fmt.Errorf("payload is too short for booking %d", i)
+		}
+		item := BookingMakePayloadV2{
+			Version: PayloadVersion3,
+			Start:   unixTime.Add(time.Duration(binary.BigEndian.Uint32(data[0:4])) * time.Minute),
+			End:     unixTime.Add(time.Duration(binary.BigEndian.Uint32(data[4:8])) * time.Minute),
+		}
+
+		name, rest, err := readString(data[8:], "name")
+		if err != nil {
+			return err
+		}
+		item.Name = bookings.FacilityName(name)
+		b.Items[i], data = item, rest
+	}
+	if len(data) != 0 {
+		return fmt.Errorf("payload for BookingMakeMultiPayload has %d trailing bytes", len(data))
+	}
+
+	return nil
+}
@@ -80,7 +80,7 @@ func TestBookingMakePayload_GetBooking(t *testing.T) {
 
 	name := "TestBookingMakePayload_GetBooking"
 
-	manager := bookings.GetManager()
+	manager := bookings.NewManager()
 	if err := manager.NewFacility(bookings.FacilityName(name)); err != nil {
 		t.Error(err)
 	}
@@ -122,7 +122,7 @@ func TestBookingMakePayload_GetBookingTimTestCase(t *testing.T) {
 
 	name := "TestBookingMakePayload_GetBookingTimTestCase"
 
-	manager := bookings.GetManager()
+	manager := bookings.NewManager()
 	if err := manager.NewFacility(bookings.FacilityName(name)); err != nil {
 		t.Error(err)
 	}
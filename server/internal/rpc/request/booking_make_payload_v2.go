@@ -0,0 +1,89 @@
+package request
+
+import (
+	"encoding/binary"
+	"fmt"
+	"server/internal/bookings"
+	"time"
+)
+
+// BookingMakePayloadV2 is a BookingMakePayload at minute resolution, encoded as:
+//
+//	[version uint8][start uint32][end uint32][name]
+//
+// where start and end are minutes since the Unix epoch. The payload is of version PayloadVersion3 when the booking
+// should be made with a 64 bit Id.
+type BookingMakePayloadV2 struct {
+	Version PayloadVersion
+	Name    bookings.FacilityName
+	Start   time.Time
+	End     time.Time
+}
+
+func NewBookingMakePayloadV2(
+	name string,
+	start time.Time,
+	end time.Time,
+) *BookingMakePayloadV2 {
+	return &BookingMakePayloadV2{
+		Version: PayloadVersion2,
+		Name:    bookings.FacilityName(name),
+		Start:   start.Truncate(time.Minute),
+		End:     end.Truncate(time.Minute),
+	}
+}
+
+// NewBookingMakePayloadV3 is NewBookingMakePayloadV2 for a booking with a 64 bit Id.
+func NewBookingMakePayloadV3(
+	name string,
+	start time.Time,
+	end time.Time,
+) *BookingMakePayloadV2 {
+	b := NewBookingMakePayloadV2(name, start, end)
+	b.Version = PayloadVersion3
+	return b
+}
+
+func (b *BookingMakePayloadV2) MarshalBinary() ([]byte, error) {
+	data := make([]byte, 9, 9+len(b.Name))
+	data[0] = byte(b.Version)
+	binary.BigEndian.PutUint32(data[1:5], uint32(b.Start.Unix()/60))
+	binary.BigEndian.PutUint32(data[5:9], uint32(b.End.Unix()/60))
+	return append(data, b.Name...), nil
+}
+
+func (b *BookingMakePayloadV2) UnmarshalBinary(data []byte) error {
+	if GetPayloadVersion(data) != PayloadVersion3 {
+		if err := checkVersion(data, PayloadVersion2); err != nil {
+			return err
+		}
+	}
+	if len(data) < 9 {
+		return fmt.Errorf("payload for BookingMakePayloadV2 must be at least 9 bytes, received: %d", len(data))
+	}
+
+	unixTime := time.Unix(0, 0)
+
+	b.Version = PayloadVersion(data[0])
+	b.Start = unixTime.Add(time.Duration(binary.BigEndian.Uint32(data[1:5])) * time.Minute)
+	b.End = unixTime.Add(time.Duration(binary.BigEndian.Uint32(data[5:9])) * time.Minute)
+	b.Name = bookings.FacilityName(data[9:])
+
+	return nil
+}
+
+// GetBooking returns the requested booking, which must start and end on a multiple of slot since the Unix epoch.
+func (b *BookingMakePayloadV2) GetBooking(slot time.Duration) (bookings.Booking, error) {
+	if err := checkAligned("booking start", time.Duration(b.Start.Unix())*time.Second, slot); err != nil {
+		return bookings.Booking{}, err
+	}
+	if err := checkAligned("booking end", time.Duration(b.End.Unix())*time.Second, slot); err != nil {
+		return bookings.Booking{}, err
+	}
+
+	return bookings.NewBooking(
+		bookings.BookingWithRandomId(),
+		bookings.BookingWithStartTime(b.Start),
+		bookings.BookingWithEndTime(b.End),
+	)
+}
@@ -0,0 +1,55 @@
+package request
+
+import (
+	"encoding/binary"
+	"fmt"
+	"time"
+)
+
+// BookingModifyPayloadV2 is a BookingModifyPayload at minute resolution, encoded as:
+//
+//	[version uint8][id uint16][delta int32]
+//
+// where delta is the number of minutes to shift the booking by.
+type BookingModifyPayloadV2 struct {
+	Id           uint16
+	DeltaMinutes int
+}
+
+func NewBookingModifyPayloadV2(id uint16, delta time.Duration) *BookingModifyPayloadV2 {
+	return &BookingModifyPayloadV2{
+		Id:           id,
+		DeltaMinutes: int(delta / time.Minute),
+	}
+}
+
+func (b *BookingModifyPayloadV2) MarshalBinary() ([]byte, error) {
+	data := make([]byte, 7)
+	data[0] = byte(PayloadVersion2)
+	binary.BigEndian.PutUint16(data[1:3], b.Id)
+	binary.BigEndian.PutUint32(data[3:7], uint32(int32(b.DeltaMinutes)))
+	return data, nil
+}
+
+func (b *BookingModifyPayloadV2) UnmarshalBinary(data []byte) error {
+	if err := checkVersion(data, PayloadVersion2); err != nil {
+		return err
+	}
+	if len(data) != 7 {
+		return fmt.Errorf("payload for BookingModifyPayloadV2 must be 7 bytes, received: %d", len(data))
+	}
+
+	b.Id = binary.BigEndian.Uint16(data[1:3])
+	b.DeltaMinutes = int(int32(binary.BigEndian.Uint32(data[3:7])))
+
+	return nil
+}
+
+// GetDelta returns the requested shift, which must be a multiple of slot.
+func (b *BookingModifyPayloadV2) GetDelta(slot time.Duration) (time.Duration, error) {
+	delta := time.Duration(b.DeltaMinutes) * time.Minute
+	if err := checkAligned("booking shift", delta, slot); err != nil {
+		return 0, err
+	}
+	return delta, nil
+}
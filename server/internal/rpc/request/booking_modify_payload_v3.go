@@ -0,0 +1,55 @@
+package request
+
+import (
+	"encoding/binary"
+	"fmt"
+	"time"
+)
+
+// BookingModifyPayloadV3 is a BookingModifyPayloadV2 with a 64 bit Id, encoded as:
+//
+//	[version uint8][id uint64][delta int32]
+//
+// where delta is the number of minutes to shift the booking by.
+type BookingModifyPayloadV3 struct {
+	Id           uint64
+	DeltaMinutes int
+}
+
+func NewBookingModifyPayloadV3(id uint64, delta time.Duration) *BookingModifyPayloadV3 {
+	return &BookingModifyPayloadV3{
+		Id:           id,
+		DeltaMinutes: int(delta / time.Minute),
+	}
+}
+
+func (b *BookingModifyPayloadV3) MarshalBinary() ([]byte, error) {
+	data := make([]byte, 13)
+	data[0] = byte(PayloadVersion3)
+	binary.BigEndian.PutUint64(data[1:9], b.Id)
+	binary.BigEndian.PutUint32(data[9:13], uint32(int32(b.DeltaMinutes)))
+	return data, nil
+}
+
+func (b *BookingModifyPayloadV3) UnmarshalBinary(data []byte) error {
+	if err := checkVersion(data, PayloadVersion3); err != nil {
+		return err
+	}
+	if len(data) != 13 {
+		return fmt.Errorf("payload for BookingModifyPayloadV3 must be 13 bytes, received: %d", len(data))
+	}
+
+	b.Id = binary.BigEndian.Uint64(data[1:9])
+	b.DeltaMinutes = int(int32(binary.BigEndian.Uint32(data[9:13])))
+
+	return nil
+}
+
+// GetDelta returns the requested shift, which must be a multiple of slot.
+func (b *BookingModifyPayloadV3) GetDelta(slot time.Duration) (time.Duration, error) {
+	delta := time.Duration(b.DeltaMinutes) * time.Minute
+	if err := checkAligned("booking shift", delta, slot); err != nil {
+		return 0, err
+	}
+	return delta, nil
+}
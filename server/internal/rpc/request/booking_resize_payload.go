@@ -0,0 +1,86 @@
+package request
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"time"
+)
+
+// BookingResizePayload sets a new start and/or end of the booking with the given 64 bit Id, encoded as:
+//
+//	[version uint8][id uint64][start uint32][end uint32]
+//
+// where start and end are in minutes since the Unix epoch, and 0 keeps the current start or end of the booking.
+type BookingResizePayload struct {
+	Id    uint64
+	Start time.Time // Zero keeps the current start
+	End   time.Time // Zero keeps the current end
+}
+
+func NewBookingResizePayload(id uint64, start time.Time, end time.Time) *BookingResizePayload {
+	return &BookingResizePayload{
+		Id:    id,
+		Start: start,
+		End:   end,
+	}
+}
+
+func (b *BookingResizePayload) MarshalBinary() ([]byte, error) {
+	data := make([]byte, 17)
+	data[0] = byte(PayloadVersion3)
+	binary.BigEndian.PutUint64(data[1:9], b.Id)
+	binary.BigEndian.PutUint32(data[9:13], encodeOptionalMinutes(b.Start))
+	binary.BigEndian.PutUint32(data[13:17], encodeOptionalMinutes(b.End))
+	return data, nil
+}
+
+func (b *BookingResizePayload) UnmarshalBinary(data []byte) error {
+	if err := checkVersion(data, PayloadVersion3); err != nil {
+		return err
+	}
+	if len(data) != 17 {
+		return fmt.Errorf("payload for BookingResizePayload must be 17 bytes, received: %d", len(data))
+	}
+
+	b.Id = binary.BigEndian.Uint64(data[1:9])
+	b.Start = decodeOptionalMinutes(binary.BigEndian.Uint32(data[9:13]))
+	b.End = decodeOptionalMinutes(binary.BigEndian.Uint32(data[13:17]))
+
+	return nil
+}
+
+// GetTimes returns the requested start and end, which must be multiples of slot. At least one of them must be set.
+func (b *BookingResizePayload) GetTimes(slot time.Duration) (time.Time, time.Time, error) {
+	if b.Start.IsZero() && b.End.IsZero() {
+		return time.Time{}, time.Time{}, errors.New("booking start or end must be changed")
+	}
+	for _, t := range []struct {
+		name string
+		at   time.Time
+	}{{"booking start", b.Start}, {"booking end", b.End}} {
+		if t.at.IsZero() {
+			continue
+		}
+		if err := checkAligned(t.name, time.Duration(t.at.Unix())*time.Second, slot); err != nil {
+			return time.Time{}, time.Time{}, err
+		}
+	}
+	return b.Start, b.End, nil
+}
+
+// encodeOptionalMinutes returns t in minutes since the Unix epoch, 0 if t is zero.
+func encodeOptionalMinutes(t time.Time) uint32 {
+	if t.IsZero() {
+		return 0
+	}
+	return uint32(t.Unix() / 60)
+}
+
+// decodeOptionalMinutes returns the time m minutes since the Unix epoch, the zero time if m is 0.
+func decodeOptionalMinutes(m uint32) time.Time {
+	if m == 0 {
+		return time.Time{}
+	}
+	return time.Unix(0, 0).Add(time.Duration(m) * time.Minute)
+}
@@ -0,0 +1,86 @@
+package request
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"server/internal/bookings"
+	"time"
+)
+
+// FacilityCommonFreePayload searches for the free time common to several facilities, encoded as:
+//
+//	[version uint8][start uint32][end uint32][min duration uint16][count uint8]([name length uint8][name])...
+//
+// where start and end bound the search in minutes since the Unix epoch, and min duration is the shortest period of
+// free time to return in minutes.
+type FacilityCommonFreePayload struct {
+	Names       []bookings.FacilityName
+	Start       time.Time
+	End         time.Time
+	MinDuration time.Duration
+}
+
+func NewFacilityCommonFreePayload(names []string, start time.Time, end time.Time, minDuration time.Duration) *FacilityCommonFreePayload {
+	p := &FacilityCommonFreePayload{
+		Start:       start,
+		End:         end,
+		MinDuration: minDuration,
+	}
+	for _, name := range names {
+		p.Names = append(p.Names, bookings.FacilityName(name))
+	}
+	return p
+}
+
+func (f *FacilityCommonFreePayload) MarshalBinary() ([]byte, error) {
+	if len(f.Names) == 0 || len(f.Names) > 0xFF {
+		return nil, fmt.Errorf("between 1 and %d facilities must be searched", 0xFF)
+	}
+	if f.MinDuration < 0 || f.MinDuration/time.Minute > 0xFFFF {
+		return nil, fmt.Errorf("minimum duration must be between 0 and %d minutes, received: %v", 0xFFFF, f.MinDuration)
+	}
+
+	data := []byte{byte(PayloadVersion2)}
+	data = binary.BigEndian.AppendUint32(data, uint32(f.Start.Unix()/60))
+	data = binary.BigEndian.AppendUint32(data, uint32(f.End.Unix()/60))
+	data = binary.BigEndian.AppendUint16(data, uint16(f.MinDuration/time.Minute))
+	data = append(data, byte(len(f.Names)))
+	for _, name := range f.Names {
+		var err error
+		if data, err = appendString(data, "name", string(name)); err != nil {
+			return nil, err
+		}
+	}
+	return data, nil
+}
+
+func (f *FacilityCommonFreePayload) UnmarshalBinary(data []byte) error {
+	if err := checkVersion(data, PayloadVersion2); err != nil {
+		return err
+	}
+	if len(data) < 12 || data[11] == 0 {
+		return errors.New("payload for FacilityCommonFreePayload must hold at least 1 facility")
+	}
+
+	unixTime := time.Unix(0, 0)
+
+	f.Start = unixTime.Add(time.Duration(binary.BigEndian.Uint32(data[1:5])) * time.Minute)
+	f.End = unixTime.Add(time.Duration(binary.BigEndian.Uint32(data[5:9])) * time.Minute)
+	f.MinDuration = time.Duration(binary.BigEndian.Uint16(data[9:11])) * time.Minute
+
+	f.Names = make([]bookings.FacilityName, data[11])
+	data = data[12:]
+	for i := range f.Names {
+		name, rest, err := readString(data, "name")
+		if err != nil {
+			return err
+		}
+		f.Names[i], data = bookings.FacilityName(name), rest
+	}
+	if len(data) != 0 {
+		return fmt.Errorf("payload for FacilityCommonFreePayload has %d trailing bytes", len(data))
+	}
+
+	return nil
+}
@@ -0,0 +1,45 @@
+package request
+
+import (
+	"encoding/binary"
+	"fmt"
+	"server/internal/bookings"
+)
+
+// FacilityCreatePayloadV2 is a FacilityCreatePayload with a capacity, encoded as:
+//
+//	[version uint8][capacity uint16][name]
+//
+// where capacity is the number of bookings allowed at the same time.
+type FacilityCreatePayloadV2 struct {
+	Name     bookings.FacilityName
+	Capacity int
+}
+
+func NewFacilityCreatePayloadV2(name string, capacity int) *FacilityCreatePayloadV2 {
+	return &FacilityCreatePayloadV2{
+		Name:     bookings.FacilityName(name),
+		Capacity: capacity,
+	}
+}
+
+func (f *FacilityCreatePayloadV2) MarshalBinary() ([]byte, error) {
+	data := make([]byte, 3, 3+len(f.Name))
+	data[0] = byte(PayloadVersion2)
+	binary.BigEndian.PutUint16(data[1:3], uint16(f.Capacity))
+	return append(data, f.Name...), nil
+}
+
+func (f *FacilityCreatePayloadV2) UnmarshalBinary(data []byte) error {
+	if err := checkVersion(data, PayloadVersion2); err != nil {
+		return err
+	}
+	if len(data) < 3 {
+		return fmt.Errorf("payload for FacilityCreatePayloadV2 must be at least 3 bytes, received: %d", len(data))
+	}
+
+	f.Capacity = int(binary.BigEndian.Uint16(data[1:3]))
+	f.Name = bookings.FacilityName(data[3:])
+
+	return nil
+}
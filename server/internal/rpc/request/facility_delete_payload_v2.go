@@ -0,0 +1,32 @@
+package request
+
+import (
+	"server/internal/bookings"
+)
+
+// FacilityDeletePayloadV2 is a FacilityDeletePayload that is versioned, so that it can be wrapped with a principal or an
+// idempotency key, encoded as:
+//
+//	[version uint8][name]
+type FacilityDeletePayloadV2 struct {
+	Name bookings.FacilityName
+}
+
+func NewFacilityDeletePayloadV2(name string) *FacilityDeletePayloadV2 {
+	return &FacilityDeletePayloadV2{Name: bookings.FacilityName(name)}
+}
+
+func (f *FacilityDeletePayloadV2) MarshalBinary() ([]byte, error) {
+	data := make([]byte, 1, 1+len(f.Name))
+	data[0] = byte(PayloadVersion2)
+	return append(data, f.Name...), nil
+}
+
+func (f *FacilityDeletePayloadV2) UnmarshalBinary(data []byte) error {
+	if err := checkVersion(data, PayloadVersion2); err != nil {
+		return err
+	}
+
+	f.Name = bookings.FacilityName(data[1:])
+	return nil
+}
@@ -0,0 +1,83 @@
+package request
+
+import (
+	"encoding/binary"
+	"fmt"
+	"server/internal/bookings"
+	"time"
+)
+
+// FacilityFreeSlotsFlagWithinHours restricts a FacilityFreeSlotsPayload to the opening hours of the facility.
+const FacilityFreeSlotsFlagWithinHours uint8 = 1 << 0
+
+// FacilityFreeSlotsPayload searches for the next free periods of a facility, encoded as:
+//
+//	[version uint8][start uint32][end uint32][duration uint16][count uint8][flags uint8][name]
+//
+// where start and end bound the search in minutes since the Unix epoch, and duration is the length of each period in
+// minutes.
+type FacilityFreeSlotsPayload struct {
+	Name     bookings.FacilityName
+	Start    time.Time
+	End      time.Time
+	Duration time.Duration
+	Count    int
+	Flags    uint8
+}
+
+func NewFacilityFreeSlotsPayload(name string, start time.Time, end time.Time, duration time.Duration, count int, withinHours bool) *FacilityFreeSlotsPayload {
+	p := &FacilityFreeSlotsPayload{
+		Name:     bookings.FacilityName(name),
+		Start:    start,
+		End:      end,
+		Duration: duration,
+		Count:    count,
+	}
+	if withinHours {
+		p.Flags |= FacilityFreeSlotsFlagWithinHours
+	}
+	return p
+}
+
+func (f *FacilityFreeSlotsPayload) MarshalBinary() ([]byte, error) {
+	if f.Count < 0 || f.Count > 0xFF {
+		return nil, fmt.Errorf("count must be between 0 and %d, received: %d", 0xFF, f.Count)
+	}
+	if f.Duration < 0 || f.Duration/time.Minute > 0xFFFF {
+		return nil, fmt.Errorf("duration must be between 0 and %d minutes, received: %v", 0xFFFF, f.Duration)
+	}
+
+	data := make([]byte, 13, 13+len(f.Name))
+	data[0] = byte(PayloadVersion2)
+	binary.BigEndian.PutUint32(data[1:5], uint32(f.Start.Unix()/60))
+	binary.BigEndian.PutUint32(data[5:9], uint32(f.End.Unix()/60))
+	binary.BigEndian.PutUint16(data[9:11], uint16(f.Duration/time.Minute))
+	data[11] = byte(f.Count)
+	data[12] = f.Flags
+	return append(data, f.Name...), nil
+}
+
+func (f *FacilityFreeSlotsPayload) UnmarshalBinary(data []byte) error {
+	if err := checkVersion(data, PayloadVersion2); err != nil {
+		return err
+	}
+	if len(data) < 13 {
+		return fmt.Errorf("payload for FacilityFreeSlotsPayload must be at least 13 bytes, received: %d", len(data))
+	}
+
+	unixTime := time.Unix(0, 0)
+
+	f.Start = unixTime.Add(time.Duration(binary.BigEndian.Uint32(data[1:5])) * time.Minute)
+	f.End = unixTime.Add(time.Duration(binary.BigEndian.Uint32(data[5:9])) * time.Minute)
+	f.Duration = time.Duration(binary.BigEndian.Uint16(data[9:11])) * time.Minute
+	f.Count = int(data[11])
+	f.Flags = data[12]
+	f.Name = bookings.FacilityName(data[13:])
+
+	return nil
+}
+
+// WithinHours returns whether the search is restricted to the opening hours of the facility.
+func (f *FacilityFreeSlotsPayload) WithinHours() bool {
+	return f.Flags&FacilityFreeSlotsFlagWithinHours != 0
+}
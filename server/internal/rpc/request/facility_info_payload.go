@@ -0,0 +1,106 @@
+package request
+
+import (
+	"encoding/binary"
+	"fmt"
+	"server/internal/bookings"
+	"time"
+)
+
+// FacilityInfoPayload creates or updates a facility with a capacity and information, encoded as:
+//
+//	[version uint8][capacity uint16][weekdays uint8][open uint16][close uint16]...
+//	[name length uint8][name][description length uint8][description][location length uint8][location][timezone]
+//
+// where weekdays is 0 for a facility that is always open or 7 for opening hours from Sunday to Saturday, each given as
+// minutes after midnight, and timezone is an IANA timezone or empty for the server's timezone.
+type FacilityInfoPayload struct {
+	Name     bookings.FacilityName
+	Capacity int
+	Info     bookings.FacilityInfo
+}
+
+func NewFacilityInfoPayload(name string, capacity int, info bookings.FacilityInfo) *FacilityInfoPayload {
+	return &FacilityInfoPayload{
+		Name:     bookings.FacilityName(name),
+		Capacity: capacity,
+		Info:     info,
+	}
+}
+
+// appendString appends s to data prefixed by its length.
+func appendString(data []byte, name string, s string) ([]byte, error) {
+	if len(s) > 0xFF {
+		return nil, fmt.Errorf("%s must not be longer than %d bytes", name, 0xFF)
+	}
+	return append(append(data, byte(len(s))), s...), nil
+}
+
+// readString reads a string prefixed by its length from data, returning the remainder of data.
+func readString(data []byte, name string) (string, []byte, error) {
+	if len(data) < 1 || len(data) < 1+int(data[0]) {
+		return "", nil, fmt.Errorf("payload is too short for %s", name)
+	}
+	return string(data[1 : 1+int(data[0])]), data[1+int(data[0]):], nil
+}
+
+func (f *FacilityInfoPayload) MarshalBinary() ([]byte, error) {
+	data := make([]byte, 4, 4+4*len(f.Info.Hours)+len(f.Name)+len(f.Info.Description)+len(f.Info.Location)+len(f.Info.Timezone)+3)
+	data[0] = byte(PayloadVersion2)
+	binary.BigEndian.PutUint16(data[1:3], uint16(f.Capacity))
+	data[3] = byte(len(f.Info.Hours))
+	for _, h := range f.Info.Hours {
+		data = binary.BigEndian.AppendUint16(data, uint16(h.Open/time.Minute))
+		data = binary.BigEndian.AppendUint16(data, uint16(h.Close/time.Minute))
+	}
+
+	var err error
+	if data, err = appendString(data, "name", string(f.Name)); err != nil {
+		return nil, err
+	}
+	if data, err = appendString(data, "description", f.Info.Description); err != nil {
+		return nil, err
+	}
+	if data, err = appendString(data, "location", f.Info.Location); err != nil {
+		return nil, err
+	}
+	return append(data, f.Info.Timezone...), nil
+}
+
+func (f *FacilityInfoPayload) UnmarshalBinary(data []byte) error {
+	if err := checkVersion(data, PayloadVersion2); err != nil {
+		return err
+	}
+	if len(data) < 4 || len(data) < 4+4*int(data[3]) {
+		return fmt.Errorf("payload for FacilityInfoPayload is too short: %d", len(data))
+	}
+
+	f.Capacity = int(binary.BigEndian.Uint16(data[1:3]))
+	f.Info = bookings.FacilityInfo{}
+	if days := int(data[3]); days > 0 {
+		f.Info.Hours = make([]bookings.OpeningHours, days)
+		for i := range f.Info.Hours {
+			offset := 4 + 4*i
+			f.Info.Hours[i] = bookings.OpeningHours{
+				Open:  time.Duration(binary.BigEndian.Uint16(data[offset:offset+2])) * time.Minute,
+				Close: time.Duration(binary.BigEndian.Uint16(data[offset+2:offset+4])) * time.Minute,
+			}
+		}
+	}
+	data = data[4+4*int(data[3]):]
+
+	name, data, err := readString(data, "name")
+	if err != nil {
+		return err
+	}
+	f.Name = bookings.FacilityName(name)
+	if f.Info.Description, data, err = readString(data, "description"); err != nil {
+		return err
+	}
+	if f.Info.Location, data, err = readString(data, "location"); err != nil {
+		return err
+	}
+	f.Info.Timezone = string(data)
+
+	return nil
+}
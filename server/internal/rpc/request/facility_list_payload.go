@@ -0,0 +1,80 @@
+package request
+
+import (
+	"encoding/binary"
+	"fmt"
+	"server/internal/bookings"
+)
+
+// FacilityListPayload lists facilities (see bookings.FacilityListFilter), encoded as:
+//
+//	[version uint8][sort uint8][limit uint8][after bookings uint32][prefix length uint8][prefix]
+//	[contains length uint8][contains][after name length uint8][after name]
+//
+// where after name and after bookings are the cursor of the last facility of the previous page, empty and 0 to list
+// from the first facility. A limit of 0 lists as many facilities as fit within the response.
+type FacilityListPayload struct {
+	Filter bookings.FacilityListFilter
+	Limit  int
+}
+
+func NewFacilityListPayload(filter bookings.FacilityListFilter, limit int) *FacilityListPayload {
+	return &FacilityListPayload{Filter: filter, Limit: limit}
+}
+
+func (f *FacilityListPayload) MarshalBinary() ([]byte, error) {
+	if f.Limit < 0 || f.Limit > 0xFF {
+		return nil, fmt.Errorf("limit must be between 0 and %d, received: %d", 0xFF, f.Limit)
+	}
+
+	data := make([]byte, 7, 10+len(f.Filter.Prefix)+len(f.Filter.Contains)+len(f.Filter.After.Name))
+	data[0] = byte(PayloadVersion2)
+	data[1] = byte(f.Filter.Sort)
+	data[2] = byte(f.Limit)
+	binary.BigEndian.PutUint32(data[3:7], uint32(f.Filter.After.Bookings))
+
+	var err error
+	if data, err = appendString(data, "prefix", f.Filter.Prefix); err != nil {
+		return nil, err
+	}
+	if data, err = appendString(data, "contains", f.Filter.Contains); err != nil {
+		return nil, err
+	}
+	return appendString(data, "cursor", string(f.Filter.After.Name))
+}
+
+func (f *FacilityListPayload) UnmarshalBinary(data []byte) error {
+	if err := checkVersion(data, PayloadVersion2); err != nil {
+		return err
+	}
+	if len(data) < 7 {
+		return fmt.Errorf("payload for FacilityListPayload must be at least 7 bytes, received: %d", len(data))
+	}
+
+	f.Filter.Sort = bookings.FacilitySort(data[1])
+	f.Limit = int(data[2])
+	f.Filter.After.Bookings = int(binary.BigEndian.Uint32(data[3:7]))
+	data = data[7:]
+
+	var err error
+	if f.Filter.Prefix, data, err = readString(data, "prefix"); err != nil {
+		return err
+	}
+	if f.Filter.Contains, data, err = readString(data, "contains"); err != nil {
+		return err
+	}
+	var after string
+	if after, data, err = readString(data, "cursor"); err != nil {
+		return err
+	}
+	if len(data) != 0 {
+		return fmt.Errorf("payload for FacilityListPayload has %d trailing bytes", len(data))
+	}
+	f.Filter.After.Name = bookings.FacilityName(after)
+
+	switch f.Filter.Sort {
+	case bookings.FacilitySortName, bookings.FacilitySortBookings:
+		return nil
+	}
+	return fmt.Errorf("unknown facility sort %d", f.Filter.Sort)
+}
@@ -0,0 +1,127 @@
+package request
+
+import (
+	"encoding/binary"
+	"fmt"
+	"server/internal/bookings"
+	"time"
+)
+
+// FacilityQueryPayloadV2 is a FacilityQueryPayload with a chosen resolution, encoded as:
+//
+//	[version uint8][days uint8][resolution uint16][name]
+//
+// where resolution is the length of each slot in minutes, 0 for the server's booking slot. Days start from today in
+// the server's timezone, unless the payload is of PayloadVersion5, encoded as:
+//
+//	[version uint8][days uint8][resolution uint16][year uint16][month uint8][day uint8][timezone length uint8][timezone][name]
+//
+// where days start from the given date in the given timezone (see bookings.ParseTimezone).
+type FacilityQueryPayloadV2 struct {
+	Version           PayloadVersion
+	Name              bookings.FacilityName
+	Days              int
+	ResolutionMinutes int
+
+	// Date and Timezone are only encoded in payloads of PayloadVersion5; only the year, month and day of Date are used.
+	Date     time.Time
+	Timezone string
+}
+
+func NewFacilityQueryPayloadV2(name string, days int, resolution time.Duration) *FacilityQueryPayloadV2 {
+	return &FacilityQueryPayloadV2{
+		Version:           PayloadVersion2,
+		Name:              bookings.FacilityName(name),
+		Days:              days,
+		ResolutionMinutes: int(resolution / time.Minute),
+	}
+}
+
+// NewFacilityQueryPayloadV5 creates a payload querying days from the year, month and day of date in timezone.
+func NewFacilityQueryPayloadV5(name string, date time.Time, timezone string, days int, resolution time.Duration) *FacilityQueryPayloadV2 {
+	return &FacilityQueryPayloadV2{
+		Version:           PayloadVersion5,
+		Name:              bookings.FacilityName(name),
+		Days:              days,
+		ResolutionMinutes: int(resolution / time.Minute),
+		Date:              time.Date(date.Year(), date.Month(), date.Day(), 0, 0, 0, 0, time.UTC),
+		Timezone:          timezone,
+	}
+}
+
+func (f *FacilityQueryPayloadV2) MarshalBinary() ([]byte, error) {
+	data := make([]byte, 4, 4+len(f.Name))
+	data[0] = byte(PayloadVersion2)
+	data[1] = byte(f.Days)
+	binary.BigEndian.PutUint16(data[2:4], uint16(f.ResolutionMinutes))
+
+	if f.Version == PayloadVersion5 {
+		data[0] = byte(PayloadVersion5)
+		data = binary.BigEndian.AppendUint16(data, uint16(f.Date.Year()))
+		data = append(data, byte(f.Date.Month()), byte(f.Date.Day()))
+
+		var err error
+		if data, err = appendString(data, "timezone", f.Timezone); err != nil {
+			return nil, err
+		}
+	}
+	return append(data, f.Name...), nil
+}
+
+func (f *FacilityQueryPayloadV2) UnmarshalBinary(data []byte) error {
+	if GetPayloadVersion(data) != PayloadVersion5 {
+		if err := checkVersion(data, PayloadVersion2); err != nil {
+			return err
+		}
+	}
+	if len(data) < 4 {
+		return fmt.Errorf("payload for FacilityQueryPayloadV2 must be at least 4 bytes, received: %d", len(data))
+	}
+
+	f.Version = PayloadVersion(data[0])
+	f.Days = int(data[1])
+	f.ResolutionMinutes = int(binary.BigEndian.Uint16(data[2:4]))
+	data = data[4:]
+
+	if f.Version == PayloadVersion5 {
+		if len(data) < 4 {
+			return fmt.Errorf("payload for FacilityQueryPayloadV2 must be at least 8 bytes, received: %d", len(data)+4)
+		}
+		year, month, day := int(binary.BigEndian.Uint16(data[0:2])), time.Month(data[2]), int(data[3])
+		f.Date = time.Date(year, month, day, 0, 0, 0, 0, time.UTC)
+		if f.Date.Month() != month || f.Date.Day() != day {
+			return fmt.Errorf("invalid date %04d-%02d-%02d", year, month, day)
+		}
+
+		var err error
+		if f.Timezone, data, err = readString(data[4:], "timezone"); err != nil {
+			return err
+		}
+	}
+	f.Name = bookings.FacilityName(data)
+
+	return nil
+}
+
+// GetResolution returns the requested resolution, or slot if none was requested.
+func (f *FacilityQueryPayloadV2) GetResolution(slot time.Duration) time.Duration {
+	if f.ResolutionMinutes == 0 {
+		return slot
+	}
+	return time.Duration(f.ResolutionMinutes) * time.Minute
+}
+
+// GetFirstDay returns midnight at the start of the first day queried, which is today in the server's timezone unless
+// the payload is of PayloadVersion5.
+func (f *FacilityQueryPayloadV2) GetFirstDay() (time.Time, error) {
+	if f.Version != PayloadVersion5 {
+		now := time.Now()
+		return time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, time.Local), nil
+	}
+
+	loc, err := bookings.ParseTimezone(f.Timezone)
+	if err != nil {
+		return time.Time{}, err
+	}
+	return time.Date(f.Date.Year(), f.Date.Month(), f.Date.Day(), 0, 0, 0, 0, loc), nil
+}
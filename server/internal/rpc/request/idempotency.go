@@ -0,0 +1,48 @@
+package request
+
+import (
+	"errors"
+	"fmt"
+)
+
+// PayloadVersion6 wraps any other versioned payload with an idempotency key chosen by the client, encoded as:
+//
+//	[version uint8][key length uint8][key][payload]
+//
+// so that a mutation made again with the same key (e.g. after the client restarted, with a new message Id) is answered
+// with the outcome of the original request. The wrapped payload may itself be wrapped with a principal (see
+// PayloadVersion4).
+const PayloadVersion6 PayloadVersion = 0x06
+
+// WithIdempotencyKey wraps the versioned payload with key.
+func WithIdempotencyKey(key string, payload []byte) ([]byte, error) {
+	if key == "" || len(key) > 0xFF {
+		return nil, fmt.Errorf("idempotency key must be between 1 and %d bytes", 0xFF)
+	}
+	if v := GetPayloadVersion(payload); v < PayloadVersion2 || v == PayloadVersion6 {
+		return nil, errors.New("idempotency key can only be supplied with versioned payloads")
+	}
+
+	data := make([]byte, 2, 2+len(key)+len(payload))
+	data[0] = byte(PayloadVersion6)
+	data[1] = byte(len(key))
+	data = append(data, key...)
+	return append(data, payload...), nil
+}
+
+// SplitIdempotencyKey returns the idempotency key and the payload it wraps, for payloads made with WithIdempotencyKey.
+// Other payloads are returned as is, without a key.
+func SplitIdempotencyKey(data []byte) (string, []byte, error) {
+	if GetPayloadVersion(data) != PayloadVersion6 {
+		return "", data, nil
+	}
+	if len(data) < 2 || len(data) < 2+int(data[1]) {
+		return "", nil, fmt.Errorf("payload is too short for idempotency key: %d", len(data))
+	}
+
+	key, payload := string(data[2:2+int(data[1])]), data[2+int(data[1]):]
+	if GetPayloadVersion(payload) == PayloadVersion6 {
+		return "", nil, errors.New("idempotency key must only be supplied once")
+	}
+	return key, payload, nil
+}
@@ -1,5 +1,7 @@
 package request
 
+import "fmt"
+
 type MethodIdentifier uint8
 
 const (
@@ -7,8 +9,107 @@ const (
 	MethodIdentifierFacilityQuery   MethodIdentifier = 0x02
 	MethodIdentifierFacilityMonitor MethodIdentifier = 0x03
 	MethodIdentifierFacilityDelete  MethodIdentifier = 0x04
+	MethodIdentifierFacilityQueryV2 MethodIdentifier = 0x05 // Availability at minute resolution
+
+	MethodIdentifierFacilityCreateV2      MethodIdentifier = 0x06 // Facility with a capacity
+	MethodIdentifierFacilityQueryCapacity MethodIdentifier = 0x07 // Remaining capacity at minute resolution
+	MethodIdentifierFacilityCreateV3      MethodIdentifier = 0x08 // Facility with a capacity, opening hours and other information
+	MethodIdentifierFacilityUpdate        MethodIdentifier = 0x09 // Capacity, opening hours and other information of a facility
+	MethodIdentifierFacilityFreeSlots     MethodIdentifier = 0x0A // Next free periods of a given length
+	MethodIdentifierFacilityCommonFree    MethodIdentifier = 0x0B // Free time common to several facilities
+	MethodIdentifierFacilityDeleteV2      MethodIdentifier = 0x0C // Facility deletion with a versioned payload
+	MethodIdentifierFacilityList          MethodIdentifier = 0x0D // Facilities by name, a page at a time
 
 	MethodIdentifierBookingMake   MethodIdentifier = 0x11
 	MethodIdentifierBookingUpdate MethodIdentifier = 0x12
 	MethodIdentifierBookingDelete MethodIdentifier = 0x13
+
+	MethodIdentifierBookingMakeV2   MethodIdentifier = 0x14 // Booking times at minute resolution
+	MethodIdentifierBookingUpdateV2 MethodIdentifier = 0x15 // Booking shifts at minute resolution
+
+	MethodIdentifierSeriesMake   MethodIdentifier = 0x16 // Recurring bookings
+	MethodIdentifierSeriesUpdate MethodIdentifier = 0x17 // Shift every occurrence of a recurring booking
+	MethodIdentifierSeriesDelete MethodIdentifier = 0x18 // Cancel every occurrence of a recurring booking
+
+	MethodIdentifierBookingDeleteV2 MethodIdentifier = 0x19 // Booking deletion by 64 bit Id
+
+	MethodIdentifierWaitlistJoin  MethodIdentifier = 0x1A // Booking made once it no longer clashes
+	MethodIdentifierWaitlistLeave MethodIdentifier = 0x1B // Removal of a booking from the waitlist
+
+	MethodIdentifierBookingHold    MethodIdentifier = 0x1C // Tentative booking, released unless confirmed in time
+	MethodIdentifierBookingConfirm MethodIdentifier = 0x1D // Confirmation of a tentative booking
+
+	MethodIdentifierBookingMakeMulti MethodIdentifier = 0x1E // Bookings made together, possibly in different facilities
+	MethodIdentifierBookingResize    MethodIdentifier = 0x1F // New start and/or end of a booking
+
+	MethodIdentifierBookingList MethodIdentifier = 0x20 // Bookings of a facility within a time window, a page at a time
+	MethodIdentifierAuditQuery  MethodIdentifier = 0x21 // Entries of the audit log of changes
 )
+
+var methodNames = map[MethodIdentifier]string{
+	MethodIdentifierFacilityCreate:        "FacilityCreate",
+	MethodIdentifierFacilityQuery:         "FacilityQuery",
+	MethodIdentifierFacilityMonitor:       "FacilityMonitor",
+	MethodIdentifierFacilityDelete:        "FacilityDelete",
+	MethodIdentifierFacilityQueryV2:       "FacilityQueryV2",
+	MethodIdentifierFacilityCreateV2:      "FacilityCreateV2",
+	MethodIdentifierFacilityQueryCapacity: "FacilityQueryCapacity",
+	MethodIdentifierFacilityCreateV3:      "FacilityCreateV3",
+	MethodIdentifierFacilityUpdate:        "FacilityUpdate",
+	MethodIdentifierFacilityFreeSlots:     "FacilityFreeSlots",
+	MethodIdentifierFacilityCommonFree:    "FacilityCommonFree",
+	MethodIdentifierFacilityDeleteV2:      "FacilityDeleteV2",
+	MethodIdentifierFacilityList:          "FacilityList",
+	MethodIdentifierBookingMake:           "BookingMake",
+	MethodIdentifierBookingUpdate:         "BookingUpdate",
+	MethodIdentifierBookingDelete:         "BookingDelete",
+	MethodIdentifierBookingMakeV2:         "BookingMakeV2",
+	MethodIdentifierBookingUpdateV2:       "BookingUpdateV2",
+	MethodIdentifierSeriesMake:            "SeriesMake",
+	MethodIdentifierSeriesUpdate:          "SeriesUpdate",
+	MethodIdentifierSeriesDelete:          "SeriesDelete",
+	MethodIdentifierBookingDeleteV2:       "BookingDeleteV2",
+	MethodIdentifierWaitlistJoin:          "WaitlistJoin",
+	MethodIdentifierWaitlistLeave:         "WaitlistLeave",
+	MethodIdentifierBookingHold:           "BookingHold",
+	MethodIdentifierBookingConfirm:        "BookingConfirm",
+	MethodIdentifierBookingMakeMulti:      "BookingMakeMulti",
+	MethodIdentifierBookingResize:         "BookingResize",
+	MethodIdentifierBookingList:           "BookingList",
+	MethodIdentifierAuditQuery:            "AuditQuery",
+}
+
+// readOnlyMethods are the methods that do not change any facilities or bookings.
+var readOnlyMethods = map[MethodIdentifier]bool{
+	MethodIdentifierFacilityQuery:         true,
+	MethodIdentifierFacilityMonitor:       true,
+	MethodIdentifierFacilityQueryV2:       true,
+	MethodIdentifierFacilityQueryCapacity: true,
+	MethodIdentifierFacilityFreeSlots:     true,
+	MethodIdentifierFacilityCommonFree:    true,
+	MethodIdentifierFacilityList:          true,
+	MethodIdentifierAuditQuery:            true,
+	MethodIdentifierBookingList:           true,
+}
+
+// ReadOnly reports whether m does not change any facilities or bookings, and can safely be executed again.
+func (m MethodIdentifier) ReadOnly() bool {
+	return readOnlyMethods[m]
+}
+
+func (m MethodIdentifier) String() string {
+	if name, ok := methodNames[m]; ok {
+		return name
+	}
+	return fmt.Sprintf("MethodIdentifier(0x%02X)", uint8(m))
+}
+
+// ParseMethodIdentifier returns the MethodIdentifier with the given name, e.g. "BookingMake".
+func ParseMethodIdentifier(name string) (MethodIdentifier, error) {
+	for m, n := range methodNames {
+		if n == name {
+			return m, nil
+		}
+	}
+	return 0, fmt.Errorf("unknown method %q", name)
+}
@@ -0,0 +1,420 @@
+package request
+
+import (
+	"github.com/google/go-cmp/cmp"
+	"math"
+	"server/internal/bookings"
+	"testing"
+	"time"
+)
+
+func TestBookingMakePayloadV2_FullCycle(t *testing.T) {
+
+	now := time.Now()
+	original := NewBookingMakePayloadV2("TestBookingMakePayloadV2_FullCycle", now, now.Add(time.Duration(95)*time.Minute))
+
+	if !original.Start.Equal(now.Truncate(time.Minute)) {
+		t.Logf("E: %v", now.Truncate(time.Minute))
+		t.Logf("R: %v", original.Start)
+		t.Error("Start time does not match correct minute")
+	}
+
+	binary, err := original.MarshalBinary()
+	if err != nil {
+		t.Error(err)
+	}
+
+	var reconstructed BookingMakePayloadV2
+	if err := reconstructed.UnmarshalBinary(binary); err != nil {
+		t.Error(err)
+	}
+
+	if !original.Start.Equal(reconstructed.Start) || !original.End.Equal(reconstructed.End) || original.Name != reconstructed.Name {
+		t.Logf("E: %v", *original)
+		t.Logf("R: %v", reconstructed)
+		t.Error("Original and reconstructed BookingMakePayloadV2's do not match")
+	}
+}
+
+func TestBookingMakePayloadV2_UnmarshalBinary_unsupportedVersion(t *testing.T) {
+
+	data, _ := NewBookingMakePayloadV2("TestBookingMakePayloadV2_UnmarshalBinary_unsupportedVersion", time.Now(), time.Now()).MarshalBinary()
+	data[0] = 0xFF
+
+	var p BookingMakePayloadV2
+	if err := p.UnmarshalBinary(data); err == nil {
+		t.Error("Expected unsupported payload version to be rejected")
+	}
+}
+
+func TestBookingMakePayloadV2_GetBooking(t *testing.T) {
+
+	start := time.Now().Truncate(time.Hour).Add(time.Duration(24)*time.Hour + time.Duration(30)*time.Minute)
+
+	aligned := NewBookingMakePayloadV2("TestBookingMakePayloadV2_GetBooking", start, start.Add(time.Duration(45)*time.Minute))
+	if _, err := aligned.GetBooking(time.Duration(15) * time.Minute); err != nil {
+		t.Error(err)
+	}
+
+	misaligned := NewBookingMakePayloadV2("TestBookingMakePayloadV2_GetBooking", start, start.Add(time.Duration(50)*time.Minute))
+	if _, err := misaligned.GetBooking(time.Duration(15) * time.Minute); err == nil {
+		t.Error("Expected booking not ending on a slot to be rejected")
+	}
+}
+
+func TestBookingModifyPayloadV2_MarshalUnmarshalBinary(t *testing.T) {
+
+	for _, delta := range []time.Duration{time.Duration(90) * time.Minute, time.Duration(-925023) * time.Minute} {
+		payload := NewBookingModifyPayloadV2(2532, delta)
+
+		bin, err := payload.MarshalBinary()
+		if err != nil {
+			t.Error(err)
+		}
+
+		var reflected BookingModifyPayloadV2
+		if err := reflected.UnmarshalBinary(bin); err != nil {
+			t.Error(err)
+		}
+
+		if !cmp.Equal(reflected, *payload) {
+			t.Logf("E: %v", *payload)
+			t.Logf("R: %v", reflected)
+			t.Error("Reflected payload does not match original")
+		}
+
+		if d, err := reflected.GetDelta(time.Minute); err != nil || d != delta {
+			t.Errorf("E: %v, R: %v (%v)", delta, d, err)
+		}
+	}
+}
+
+func TestSeriesMakePayload_MarshalUnmarshalBinary(t *testing.T) {
+
+	start := time.Now().Truncate(time.Minute)
+	payload := &SeriesMakePayload{
+		Name:       "TestSeriesMakePayload_MarshalUnmarshalBinary",
+		Frequency:  bookings.FrequencyMonthly,
+		Start:      start,
+		End:        start.Add(time.Hour),
+		Count:      12,
+		Exceptions: []time.Time{start.AddDate(0, 1, 0), start.AddDate(0, 3, 0)},
+	}
+
+	bin, err := payload.MarshalBinary()
+	if err != nil {
+		t.Error(err)
+	}
+
+	var reflected SeriesMakePayload
+	if err := reflected.UnmarshalBinary(bin); err != nil {
+		t.Error(err)
+	}
+
+	if !cmp.Equal(reflected, *payload, cmp.Comparer(time.Time.Equal)) {
+		t.Logf("E: %v", *payload)
+		t.Logf("R: %v", reflected)
+		t.Error("Reflected payload does not match original")
+	}
+}
+
+func TestFacilityCreatePayloadV2_MarshalUnmarshalBinary(t *testing.T) {
+	p := NewFacilityCreatePayloadV2("Rooms", 3)
+
+	data, err := p.MarshalBinary()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var decoded FacilityCreatePayloadV2
+	if err := decoded.UnmarshalBinary(data); err != nil {
+		t.Fatal(err)
+	}
+	if decoded != *p {
+		t.Errorf("E: %v, R: %v", *p, decoded)
+	}
+}
+
+func TestFacilityInfoPayload_MarshalUnmarshalBinary(t *testing.T) {
+	hours := make([]bookings.OpeningHours, 7)
+	hours[time.Monday] = bookings.OpeningHours{Open: time.Duration(510) * time.Minute, Close: time.Duration(22) * time.Hour}
+	p := NewFacilityInfoPayload("Rooms", 3, bookings.FacilityInfo{
+		Description: "Seminar rooms",
+		Location:    "Level 2",
+		Timezone:    "Asia/Singapore",
+		Hours:       hours,
+	})
+
+	data, err := p.MarshalBinary()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var decoded FacilityInfoPayload
+	if err := decoded.UnmarshalBinary(data); err != nil {
+		t.Fatal(err)
+	}
+	if !cmp.Equal(decoded, *p) {
+		t.Errorf("E: %v, R: %v", *p, decoded)
+	}
+
+	if err := decoded.UnmarshalBinary(data[:len(data)-len(p.Info.Timezone)-1]); err == nil {
+		t.Error("Expected truncated payload to be rejected")
+	}
+}
+
+func TestPayloadV3_MarshalUnmarshalBinary(t *testing.T) {
+	start := time.Now().Truncate(time.Minute)
+
+	make3 := NewBookingMakePayloadV3("Rooms", start, start.Add(time.Hour))
+	modify3 := NewBookingModifyPayloadV3(math.MaxUint64-1, -time.Duration(90)*time.Minute)
+	delete3 := NewBookingDeletePayloadV3(math.MaxUint64 - 1)
+
+	cases := []struct {
+		name    string
+		payload interface {
+			MarshalBinary() ([]byte, error)
+			UnmarshalBinary([]byte) error
+		}
+		decoded interface{ UnmarshalBinary([]byte) error }
+	}{
+		{name: "make", payload: make3, decoded: &BookingMakePayloadV2{}},
+		{name: "modify", payload: modify3, decoded: &BookingModifyPayloadV3{}},
+		{name: "delete", payload: delete3, decoded: &BookingDeletePayloadV3{}},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			data, err := c.payload.MarshalBinary()
+			if err != nil {
+				t.Fatal(err)
+			}
+			if v := GetPayloadVersion(data); v != PayloadVersion3 {
+				t.Fatalf("E: version %d, R: %d", PayloadVersion3, v)
+			}
+			if err := c.decoded.UnmarshalBinary(data); err != nil {
+				t.Fatal(err)
+			}
+			if !cmp.Equal(c.decoded, c.payload, cmp.Comparer(time.Time.Equal)) {
+				t.Errorf("E: %v, R: %v", c.payload, c.decoded)
+			}
+		})
+	}
+}
+
+func TestBookingMakeMultiPayload_MarshalUnmarshalBinary(t *testing.T) {
+	start := time.Now().Truncate(time.Minute)
+	p := NewBookingMakeMultiPayload(
+		NewBookingMakePayloadV3("Hall", start, start.Add(time.Hour)),
+		NewBookingMakePayloadV3("Projector", start.Add(time.Hour), start.Add(time.Duration(2)*time.Hour)),
+	)
+
+	data, err := p.MarshalBinary()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var decoded BookingMakeMultiPayload
+	if err := decoded.UnmarshalBinary(data); err != nil {
+		t.Fatal(err)
+	}
+	if !cmp.Equal(decoded, *p, cmp.Comparer(time.Time.Equal)) {
+		t.Errorf("E: %v, R: %v", *p, decoded)
+	}
+
+	if err := decoded.UnmarshalBinary(data[:len(data)-1]); err == nil {
+		t.Error("Expected truncated payload to be rejected")
+	}
+}
+
+func TestFacilityFreeSlotsPayload_MarshalUnmarshalBinary(t *testing.T) {
+	start := time.Now().Truncate(time.Minute)
+	p := NewFacilityFreeSlotsPayload("Hall", start, start.Add(time.Duration(48)*time.Hour), time.Duration(90)*time.Minute, 5, true)
+
+	data, err := p.MarshalBinary()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var decoded FacilityFreeSlotsPayload
+	if err := decoded.UnmarshalBinary(data); err != nil {
+		t.Fatal(err)
+	}
+	if !cmp.Equal(decoded, *p, cmp.Comparer(time.Time.Equal)) {
+		t.Errorf("E: %v, R: %v", *p, decoded)
+	}
+	if !decoded.WithinHours() {
+		t.Error("Expected search to be restricted to opening hours")
+	}
+
+	if err := decoded.UnmarshalBinary(data[:12]); err == nil {
+		t.Error("Expected truncated payload to be rejected")
+	}
+}
+
+func TestFacilityCommonFreePayload_MarshalUnmarshalBinary(t *testing.T) {
+	start := time.Now().Truncate(time.Minute)
+	p := NewFacilityCommonFreePayload([]string{"Hall", "Projector"}, start, start.Add(time.Duration(8)*time.Hour), time.Hour)
+
+	data, err := p.MarshalBinary()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var decoded FacilityCommonFreePayload
+	if err := decoded.UnmarshalBinary(data); err != nil {
+		t.Fatal(err)
+	}
+	if !cmp.Equal(decoded, *p, cmp.Comparer(time.Time.Equal)) {
+		t.Errorf("E: %v, R: %v", *p, decoded)
+	}
+
+	if err := decoded.UnmarshalBinary(data[:len(data)-1]); err == nil {
+		t.Error("Expected truncated payload to be rejected")
+	}
+}
+
+func TestFacilityQueryPayloadV5_MarshalUnmarshalBinary(t *testing.T) {
+	p := NewFacilityQueryPayloadV5("Hall", time.Date(2030, time.March, 4, 0, 0, 0, 0, time.UTC), "Asia/Singapore", 7, time.Hour)
+
+	data, err := p.MarshalBinary()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var decoded FacilityQueryPayloadV2
+	if err := decoded.UnmarshalBinary(data); err != nil {
+		t.Fatal(err)
+	}
+	if !cmp.Equal(decoded, *p, cmp.Comparer(time.Time.Equal)) {
+		t.Errorf("E: %v, R: %v", *p, decoded)
+	}
+
+	first, err := decoded.GetFirstDay()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if e := time.Date(2030, time.March, 3, 16, 0, 0, 0, time.UTC); !first.Equal(e) {
+		t.Errorf("E: %v, R: %v", e, first)
+	}
+
+	// Dates that do not exist and unknown timezones are rejected
+	data[7] = 32
+	if err := decoded.UnmarshalBinary(data); err == nil {
+		t.Error("Expected invalid date to be rejected")
+	}
+	decoded = *NewFacilityQueryPayloadV5("Hall", time.Now(), "Nowhere/Special", 1, time.Hour)
+	if _, err := decoded.GetFirstDay(); err == nil {
+		t.Error("Expected unknown timezone to be rejected")
+	}
+}
+
+func TestAuditQueryPayload_MarshalUnmarshalBinary(t *testing.T) {
+	from := time.Now().Truncate(time.Minute)
+	for name, filter := range map[string]bookings.AuditFilter{
+		"Everything": {},
+		"Filtered":   {Facility: "Hall", BookingId: math.MaxUint64, From: from, To: from.Add(time.Hour), AfterSeq: 42},
+	} {
+		t.Run(name, func(t *testing.T) {
+			data, err := NewAuditQueryPayload(filter).MarshalBinary()
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			var decoded AuditQueryPayload
+			if err := decoded.UnmarshalBinary(data); err != nil {
+				t.Fatal(err)
+			}
+			if !cmp.Equal(decoded.Filter, filter, cmp.Comparer(time.Time.Equal)) {
+				t.Errorf("E: %v, R: %v", filter, decoded.Filter)
+			}
+		})
+	}
+}
+
+func TestBookingResizePayload_MarshalUnmarshalBinary(t *testing.T) {
+	end := time.Now().Truncate(time.Hour).Add(time.Duration(30) * time.Hour)
+	p := NewBookingResizePayload(1<<40, time.Time{}, end)
+
+	data, err := p.MarshalBinary()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var decoded BookingResizePayload
+	if err := decoded.UnmarshalBinary(data); err != nil {
+		t.Fatal(err)
+	}
+	if !cmp.Equal(decoded, *p, cmp.Comparer(time.Time.Equal)) {
+		t.Errorf("E: %v, R: %v", *p, decoded)
+	}
+
+	// Unchanged start is kept zero, and the end must be a multiple of the slot
+	if start, _, err := decoded.GetTimes(time.Hour); err != nil || !start.IsZero() {
+		t.Errorf("Expected unchanged start, got %v (%v)", start, err)
+	}
+	decoded.End = end.Add(time.Duration(10) * time.Minute)
+	if _, _, err := decoded.GetTimes(time.Duration(15) * time.Minute); err == nil {
+		t.Error("Expected unaligned end to be rejected")
+	}
+	if _, _, err := NewBookingResizePayload(1, time.Time{}, time.Time{}).GetTimes(time.Minute); err == nil {
+		t.Error("Expected resize without a new start or end to be rejected")
+	}
+}
+
+func TestFacilityListPayload_MarshalUnmarshalBinary(t *testing.T) {
+	p := NewFacilityListPayload(bookings.FacilityListFilter{
+		Prefix:   "Hall",
+		Contains: "B",
+		Sort:     bookings.FacilitySortBookings,
+		After:    bookings.FacilityCursor{Name: "Hall A", Bookings: 3},
+	}, 10)
+
+	data, err := p.MarshalBinary()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var decoded FacilityListPayload
+	if err := decoded.UnmarshalBinary(data); err != nil {
+		t.Fatal(err)
+	}
+	if !cmp.Equal(decoded, *p) {
+		t.Errorf("E: %v, R: %v", *p, decoded)
+	}
+
+	if err := decoded.UnmarshalBinary(data[:len(data)-1]); err == nil {
+		t.Error("Expected truncated payload to be rejected")
+	}
+	data[1] = 0xFF
+	if err := decoded.UnmarshalBinary(data); err == nil {
+		t.Error("Expected unknown sort to be rejected")
+	}
+}
+
+func TestBookingListPayload_MarshalUnmarshalBinary(t *testing.T) {
+	start := time.Now().Truncate(time.Minute)
+	after := bookings.BookingCursor{Start: start.Add(time.Hour), Id: 1 << 40}
+
+	for _, p := range []*BookingListPayload{
+		NewBookingListPayload("Hall", start, start.Add(time.Duration(24)*time.Hour), bookings.BookingCursor{}, 0),
+		NewBookingListPayload("Hall", start, start.Add(time.Duration(24)*time.Hour), after, 20),
+	} {
+		data, err := p.MarshalBinary()
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		var decoded BookingListPayload
+		if err := decoded.UnmarshalBinary(data); err != nil {
+			t.Fatal(err)
+		}
+		if !cmp.Equal(decoded, *p, cmp.Comparer(time.Time.Equal)) {
+			t.Errorf("E: %v, R: %v", *p, decoded)
+		}
+	}
+
+	if _, err := NewBookingListPayload("Hall", start, start, after, 0x100).MarshalBinary(); err == nil {
+		t.Error("Expected limit beyond a byte to be rejected")
+	}
+}
@@ -0,0 +1,43 @@
+package request
+
+import (
+	"fmt"
+	"time"
+)
+
+// PayloadVersion is the first byte of versioned payloads, allowing their format to change without a new method.
+// Payloads of the original methods are unversioned.
+type PayloadVersion uint8
+
+const (
+	PayloadVersion2 PayloadVersion = 0x02 // Times and shifts at minute resolution
+	PayloadVersion3 PayloadVersion = 0x03 // Booking Ids of 64 bits
+	PayloadVersion5 PayloadVersion = 0x05 // Queries from a chosen date in a chosen timezone
+)
+
+// GetPayloadVersion returns the version of a versioned payload, for methods accepting several versions.
+func GetPayloadVersion(data []byte) PayloadVersion {
+	if len(data) < 1 {
+		return 0
+	}
+	return PayloadVersion(data[0])
+}
+
+// checkVersion returns an error unless data starts with the expected payload version.
+func checkVersion(data []byte, expected PayloadVersion) error {
+	if len(data) < 1 {
+		return fmt.Errorf("payload is empty, expected version %d", expected)
+	}
+	if v := PayloadVersion(data[0]); v != expected {
+		return fmt.Errorf("unsupported payload version %d, expected %d", v, expected)
+	}
+	return nil
+}
+
+// checkAligned returns an error unless d is a multiple of slot.
+func checkAligned(name string, d time.Duration, slot time.Duration) error {
+	if d%slot != 0 {
+		return fmt.Errorf("%s must be a multiple of %v", name, slot)
+	}
+	return nil
+}
@@ -0,0 +1,49 @@
+package request
+
+import (
+	"errors"
+	"fmt"
+)
+
+// PayloadVersion4 wraps any other versioned payload with the principal of the caller, encoded as:
+//
+//	[version uint8][principal length uint8][principal][payload]
+//
+// so that the principal can be supplied to any method accepting versioned payloads.
+const PayloadVersion4 PayloadVersion = 0x04
+
+// WithPrincipal wraps the versioned payload with principal.
+func WithPrincipal(principal string, payload []byte) ([]byte, error) {
+	if principal == "" || len(principal) > 0xFF {
+		return nil, fmt.Errorf("principal must be between 1 and %d bytes", 0xFF)
+	}
+	switch v := GetPayloadVersion(payload); {
+	case v < PayloadVersion2 || v == PayloadVersion4:
+		return nil, errors.New("principal can only be supplied with versioned payloads")
+	case v == PayloadVersion6:
+		return nil, errors.New("principal must be wrapped by the idempotency key, not wrap it")
+	}
+
+	data := make([]byte, 2, 2+len(principal)+len(payload))
+	data[0] = byte(PayloadVersion4)
+	data[1] = byte(len(principal))
+	data = append(data, principal...)
+	return append(data, payload...), nil
+}
+
+// SplitPrincipal returns the principal and the payload it wraps, for payloads made with WithPrincipal. Other payloads
+// are returned as is, without a principal.
+func SplitPrincipal(data []byte) (string, []byte, error) {
+	if GetPayloadVersion(data) != PayloadVersion4 {
+		return "", data, nil
+	}
+	if len(data) < 2 || len(data) < 2+int(data[1]) {
+		return "", nil, fmt.Errorf("payload is too short for principal: %d", len(data))
+	}
+
+	principal, payload := string(data[2:2+int(data[1])]), data[2+int(data[1]):]
+	if GetPayloadVersion(payload) == PayloadVersion4 {
+		return "", nil, errors.New("principal must only be supplied once")
+	}
+	return principal, payload, nil
+}
@@ -0,0 +1,90 @@
+package request
+
+import (
+	"bytes"
+	"testing"
+)
+
+func TestWithPrincipal_SplitPrincipal(t *testing.T) {
+	payload, err := NewBookingDeletePayloadV3(42).MarshalBinary()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	data, err := WithPrincipal("alice", payload)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if v := GetPayloadVersion(data); v != PayloadVersion4 {
+		t.Fatalf("E: version %d, R: %d", PayloadVersion4, v)
+	}
+
+	principal, split, err := SplitPrincipal(data)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if principal != "alice" || !bytes.Equal(split, payload) {
+		t.Errorf("E: %q %v, R: %q %v", "alice", payload, principal, split)
+	}
+
+	// Payloads without a principal are returned as is
+	if principal, split, err := SplitPrincipal(payload); err != nil || principal != "" || !bytes.Equal(split, payload) {
+		t.Errorf("Expected payload without principal to be returned as is, got %q %v (%v)", principal, split, err)
+	}
+
+	// A principal must only wrap versioned payloads, once
+	if _, err := WithPrincipal("", payload); err == nil {
+		t.Error("Expected empty principal to be rejected")
+	}
+	if _, err := WithPrincipal("alice", data); err == nil {
+		t.Error("Expected payload with a principal to be rejected")
+	}
+	if _, _, err := SplitPrincipal(data[:3]); err == nil {
+		t.Error("Expected truncated principal to be rejected")
+	}
+}
+
+func TestWithIdempotencyKey_SplitIdempotencyKey(t *testing.T) {
+	payload, err := NewBookingDeletePayloadV3(42).MarshalBinary()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if payload, err = WithPrincipal("alice", payload); err != nil {
+		t.Fatal(err)
+	}
+
+	data, err := WithIdempotencyKey("retry-1", payload)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if v := GetPayloadVersion(data); v != PayloadVersion6 {
+		t.Fatalf("E: version %d, R: %d", PayloadVersion6, v)
+	}
+
+	key, split, err := SplitIdempotencyKey(data)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if key != "retry-1" || !bytes.Equal(split, payload) {
+		t.Errorf("E: %q %v, R: %q %v", "retry-1", payload, key, split)
+	}
+
+	// Payloads without a key are returned as is
+	if key, split, err := SplitIdempotencyKey(payload); err != nil || key != "" || !bytes.Equal(split, payload) {
+		t.Errorf("Expected payload without idempotency key to be returned as is, got %q %v (%v)", key, split, err)
+	}
+
+	// A key must only wrap versioned payloads, once
+	if _, err := WithIdempotencyKey("", payload); err == nil {
+		t.Error("Expected empty idempotency key to be rejected")
+	}
+	if _, err := WithIdempotencyKey("retry-1", data); err == nil {
+		t.Error("Expected payload with an idempotency key to be rejected")
+	}
+	if _, err := WithIdempotencyKey("retry-1", nil); err == nil {
+		t.Error("Expected empty payload to be rejected")
+	}
+	if _, _, err := SplitIdempotencyKey(data[:3]); err == nil {
+		t.Error("Expected truncated idempotency key to be rejected")
+	}
+}
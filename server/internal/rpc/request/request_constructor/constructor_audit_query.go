@@ -0,0 +1,39 @@
+package request_constructor
+
+import (
+	"server/internal/bookings"
+	"server/internal/interfaces"
+	"server/internal/protocol"
+	"server/internal/protocol/proto_defs"
+	"server/internal/rpc/request"
+)
+
+// NewAuditQueryPacket queries the entries of the audit log selected by filter.
+func NewAuditQueryPacket(filter bookings.AuditFilter) interfaces.RpcRequestConstructor {
+	return func() ([]*protocol.Packet, error) {
+		payload := request.NewAuditQueryPayload(filter)
+		payloadBytes, err := payload.MarshalBinary()
+		if err != nil {
+			return nil, err
+		}
+
+		r := request.Request{
+			MethodIdentifier: request.MethodIdentifierAuditQuery,
+			Payload:          payloadBytes,
+		}
+
+		headerDistilled := &protocol.PacketHeaderDistilled{
+			Version:     proto_defs.ProtocolV1,
+			MessageId:   proto_defs.NewMessageId(),
+			MessageType: proto_defs.MessageTypeRequest,
+			RequireAck:  true,
+		}
+
+		message, err := protocol.NewMessage(headerDistilled, &r)
+		if err != nil {
+			return nil, err
+		}
+
+		return message.ToPackets()
+	}
+}
@@ -0,0 +1,38 @@
+package request_constructor
+
+import (
+	"server/internal/interfaces"
+	"server/internal/protocol"
+	"server/internal/protocol/proto_defs"
+	"server/internal/rpc/request"
+)
+
+// NewBookingDeleteV2Packet deletes the booking with the given 64 bit Id.
+func NewBookingDeleteV2Packet(id uint64) interfaces.RpcRequestConstructor {
+	return func() ([]*protocol.Packet, error) {
+		payload := request.NewBookingDeletePayloadV3(id)
+		payloadBytes, err := payload.MarshalBinary()
+		if err != nil {
+			return nil, err
+		}
+
+		r := request.Request{
+			MethodIdentifier: request.MethodIdentifierBookingDeleteV2,
+			Payload:          payloadBytes,
+		}
+
+		headerDistilled := &protocol.PacketHeaderDistilled{
+			Version:     proto_defs.ProtocolV1,
+			MessageId:   proto_defs.NewMessageId(),
+			MessageType: proto_defs.MessageTypeRequest,
+			RequireAck:  true,
+		}
+
+		message, err := protocol.NewMessage(headerDistilled, &r)
+		if err != nil {
+			return nil, err
+		}
+
+		return message.ToPackets()
+	}
+}
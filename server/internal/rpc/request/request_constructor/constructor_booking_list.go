@@ -0,0 +1,41 @@
+package request_constructor
+
+import (
+	"server/internal/bookings"
+	"server/internal/interfaces"
+	"server/internal/protocol"
+	"server/internal/protocol/proto_defs"
+	"server/internal/rpc/request"
+	"time"
+)
+
+// NewBookingListPacket lists up to limit bookings of the facility from start to end following after, 0 for as many as
+// fit within the response.
+func NewBookingListPacket(name string, start time.Time, end time.Time, after bookings.BookingCursor, limit int) interfaces.RpcRequestConstructor {
+	return func() ([]*protocol.Packet, error) {
+		payload := request.NewBookingListPayload(name, start, end, after, limit)
+		payloadBytes, err := payload.MarshalBinary()
+		if err != nil {
+			return nil, err
+		}
+
+		r := request.Request{
+			MethodIdentifier: request.MethodIdentifierBookingList,
+			Payload:          payloadBytes,
+		}
+
+		headerDistilled := &protocol.PacketHeaderDistilled{
+			Version:     proto_defs.ProtocolV1,
+			MessageId:   proto_defs.NewMessageId(),
+			MessageType: proto_defs.MessageTypeRequest,
+			RequireAck:  true,
+		}
+
+		message, err := protocol.NewMessage(headerDistilled, &r)
+		if err != nil {
+			return nil, err
+		}
+
+		return message.ToPackets()
+	}
+}
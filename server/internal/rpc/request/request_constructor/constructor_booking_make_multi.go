@@ -0,0 +1,40 @@
+package request_constructor
+
+import (
+	"server/internal/interfaces"
+	"server/internal/protocol"
+	"server/internal/protocol/proto_defs"
+	"server/internal/rpc/request"
+)
+
+// NewBookingMakeMultiPacket makes every booking in items together with 64 bit Ids, so that either all or none of them
+// are made. The version of the items is ignored.
+func NewBookingMakeMultiPacket(items ...*request.BookingMakePayloadV2) interfaces.RpcRequestConstructor {
+	return func() ([]*protocol.Packet, error) {
+
+		payload := request.NewBookingMakeMultiPayload(items...)
+		payloadBytes, err := payload.MarshalBinary()
+		if err != nil {
+			return nil, err
+		}
+
+		r := request.Request{
+			MethodIdentifier: request.MethodIdentifierBookingMakeMulti,
+			Payload:          payloadBytes,
+		}
+
+		headerDistilled := &protocol.PacketHeaderDistilled{
+			Version:     proto_defs.ProtocolV1,
+			MessageId:   proto_defs.NewMessageId(),
+			MessageType: proto_defs.MessageTypeRequest,
+			RequireAck:  true,
+		}
+
+		message, err := protocol.NewMessage(headerDistilled, &r)
+		if err != nil {
+			return nil, err
+		}
+
+		return message.ToPackets()
+	}
+}
@@ -0,0 +1,41 @@
+package request_constructor
+
+import (
+	"server/internal/interfaces"
+	"server/internal/protocol"
+	"server/internal/protocol/proto_defs"
+	"server/internal/rpc/request"
+)
+
+// NewFacilityDeleteV2Packet deletes the facility with a versioned payload, which may be wrapped with a principal or an
+// idempotency key.
+func NewFacilityDeleteV2Packet(name string) interfaces.RpcRequestConstructor {
+
+	return func() ([]*protocol.Packet, error) {
+		payload := request.NewFacilityDeletePayloadV2(name)
+
+		payloadByte, err := payload.MarshalBinary()
+		if err != nil {
+			return nil, err
+		}
+
+		r := request.Request{
+			MethodIdentifier: request.MethodIdentifierFacilityDeleteV2,
+			Payload:          payloadByte,
+		}
+
+		headerDistilled := &protocol.PacketHeaderDistilled{
+			Version:     proto_defs.ProtocolV1,
+			MessageId:   proto_defs.NewMessageId(),
+			MessageType: proto_defs.MessageTypeRequest,
+			RequireAck:  true,
+		}
+
+		message, err := protocol.NewMessage(headerDistilled, &r)
+		if err != nil {
+			return nil, err
+		}
+
+		return message.ToPackets()
+	}
+}
@@ -0,0 +1,39 @@
+package request_constructor
+
+import (
+	"server/internal/bookings"
+	"server/internal/interfaces"
+	"server/internal/protocol"
+	"server/internal/protocol/proto_defs"
+	"server/internal/rpc/request"
+)
+
+// NewFacilityListPacket lists up to limit facilities selected by filter, 0 for as many as fit within the response.
+func NewFacilityListPacket(filter bookings.FacilityListFilter, limit int) interfaces.RpcRequestConstructor {
+	return func() ([]*protocol.Packet, error) {
+		payload := request.NewFacilityListPayload(filter, limit)
+		payloadBytes, err := payload.MarshalBinary()
+		if err != nil {
+			return nil, err
+		}
+
+		r := request.Request{
+			MethodIdentifier: request.MethodIdentifierFacilityList,
+			Payload:          payloadBytes,
+		}
+
+		headerDistilled := &protocol.PacketHeaderDistilled{
+			Version:     proto_defs.ProtocolV1,
+			MessageId:   proto_defs.NewMessageId(),
+			MessageType: proto_defs.MessageTypeRequest,
+			RequireAck:  true,
+		}
+
+		message, err := protocol.NewMessage(headerDistilled, &r)
+		if err != nil {
+			return nil, err
+		}
+
+		return message.ToPackets()
+	}
+}
@@ -0,0 +1,40 @@
+package request_constructor
+
+import (
+	"server/internal/bookings"
+	"server/internal/interfaces"
+	"server/internal/protocol"
+	"server/internal/protocol/proto_defs"
+	"server/internal/rpc/request"
+)
+
+func NewFacilityUpdatePacket(name string, capacity int, info bookings.FacilityInfo) interfaces.RpcRequestConstructor {
+
+	return func() ([]*protocol.Packet, error) {
+		payload := request.NewFacilityInfoPayload(name, capacity, info)
+
+		payloadByte, err := payload.MarshalBinary()
+		if err != nil {
+			return nil, err
+		}
+
+		r := request.Request{
+			MethodIdentifier: request.MethodIdentifierFacilityUpdate,
+			Payload:          payloadByte,
+		}
+
+		headerDistilled := &protocol.PacketHeaderDistilled{
+			Version:     proto_defs.ProtocolV1,
+			MessageId:   proto_defs.NewMessageId(),
+			MessageType: proto_defs.MessageTypeRequest,
+			RequireAck:  true,
+		}
+
+		message, err := protocol.NewMessage(headerDistilled, &r)
+		if err != nil {
+			return nil, err
+		}
+
+		return message.ToPackets()
+	}
+}
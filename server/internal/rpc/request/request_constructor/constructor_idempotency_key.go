@@ -0,0 +1,47 @@
+package request_constructor
+
+import (
+	"errors"
+	"server/internal/interfaces"
+	"server/internal/protocol"
+	"server/internal/protocol/proto_defs"
+	"server/internal/rpc/request"
+)
+
+// WithIdempotencyKey makes the request of c with an idempotency key, so that the request is executed at most once per
+// key. The request must use a versioned payload, which may be wrapped with AsPrincipal.
+func WithIdempotencyKey(key string, c interfaces.RpcRequestConstructor) interfaces.RpcRequestConstructor {
+	return func() ([]*protocol.Packet, error) {
+		packets, err := c()
+		if err != nil {
+			return nil, err
+		}
+		if len(packets) != 1 || len(packets[0].Payload) < 1 {
+			return nil, errors.New("idempotency key can only be supplied with requests of a single packet")
+		}
+
+		payloadBytes, err := request.WithIdempotencyKey(key, packets[0].Payload[1:])
+		if err != nil {
+			return nil, err
+		}
+
+		r := request.Request{
+			MethodIdentifier: request.MethodIdentifier(packets[0].Payload[0]),
+			Payload:          payloadBytes,
+		}
+
+		headerDistilled := &protocol.PacketHeaderDistilled{
+			Version:     proto_defs.ProtocolV1,
+			MessageId:   proto_defs.NewMessageId(),
+			MessageType: proto_defs.MessageTypeRequest,
+			RequireAck:  true,
+		}
+
+		message, err := protocol.NewMessage(headerDistilled, &r)
+		if err != nil {
+			return nil, err
+		}
+
+		return message.ToPackets()
+	}
+}
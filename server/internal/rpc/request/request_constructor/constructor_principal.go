@@ -0,0 +1,46 @@
+package request_constructor
+
+import (
+	"errors"
+	"server/internal/interfaces"
+	"server/internal/protocol"
+	"server/internal/protocol/proto_defs"
+	"server/internal/rpc/request"
+)
+
+// AsPrincipal makes the request of c on behalf of principal. The request must use a versioned payload.
+func AsPrincipal(principal string, c interfaces.RpcRequestConstructor) interfaces.RpcRequestConstructor {
+	return func() ([]*protocol.Packet, error) {
+		packets, err := c()
+		if err != nil {
+			return nil, err
+		}
+		if len(packets) != 1 || len(packets[0].Payload) < 1 {
+			return nil, errors.New("principal can only be supplied with requests of a single packet")
+		}
+
+		payloadBytes, err := request.WithPrincipal(principal, packets[0].Payload[1:])
+		if err != nil {
+			return nil, err
+		}
+
+		r := request.Request{
+			MethodIdentifier: request.MethodIdentifier(packets[0].Payload[0]),
+			Payload:          payloadBytes,
+		}
+
+		headerDistilled := &protocol.PacketHeaderDistilled{
+			Version:     proto_defs.ProtocolV1,
+			MessageId:   proto_defs.NewMessageId(),
+			MessageType: proto_defs.MessageTypeRequest,
+			RequireAck:  true,
+		}
+
+		message, err := protocol.NewMessage(headerDistilled, &r)
+		if err != nil {
+			return nil, err
+		}
+
+		return message.ToPackets()
+	}
+}
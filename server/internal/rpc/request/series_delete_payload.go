@@ -0,0 +1,37 @@
+package request
+
+import (
+	"encoding/binary"
+	"fmt"
+)
+
+// SeriesDeletePayload requests every remaining occurrence of a recurring booking to be cancelled, encoded as:
+//
+//	[version uint8][series id uint16]
+type SeriesDeletePayload struct {
+	Id uint16
+}
+
+func NewSeriesDeletePayload(id uint16) *SeriesDeletePayload {
+	return &SeriesDeletePayload{
+		Id: id,
+	}
+}
+
+func (s *SeriesDeletePayload) MarshalBinary() ([]byte, error) {
+	data := []byte{byte(PayloadVersion2), 0, 0}
+	binary.BigEndian.PutUint16(data[1:3], s.Id)
+	return data, nil
+}
+
+func (s *SeriesDeletePayload) UnmarshalBinary(data []byte) error {
+	if err := checkVersion(data, PayloadVersion2); err != nil {
+		return err
+	}
+	if len(data) != 3 {
+		return fmt.Errorf("payload for SeriesDeletePayload must be 3 bytes, received: %d", len(data))
+	}
+
+	s.Id = binary.BigEndian.Uint16(data[1:3])
+	return nil
+}
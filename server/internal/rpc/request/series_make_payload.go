@@ -0,0 +1,107 @@
+package request
+
+import (
+	"encoding/binary"
+	"fmt"
+	"server/internal/bookings"
+	"time"
+)
+
+// SeriesMakePayload requests a recurring booking, encoded as:
+//
+//	[version uint8][frequency uint8][start uint32][end uint32][count uint16][until uint32]
+//	[exceptions uint8][exception uint32]...[name]
+//
+// where times are minutes since the Unix epoch, start and end are of the first occurrence, count and until are 0 for
+// none, and each exception is the start of an occurrence to skip.
+type SeriesMakePayload struct {
+	Name       bookings.FacilityName
+	Frequency  bookings.Frequency
+	Start      time.Time
+	End        time.Time
+	Count      int
+	Until      time.Time // Zero for none
+	Exceptions []time.Time
+}
+
+const seriesMakePayloadHeaderSize = 17
+
+func (s *SeriesMakePayload) MarshalBinary() ([]byte, error) {
+	if len(s.Exceptions) > 0xFF {
+		return nil, fmt.Errorf("series must not have more than %d exceptions", 0xFF)
+	}
+
+	data := make([]byte, seriesMakePayloadHeaderSize, seriesMakePayloadHeaderSize+4*len(s.Exceptions)+len(s.Name))
+	data[0] = byte(PayloadVersion2)
+	data[1] = byte(s.Frequency)
+	binary.BigEndian.PutUint32(data[2:6], toMinutes(s.Start))
+	binary.BigEndian.PutUint32(data[6:10], toMinutes(s.End))
+	binary.BigEndian.PutUint16(data[10:12], uint16(s.Count))
+	binary.BigEndian.PutUint32(data[12:16], toMinutes(s.Until))
+	data[16] = byte(len(s.Exceptions))
+	for _, e := range s.Exceptions {
+		data = binary.BigEndian.AppendUint32(data, toMinutes(e))
+	}
+	return append(data, s.Name...), nil
+}
+
+func (s *SeriesMakePayload) UnmarshalBinary(data []byte) error {
+	if err := checkVersion(data, PayloadVersion2); err != nil {
+		return err
+	}
+	if len(data) < seriesMakePayloadHeaderSize || len(data) < seriesMakePayloadHeaderSize+4*int(data[16]) {
+		return fmt.Errorf("payload for SeriesMakePayload is too short: %d", len(data))
+	}
+
+	s.Frequency = bookings.Frequency(data[1])
+	s.Start = fromMinutes(binary.BigEndian.Uint32(data[2:6]))
+	s.End = fromMinutes(binary.BigEndian.Uint32(data[6:10]))
+	s.Count = int(binary.BigEndian.Uint16(data[10:12]))
+	s.Until = fromMinutes(binary.BigEndian.Uint32(data[12:16]))
+
+	offset := seriesMakePayloadHeaderSize
+	s.Exceptions = make([]time.Time, data[16])
+	for i := range s.Exceptions {
+		s.Exceptions[i] = fromMinutes(binary.BigEndian.Uint32(data[offset : offset+4]))
+		offset += 4
+	}
+	s.Name = bookings.FacilityName(data[offset:])
+
+	return nil
+}
+
+// GetSeries returns the requested series, whose first occurrence must start and end on a multiple of slot since the
+// Unix epoch.
+func (s *SeriesMakePayload) GetSeries(slot time.Duration) (bookings.Series, error) {
+	if err := checkAligned("series start", time.Duration(s.Start.Unix())*time.Second, slot); err != nil {
+		return bookings.Series{}, err
+	}
+	if err := checkAligned("series end", time.Duration(s.End.Unix())*time.Second, slot); err != nil {
+		return bookings.Series{}, err
+	}
+
+	return bookings.NewSeries(
+		bookings.SeriesWithRandomId(),
+		bookings.SeriesWithFrequency(s.Frequency),
+		bookings.SeriesWithFirstOccurrence(s.Start, s.End),
+		bookings.SeriesWithCount(s.Count),
+		bookings.SeriesWithUntil(s.Until),
+		bookings.SeriesWithExceptions(s.Exceptions...),
+	)
+}
+
+// toMinutes returns the minutes since the Unix epoch of t, 0 for the zero time.
+func toMinutes(t time.Time) uint32 {
+	if t.IsZero() {
+		return 0
+	}
+	return uint32(t.Unix() / 60)
+}
+
+// fromMinutes returns the time at minutes since the Unix epoch, the zero time for 0.
+func fromMinutes(minutes uint32) time.Time {
+	if minutes == 0 {
+		return time.Time{}
+	}
+	return time.Unix(0, 0).Add(time.Duration(minutes) * time.Minute)
+}
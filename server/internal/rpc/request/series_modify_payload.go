@@ -0,0 +1,55 @@
+package request
+
+import (
+	"encoding/binary"
+	"fmt"
+	"time"
+)
+
+// SeriesModifyPayload requests every remaining occurrence of a recurring booking to be shifted, encoded as:
+//
+//	[version uint8][series id uint16][delta int32]
+//
+// where delta is the number of minutes to shift the occurrences by.
+type SeriesModifyPayload struct {
+	Id           uint16
+	DeltaMinutes int
+}
+
+func NewSeriesModifyPayload(id uint16, delta time.Duration) *SeriesModifyPayload {
+	return &SeriesModifyPayload{
+		Id:           id,
+		DeltaMinutes: int(delta / time.Minute),
+	}
+}
+
+func (s *SeriesModifyPayload) MarshalBinary() ([]byte, error) {
+	data := make([]byte, 7)
+	data[0] = byte(PayloadVersion2)
+	binary.BigEndian.PutUint16(data[1:3], s.Id)
+	binary.BigEndian.PutUint32(data[3:7], uint32(int32(s.DeltaMinutes)))
+	return data, nil
+}
+
+func (s *SeriesModifyPayload) UnmarshalBinary(data []byte) error {
+	if err := checkVersion(data, PayloadVersion2); err != nil {
+		return err
+	}
+	if len(data) != 7 {
+		return fmt.Errorf("payload for SeriesModifyPayload must be 7 bytes, received: %d", len(data))
+	}
+
+	s.Id = binary.BigEndian.Uint16(data[1:3])
+	s.DeltaMinutes = int(int32(binary.BigEndian.Uint32(data[3:7])))
+
+	return nil
+}
+
+// GetDelta returns the requested shift, which must be a multiple of slot.
+func (s *SeriesModifyPayload) GetDelta(slot time.Duration) (time.Duration, error) {
+	delta := time.Duration(s.DeltaMinutes) * time.Minute
+	if err := checkAligned("series shift", delta, slot); err != nil {
+		return 0, err
+	}
+	return delta, nil
+}
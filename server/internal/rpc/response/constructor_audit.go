@@ -0,0 +1,205 @@
+package response
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"server/internal/bookings"
+	"server/internal/protocol/proto_defs"
+	"time"
+)
+
+// NewAuditResponse creates a response for entries of the audit log, holding as many of entries as fit within maxBytes.
+// The payload holds the number of entries as a uint8, and whether entries have been left out as a uint8 (1 if so),
+// followed by each entry encoded as:
+//
+//	[seq uint64][time uint32][type uint8][admin uint8][booking id uint64][before state][after state]
+//	[facility length uint8][facility][actor length uint8][actor][address length uint8][address]
+//	[request id length uint8][request id]
+//
+// where time is seconds since the Unix epoch, and each state is encoded as:
+//
+//	[kind uint8][start uint32][end uint32][capacity uint16]
+//
+// where kind is 0 if there is no state, 1 for the state of a booking, or 2 for the state of a facility, and start and
+// end are minutes since the Unix epoch. The state of a facility is followed by its information:
+//
+//	[weekdays uint8][open uint16][close uint16]...
+//	[description length uint8][description][location length uint8][location][timezone length uint8][timezone]
+//
+// where weekdays is 0 for a facility that is always open or 7 for opening hours from Sunday to Saturday, each given as
+// minutes after midnight.
+func NewAuditResponse(mid proto_defs.MessageId, entries []bookings.AuditEntry, maxBytes int) *Response {
+	payload := []byte{0, 0}
+	for _, e := range entries {
+		data := appendAuditEntry(payload, e)
+		if len(data) > maxBytes || payload[0] == 0xFF {
+			payload[1] = 1
+			break
+		}
+		payload = data
+		payload[0]++
+	}
+
+	return NewResponse(
+		WithOriginalMessageId(mid),
+		WithStatusCode(StatusOk),
+		WithPayloadBytes(payload),
+	)
+}
+
+// Audit returns the entries of the audit log, and whether entries have been left out, for responses created by
+// NewAuditResponse.
+func (r *Response) Audit() ([]bookings.AuditEntry, bool, error) {
+	if len(r.Payload) < 2 {
+		return nil, false, errors.New("audit payload must be at least 2 bytes")
+	}
+
+	entries := make([]bookings.AuditEntry, r.Payload[0])
+	data := r.Payload[2:]
+	for i := range entries {
+		var err error
+		if entries[i], data, err = readAuditEntry(data); err != nil {
+			return nil, false, fmt.Errorf("audit entry %d: %w", i, err)
+		}
+	}
+	if len(data) != 0 {
+		return nil, false, fmt.Errorf("audit payload has %d trailing bytes", len(data))
+	}
+	return entries, r.Payload[1] == 1, nil
+}
+
+func appendAuditEntry(data []byte, e bookings.AuditEntry) []byte {
+	data = binary.BigEndian.AppendUint64(data, e.Seq)
+	data = binary.BigEndian.AppendUint32(data, uint32(e.Time.Unix()))
+	data = append(data, byte(e.Type), boolByte(e.Admin))
+	data = binary.BigEndian.AppendUint64(data, e.BookingId)
+	data = appendAuditState(data, e.Before)
+	data = appendAuditState(data, e.After)
+	for _, s := range []string{string(e.Facility), e.Actor, e.Address, e.RequestId} {
+		s = s[:min(len(s), 0xFF)]
+		data = append(append(data, byte(len(s))), s...)
+	}
+	return data
+}
+
+func appendAuditState(data []byte, s *bookings.AuditState) []byte {
+	if s == nil {
+		return append(data, make([]byte, 11)...)
+	}
+	// The state of a facility has no start and end
+	var start, end uint32
+	if !s.Start.IsZero() {
+		start, end = uint32(s.Start.Unix()/60), uint32(s.End.Unix()/60)
+	}
+	kind := byte(1)
+	if s.Info != nil {
+		kind = 2
+	}
+	data = append(data, kind)
+	data = binary.BigEndian.AppendUint32(data, start)
+	data = binary.BigEndian.AppendUint32(data, end)
+	data = binary.BigEndian.AppendUint16(data, uint16(s.Capacity))
+	if s.Info == nil {
+		return data
+	}
+
+	data = append(data, byte(len(s.Info.Hours)))
+	for _, h := range s.Info.Hours {
+		data = binary.BigEndian.AppendUint16(data, uint16(h.Open/time.Minute))
+		data = binary.BigEndian.AppendUint16(data, uint16(h.Close/time.Minute))
+	}
+	for _, s := range []string{s.Info.Description, s.Info.Location, s.Info.Timezone} {
+		s = s[:min(len(s), 0xFF)]
+		data = append(append(data, byte(len(s))), s...)
+	}
+	return data
+}
+
+func readAuditEntry(data []byte) (bookings.AuditEntry, []byte, error) {
+	if len(data) < 22 {
+		return bookings.AuditEntry{}, nil, errors.New("payload is too short for entry")
+	}
+
+	e := bookings.AuditEntry{
+		Seq:       binary.BigEndian.Uint64(data[0:8]),
+		Time:      time.Unix(int64(binary.BigEndian.Uint32(data[8:12])), 0),
+		Type:      bookings.MutationType(data[12]),
+		Admin:     data[13] == 1,
+		BookingId: binary.BigEndian.Uint64(data[14:22]),
+	}
+	data = data[22:]
+
+	var err error
+	if e.Before, data, err = readAuditState(data); err != nil {
+		return bookings.AuditEntry{}, nil, err
+	}
+	if e.After, data, err = readAuditState(data); err != nil {
+		return bookings.AuditEntry{}, nil, err
+	}
+
+	var fields [4]string
+	for i := range fields {
+		if len(data) < 1 || len(data) < 1+int(data[0]) {
+			return bookings.AuditEntry{}, nil, errors.New("payload is too short for entry")
+		}
+		fields[i], data = string(data[1:1+int(data[0])]), data[1+int(data[0]):]
+	}
+	e.Facility, e.Actor, e.Address, e.RequestId = bookings.FacilityName(fields[0]), fields[1], fields[2], fields[3]
+	return e, data, nil
+}
+
+func readAuditState(data []byte) (*bookings.AuditState, []byte, error) {
+	if len(data) < 11 {
+		return nil, nil, errors.New("payload is too short for state")
+	}
+	if data[0] == 0 {
+		return nil, data[11:], nil
+	}
+
+	unixTime := time.Unix(0, 0)
+
+	s := &bookings.AuditState{Capacity: int(binary.BigEndian.Uint16(data[9:11]))}
+	if start, end := binary.BigEndian.Uint32(data[1:5]), binary.BigEndian.Uint32(data[5:9]); start != 0 || end != 0 {
+		s.Start = unixTime.Add(time.Duration(start) * time.Minute)
+		s.End = unixTime.Add(time.Duration(end) * time.Minute)
+	}
+	if data[0] != 2 {
+		return s, data[11:], nil
+	}
+	data = data[11:]
+
+	// The state of a facility is followed by its information
+	if len(data) < 1 || len(data) < 1+4*int(data[0]) {
+		return nil, nil, errors.New("payload is too short for facility state")
+	}
+	s.Info = &bookings.FacilityInfo{}
+	if days := int(data[0]); days > 0 {
+		s.Info.Hours = make([]bookings.OpeningHours, days)
+		for i := range s.Info.Hours {
+			offset := 1 + 4*i
+			s.Info.Hours[i] = bookings.OpeningHours{
+				Open:  time.Duration(binary.BigEndian.Uint16(data[offset:offset+2])) * time.Minute,
+				Close: time.Duration(binary.BigEndian.Uint16(data[offset+2:offset+4])) * time.Minute,
+			}
+		}
+	}
+	data = data[1+4*int(data[0]):]
+
+	var fields [3]string
+	for i := range fields {
+		if len(data) < 1 || len(data) < 1+int(data[0]) {
+			return nil, nil, errors.New("payload is too short for facility state")
+		}
+		fields[i], data = string(data[1:1+int(data[0])]), data[1+int(data[0]):]
+	}
+	s.Info.Description, s.Info.Location, s.Info.Timezone = fields[0], fields[1], fields[2]
+	return s, data, nil
+}
+
+func boolByte(b bool) byte {
+	if b {
+		return 1
+	}
+	return 0
+}
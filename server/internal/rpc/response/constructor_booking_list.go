@@ -0,0 +1,90 @@
+package response
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"server/internal/bookings"
+	"server/internal/protocol/proto_defs"
+	"time"
+)
+
+// NewBookingListResponse creates a response listing bookings, holding as many of listed as fit within maxBytes. The
+// payload holds the number of bookings as a uint8, and whether more bookings follow as a uint8 (1 if so, either because
+// more is set or because bookings have been left out), followed by each booking encoded as:
+//
+//	[id uint64][start uint32][end uint32][held until uint32][owner length uint8][owner]
+//
+// where start and end are minutes since the Unix epoch, and held until is seconds since the Unix epoch, 0 for bookings
+// that are not held (see bookings.Booking.IsHeld). Owners longer than 255 bytes are cut short.
+func NewBookingListResponse(mid proto_defs.MessageId, listed []bookings.Booking, more bool, maxBytes int) *Response {
+	payload := []byte{0, boolByte(more)}
+	for _, b := range listed {
+		data := appendListedBooking(payload, b)
+		if len(data) > maxBytes || payload[0] == 0xFF {
+			payload[1] = 1
+			break
+		}
+		payload = data
+		payload[0]++
+	}
+
+	return NewResponse(
+		WithOriginalMessageId(mid),
+		WithStatusCode(StatusOk),
+		WithPayloadBytes(payload),
+	)
+}
+
+// Bookings returns the bookings listed, and whether more bookings follow the last one, for responses created by
+// NewBookingListResponse.
+func (r *Response) Bookings() ([]bookings.Booking, bool, error) {
+	if len(r.Payload) < 2 {
+		return nil, false, errors.New("booking list payload must be at least 2 bytes")
+	}
+
+	listed := make([]bookings.Booking, r.Payload[0])
+	data := r.Payload[2:]
+	for i := range listed {
+		var err error
+		if listed[i], data, err = readListedBooking(data); err != nil {
+			return nil, false, fmt.Errorf("booking %d: %w", i, err)
+		}
+	}
+	if len(data) != 0 {
+		return nil, false, fmt.Errorf("booking list payload has %d trailing bytes", len(data))
+	}
+	return listed, r.Payload[1] == 1, nil
+}
+
+func appendListedBooking(data []byte, b bookings.Booking) []byte {
+	data = binary.BigEndian.AppendUint64(data, b.Id)
+	data = binary.BigEndian.AppendUint32(data, uint32(b.Start.Unix()/60))
+	data = binary.BigEndian.AppendUint32(data, uint32(b.End.Unix()/60))
+	var heldUntil uint32
+	if b.IsHeld() {
+		heldUntil = uint32(b.HeldUntil.Unix())
+	}
+	data = binary.BigEndian.AppendUint32(data, heldUntil)
+	owner := b.Owner[:min(len(b.Owner), 0xFF)]
+	return append(append(data, byte(len(owner))), owner...)
+}
+
+func readListedBooking(data []byte) (bookings.Booking, []byte, error) {
+	if len(data) < 21 || len(data) < 21+int(data[20]) {
+		return bookings.Booking{}, nil, errors.New("payload is too short for booking")
+	}
+
+	unixTime := time.Unix(0, 0)
+
+	b := bookings.Booking{
+		Id:    binary.BigEndian.Uint64(data[0:8]),
+		Start: unixTime.Add(time.Duration(binary.BigEndian.Uint32(data[8:12])) * time.Minute),
+		End:   unixTime.Add(time.Duration(binary.BigEndian.Uint32(data[12:16])) * time.Minute),
+		Owner: string(data[21 : 21+int(data[20])]),
+	}
+	if heldUntil := binary.BigEndian.Uint32(data[16:20]); heldUntil != 0 {
+		b.HeldUntil = time.Unix(int64(heldUntil), 0)
+	}
+	return b, data[21+int(data[20]):], nil
+}
@@ -0,0 +1,91 @@
+package response
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"server/internal/bookings"
+	"server/internal/protocol/proto_defs"
+)
+
+// NewFacilityListResponse creates a response listing facilities, holding as many of facilities as fit within maxBytes.
+// The payload holds the number of facilities as a uint8, and whether more facilities follow as a uint8 (1 if so, either
+// because more is set or because facilities have been left out), followed by each facility encoded as:
+//
+//	[capacity uint16][bookings uint32][waitlisted uint32][name length uint8][name]
+//	[description length uint8][description][location length uint8][location][timezone length uint8][timezone]
+//
+// Strings longer than 255 bytes are cut short.
+func NewFacilityListResponse(mid proto_defs.MessageId, facilities []bookings.FacilitySummary, more bool, maxBytes int) *Response {
+	payload := []byte{0, boolByte(more)}
+	for _, f := range facilities {
+		data := appendFacilitySummary(payload, f)
+		if len(data) > maxBytes || payload[0] == 0xFF {
+			payload[1] = 1
+			break
+		}
+		payload = data
+		payload[0]++
+	}
+
+	return NewResponse(
+		WithOriginalMessageId(mid),
+		WithStatusCode(StatusOk),
+		WithPayloadBytes(payload),
+	)
+}
+
+// Facilities returns the facilities listed, and whether more facilities follow the last one, for responses created by
+// NewFacilityListResponse.
+func (r *Response) Facilities() ([]bookings.FacilitySummary, bool, error) {
+	if len(r.Payload) < 2 {
+		return nil, false, errors.New("facility list payload must be at least 2 bytes")
+	}
+
+	facilities := make([]bookings.FacilitySummary, r.Payload[0])
+	data := r.Payload[2:]
+	for i := range facilities {
+		var err error
+		if facilities[i], data, err = readFacilitySummary(data); err != nil {
+			return nil, false, fmt.Errorf("facility %d: %w", i, err)
+		}
+	}
+	if len(data) != 0 {
+		return nil, false, fmt.Errorf("facility list payload has %d trailing bytes", len(data))
+	}
+	return facilities, r.Payload[1] == 1, nil
+}
+
+func appendFacilitySummary(data []byte, f bookings.FacilitySummary) []byte {
+	data = binary.BigEndian.AppendUint16(data, uint16(f.Capacity))
+	data = binary.BigEndian.AppendUint32(data, uint32(f.Bookings))
+	data = binary.BigEndian.AppendUint32(data, uint32(f.Waitlisted))
+	for _, s := range []string{string(f.Name), f.Info.Description, f.Info.Location, f.Info.Timezone} {
+		s = s[:min(len(s), 0xFF)]
+		data = append(append(data, byte(len(s))), s...)
+	}
+	return data
+}
+
+func readFacilitySummary(data []byte) (bookings.FacilitySummary, []byte, error) {
+	if len(data) < 14 {
+		return bookings.FacilitySummary{}, nil, errors.New("payload is too short for facility")
+	}
+
+	f := bookings.FacilitySummary{
+		Capacity:   int(binary.BigEndian.Uint16(data[0:2])),
+		Bookings:   int(binary.BigEndian.Uint32(data[2:6])),
+		Waitlisted: int(binary.BigEndian.Uint32(data[6:10])),
+	}
+	data = data[10:]
+
+	var fields [4]string
+	for i := range fields {
+		if len(data) < 1 || len(data) < 1+int(data[0]) {
+			return bookings.FacilitySummary{}, nil, errors.New("payload is too short for facility")
+		}
+		fields[i], data = string(data[1:1+int(data[0])]), data[1+int(data[0]):]
+	}
+	f.Name, f.Info.Description, f.Info.Location, f.Info.Timezone = bookings.FacilityName(fields[0]), fields[1], fields[2], fields[3]
+	return f, data, nil
+}
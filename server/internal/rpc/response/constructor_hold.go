@@ -0,0 +1,29 @@
+package response
+
+import (
+	"encoding/binary"
+	"errors"
+	"server/internal/protocol/proto_defs"
+	"time"
+)
+
+// NewHoldResponse creates a response for a booking that has been held. The payload holds the 64 bit Id of the booking
+// followed by when the booking is released unless confirmed, as seconds since the Unix epoch in a big endian uint32.
+func NewHoldResponse(mid proto_defs.MessageId, id uint64, heldUntil time.Time) *Response {
+	payload := binary.BigEndian.AppendUint64(make([]byte, 0, 12), id)
+	payload = binary.BigEndian.AppendUint32(payload, uint32(heldUntil.Unix()))
+
+	return NewResponse(
+		WithOriginalMessageId(mid),
+		WithStatusCode(StatusOk),
+		WithPayloadBytes(payload),
+	)
+}
+
+// Hold returns the Id of the booking and when it is released, for responses created by NewHoldResponse.
+func (r *Response) Hold() (uint64, time.Time, error) {
+	if len(r.Payload) != 12 {
+		return 0, time.Time{}, errors.New("hold payload must be 12 bytes")
+	}
+	return binary.BigEndian.Uint64(r.Payload[0:8]), time.Unix(int64(binary.BigEndian.Uint32(r.Payload[8:12])), 0), nil
+}
@@ -0,0 +1,57 @@
+package response
+
+import (
+	"encoding/binary"
+	"errors"
+	"server/internal/protocol/proto_defs"
+)
+
+// NewMultiResponse creates a response for bookings that have been made together. The payload holds the number of
+// bookings as a uint8, followed by the 64 bit Id of each booking in the order they were requested.
+func NewMultiResponse(mid proto_defs.MessageId, ids []uint64) *Response {
+	payload := make([]byte, 1, 1+8*len(ids))
+	payload[0] = byte(len(ids))
+	for _, id := range ids {
+		payload = binary.BigEndian.AppendUint64(payload, id)
+	}
+
+	return NewResponse(
+		WithOriginalMessageId(mid),
+		WithStatusCode(StatusOk),
+		WithPayloadBytes(payload),
+	)
+}
+
+// Multi returns the Ids of the bookings, for responses created by NewMultiResponse.
+func (r *Response) Multi() ([]uint64, error) {
+	if len(r.Payload) < 1 || len(r.Payload) != 1+8*int(r.Payload[0]) {
+		return nil, errors.New("multi payload does not match its number of bookings")
+	}
+
+	ids := make([]uint64, r.Payload[0])
+	for i := range ids {
+		ids[i] = binary.BigEndian.Uint64(r.Payload[1+8*i:])
+	}
+	return ids, nil
+}
+
+// NewConflictResponse creates a response for bookings requested together of which one cannot be made. The payload
+// holds the index of that booking as a uint8, followed by the reason it cannot be made.
+func NewConflictResponse(mid proto_defs.MessageId, index int, message string) *Response {
+	return NewResponse(
+		WithOriginalMessageId(mid),
+		WithStatusCode(StatusConflict),
+		WithPayloadBytes(append([]byte{byte(index)}, message...)),
+	)
+}
+
+// Conflict returns the index of the booking that cannot be made and the reason, for responses with StatusConflict.
+func (r *Response) Conflict() (int, string, error) {
+	if r.StatusCode != StatusConflict {
+		return 0, "", errors.New("response is not a conflict response")
+	}
+	if len(r.Payload) < 1 {
+		return 0, "", errors.New("conflict payload must be at least 1 byte")
+	}
+	return int(r.Payload[0]), string(r.Payload[1:]), nil
+}
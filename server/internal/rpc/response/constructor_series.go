@@ -0,0 +1,42 @@
+package response
+
+import (
+	"encoding/binary"
+	"errors"
+	"server/internal/protocol/proto_defs"
+)
+
+// NewSeriesResponse creates a response for a recurring booking that has been made. The payload holds the Id of the
+// series followed by the number of occurrences and the Id of each occurrence, all as big endian uint16s. Occurrences
+// have legacy booking Ids, which fit in a uint16.
+func NewSeriesResponse(mid proto_defs.MessageId, seriesId uint16, occurrenceIds []uint64) *Response {
+	payload := make([]byte, 4, 4+2*len(occurrenceIds))
+	binary.BigEndian.PutUint16(payload[0:2], seriesId)
+	binary.BigEndian.PutUint16(payload[2:4], uint16(len(occurrenceIds)))
+	for _, id := range occurrenceIds {
+		payload = binary.BigEndian.AppendUint16(payload, uint16(id))
+	}
+
+	return NewResponse(
+		WithOriginalMessageId(mid),
+		WithStatusCode(StatusOk),
+		WithPayloadBytes(payload),
+	)
+}
+
+// Series returns the Id of the series and the Ids of its occurrences, for responses created by NewSeriesResponse.
+func (r *Response) Series() (uint16, []uint16, error) {
+	if len(r.Payload) < 4 {
+		return 0, nil, errors.New("series payload must be at least 4 bytes")
+	}
+	n := int(binary.BigEndian.Uint16(r.Payload[2:4]))
+	if len(r.Payload) != 4+2*n {
+		return 0, nil, errors.New("series payload does not match its number of occurrences")
+	}
+
+	ids := make([]uint16, n)
+	for i := range ids {
+		ids[i] = binary.BigEndian.Uint16(r.Payload[4+2*i:])
+	}
+	return binary.BigEndian.Uint16(r.Payload[0:2]), ids, nil
+}
@@ -0,0 +1,44 @@
+package response
+
+import (
+	"encoding/binary"
+	"errors"
+	"server/internal/bookings"
+	"server/internal/protocol/proto_defs"
+	"time"
+)
+
+// NewSlotsResponse creates a response for free periods of a facility. The payload holds the number of periods as a
+// uint8, followed by the start and end of each period as minutes since the Unix epoch in big endian uint32s.
+func NewSlotsResponse(mid proto_defs.MessageId, slots []bookings.Interval) *Response {
+	payload := make([]byte, 1, 1+8*len(slots))
+	payload[0] = byte(len(slots))
+	for _, slot := range slots {
+		payload = binary.BigEndian.AppendUint32(payload, uint32(slot.Start.Unix()/60))
+		payload = binary.BigEndian.AppendUint32(payload, uint32(slot.End.Unix()/60))
+	}
+
+	return NewResponse(
+		WithOriginalMessageId(mid),
+		WithStatusCode(StatusOk),
+		WithPayloadBytes(payload),
+	)
+}
+
+// Slots returns the free periods, for responses created by NewSlotsResponse.
+func (r *Response) Slots() ([]bookings.Interval, error) {
+	if len(r.Payload) < 1 || len(r.Payload) != 1+8*int(r.Payload[0]) {
+		return nil, errors.New("slots payload does not match its number of periods")
+	}
+
+	unixTime := time.Unix(0, 0)
+
+	slots := make([]bookings.Interval, r.Payload[0])
+	for i := range slots {
+		slots[i] = bookings.Interval{
+			Start: unixTime.Add(time.Duration(binary.BigEndian.Uint32(r.Payload[1+8*i:])) * time.Minute),
+			End:   unixTime.Add(time.Duration(binary.BigEndian.Uint32(r.Payload[5+8*i:])) * time.Minute),
+		}
+	}
+	return slots, nil
+}
@@ -0,0 +1,35 @@
+package response
+
+import (
+	"encoding/binary"
+	"errors"
+	"server/internal/protocol/proto_defs"
+	"time"
+)
+
+// NewTooManyRequestsResponse creates a response telling the client to slow down. The payload holds the time after
+// which the client may retry, in milliseconds, as a big endian uint32.
+func NewTooManyRequestsResponse(mid proto_defs.MessageId, retryAfter time.Duration) *Response {
+	// Rounded up, so that the client does not retry before a token is available
+	millis := (retryAfter + time.Millisecond - 1) / time.Millisecond
+
+	payload := make([]byte, 4)
+	binary.BigEndian.PutUint32(payload, uint32(millis))
+
+	return NewResponse(
+		WithOriginalMessageId(mid),
+		WithStatusCode(StatusTooManyRequests),
+		WithPayloadBytes(payload),
+	)
+}
+
+// RetryAfter returns the time after which the client may retry, for responses with StatusTooManyRequests.
+func (r *Response) RetryAfter() (time.Duration, error) {
+	if r.StatusCode != StatusTooManyRequests {
+		return 0, errors.New("response is not a too many requests response")
+	}
+	if len(r.Payload) != 4 {
+		return 0, errors.New("retry after payload must be 4 bytes")
+	}
+	return time.Duration(binary.BigEndian.Uint32(r.Payload)) * time.Millisecond, nil
+}
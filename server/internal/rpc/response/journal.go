@@ -0,0 +1,37 @@
+package response
+
+import (
+	"server/internal/protocol/proto_defs"
+	"time"
+)
+
+type JournalRecordType uint8
+
+const (
+	JournalRecordProcessing JournalRecordType = 0x01 // Request has been received, and is about to be executed
+	JournalRecordResponse   JournalRecordType = 0x02 // Response has been generated for the request
+	JournalRecordRemoved    JournalRecordType = 0x03 // Response has been acknowledged or has expired
+)
+
+// JournalRecord describes a single change made to the requests and responses held by a History.
+type JournalRecord struct {
+	Type      JournalRecordType
+	MessageId proto_defs.MessageId
+	Response  *Response // Only for JournalRecordResponse
+	Updated   time.Time
+}
+
+// Journal durably records the requests and responses held by a History, so that duplicate requests are still filtered
+// after a restart.
+type Journal interface {
+	// Append records rec. Processing and response records must only return once rec has been persisted; removals may be
+	// persisted lazily, as replaying a stale response is harmless.
+	Append(rec JournalRecord) error
+
+	// Compact replaces all previously appended records with recs, which describe every request held by the History.
+	Compact(recs []JournalRecord) error
+}
+
+// interruptedMessage is returned for requests that were being executed when the server stopped, the outcome of which is
+// unknown. Executing them again could violate at-most-once semantics.
+const interruptedMessage = "request was interrupted by a server restart, and may or may not have been executed"
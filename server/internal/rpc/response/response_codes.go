@@ -5,10 +5,15 @@ import "net/http"
 type StatusCode uint16
 
 const (
-	StatusOk StatusCode = http.StatusOK
+	StatusOk       StatusCode = http.StatusOK
+	StatusAccepted StatusCode = http.StatusAccepted // Request has been accepted, but not carried out yet
 
 	StatusBadRequest StatusCode = http.StatusBadRequest
+	StatusForbidden  StatusCode = http.StatusForbidden
 	StatusNotFound   StatusCode = http.StatusNotFound
+	StatusConflict   StatusCode = http.StatusConflict
+
+	StatusTooManyRequests StatusCode = http.StatusTooManyRequests
 
 	StatusInternalServerError StatusCode = http.StatusInternalServerError
 )
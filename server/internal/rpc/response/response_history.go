@@ -1,12 +1,15 @@
 package response
 
 import (
+	"context"
 	"errors"
 	"fmt"
 	"log/slog"
+	"server/internal/network"
 	"server/internal/protocol/proto_defs"
 	"server/internal/vars"
 	"sync"
+	"sync/atomic"
 	"time"
 )
 
@@ -14,6 +17,8 @@ type HistoryRecord struct {
 	sync.RWMutex
 	Response *Response
 	Updated  time.Time
+
+	journaled bool // Whether the request, and therefore its response, is recorded in the journal of the History
 }
 
 func NewHistoryRecord(r *Response) *HistoryRecord {
@@ -30,31 +35,121 @@ func (h *HistoryRecord) GetResponse() *Response {
 	return h.Response
 }
 
+// journalCompactMin is the minimum number of records appended to a Journal before it is compacted.
+const journalCompactMin = 1024
+
+// History keeps track of the responses generated for each request, and is responsible for sending them out.
 type History struct {
 	sync.RWMutex
+	env       *vars.StaticEnvStruct
+	sender    *network.SendHistory
 	responses map[proto_defs.MessageId]*HistoryRecord
+
+	journal        Journal
+	journalRecords atomic.Int64 // Number of records appended to journal since it was last compacted
 }
 
-var (
-	history     *History
-	onceHistory sync.Once
-)
+func NewHistory(env *vars.StaticEnvStruct, sender *network.SendHistory) *History {
+	return &History{
+		env:       env,
+		sender:    sender,
+		responses: make(map[proto_defs.MessageId]*HistoryRecord),
+	}
+}
+
+// SetJournal records every subsequent change to h in j. Restore should be called beforehand, to load the requests
+// previously recorded in j.
+func (h *History) SetJournal(j Journal) {
+	h.Lock()
+	defer h.Unlock()
+	h.journal = j
+}
+
+// Restore loads recs, as previously appended to a Journal, into h. Responses that have expired are skipped. Requests
+// that never completed are answered with an error, rather than being executed again.
+func (h *History) Restore(recs []JournalRecord) {
+	h.Lock()
+	defer h.Unlock()
 
-func GetResponseHistoryInstance() *History {
-	onceHistory.Do(func() {
-		history = &History{responses: make(map[proto_defs.MessageId]*HistoryRecord)}
+	expiredTime := time.Now().Add(-time.Duration(h.env.ResponseTTL) * time.Millisecond)
 
-		t := time.NewTicker(time.Duration(vars.GetStaticEnv().ResponseIntervals) * time.Millisecond)
-		go func() {
-			defer t.Stop()
-			for range t.C {
-				history.CleanUp()
+	for _, rec := range recs {
+		switch rec.Type {
+		case JournalRecordProcessing:
+			h.responses[rec.MessageId] = &HistoryRecord{
+				Response:  NewErrorResponse(rec.MessageId, StatusInternalServerError, interruptedMessage),
+				Updated:   rec.Updated,
+				journaled: true,
 			}
-		}()
+		case JournalRecordResponse:
+			h.responses[rec.MessageId] = &HistoryRecord{Response: rec.Response, Updated: rec.Updated, journaled: true}
+		case JournalRecordRemoved:
+			delete(h.responses, rec.MessageId)
+		}
+	}
+
+	for id, r := range h.responses {
+		if r.Updated.Before(expiredTime) {
+			delete(h.responses, id)
+		}
+	}
+	h.journalRecords.Store(int64(len(recs)))
 
-	})
+	slog.Info("Restored responses from journal", "Records", len(recs), "Responses", len(h.responses))
+}
 
-	return history
+// appendJournal appends rec to j, the journal of h. h must not be locked, so that other requests are not held up
+// while rec is synced to disk. Records of a request are still appended in order, as each is appended before the call
+// that made it returns.
+func (h *History) appendJournal(j Journal, rec JournalRecord) {
+	if err := j.Append(rec); err != nil {
+		slog.Error("Unable to append to response journal", "MessageId", rec.MessageId, "err", err)
+		return
+	}
+	h.journalRecords.Add(1)
+}
+
+// compactJournal compacts the journal of h, if any, once most of its records are obsolete. h must be locked.
+func (h *History) compactJournal() {
+	if h.journal == nil {
+		return
+	}
+	if n := h.journalRecords.Load(); n < journalCompactMin || n < 2*int64(len(h.responses)) {
+		return
+	}
+
+	recs := make([]JournalRecord, 0, len(h.responses))
+	for id, r := range h.responses {
+		if !r.journaled {
+			continue
+		}
+		rec := JournalRecord{Type: JournalRecordResponse, MessageId: id, Response: r.Response, Updated: r.Updated}
+		if r.Response == nil {
+			rec.Type = JournalRecordProcessing
+		}
+		recs = append(recs, rec)
+	}
+
+	if err := h.journal.Compact(recs); err != nil {
+		slog.Error("Unable to compact response journal", "err", err)
+		return
+	}
+	h.journalRecords.Store(int64(len(recs)))
+}
+
+// Run periodically cleans up expired responses until ctx is cancelled.
+func (h *History) Run(ctx context.Context) {
+	t := time.NewTicker(time.Duration(h.env.ResponseIntervals) * time.Millisecond)
+	defer t.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-t.C:
+			h.CleanUp()
+		}
+	}
 }
 
 func (h *History) CleanUp() {
@@ -64,7 +159,7 @@ func (h *History) CleanUp() {
 	slog.Debug("Cleaning up expired responses")
 	count := 0
 
-	expiredTime := time.Now().Add(-time.Duration(vars.GetStaticEnv().ResponseTTL) * time.Millisecond)
+	expiredTime := time.Now().Add(-time.Duration(h.env.ResponseTTL) * time.Millisecond)
 
 	for id, r := range h.responses {
 		if r.Updated.Before(expiredTime) {
@@ -74,23 +169,42 @@ func (h *History) CleanUp() {
 	}
 	if count > 0 {
 		slog.Info(fmt.Sprintf("Cleaned up %d expired responses", count))
+		h.compactJournal()
 	} else {
 		slog.Debug("No expired responses to clean up")
 	}
 }
 
-func (h *History) SetProcessing(m proto_defs.MessageId) {
+// SetProcessing records that the request m is being executed. If journal is set, the request and its response are
+// recorded in the journal of h, if any; this is only needed for requests that make changes, as executing any other
+// request again after a restart is harmless.
+func (h *History) SetProcessing(m proto_defs.MessageId, journal bool) {
 	h.Lock()
-	defer h.Unlock()
-
-	h.responses[m] = NewHistoryRecord(nil)
+	record := NewHistoryRecord(nil)
+	record.journaled = journal && h.journal != nil
+	h.responses[m] = record
+	j := h.journal
+	h.Unlock()
+
+	if record.journaled {
+		h.appendJournal(j, JournalRecord{Type: JournalRecordProcessing, MessageId: m, Updated: record.Updated})
+	}
 }
 
+// AddResponse records r as the response to its request, journaled if the request was (see SetProcessing).
 func (h *History) AddResponse(r *Response) {
 	h.Lock()
-	defer h.Unlock()
+	record := NewHistoryRecord(r)
+	if prev, exists := h.responses[r.OriginalMessageId]; exists {
+		record.journaled = prev.journaled
+	}
+	h.responses[r.OriginalMessageId] = record
+	j := h.journal
+	h.Unlock()
 
-	h.responses[r.OriginalMessageId] = NewHistoryRecord(r)
+	if record.journaled {
+		h.appendJournal(j, JournalRecord{Type: JournalRecordResponse, MessageId: r.OriginalMessageId, Response: r, Updated: record.Updated})
+	}
 }
 
 // Check returns (response has been recorded, recorded into system)
@@ -124,6 +238,16 @@ func (h *History) GetResponse(id proto_defs.MessageId) (*Response, error) {
 
 func (h *History) RemoveResponse(id proto_defs.MessageId) {
 	h.Lock()
-	defer h.Unlock()
+	record, exists := h.responses[id]
+	if !exists {
+		h.Unlock()
+		return
+	}
 	delete(h.responses, id)
+	j := h.journal
+	h.Unlock()
+
+	if record.journaled {
+		h.appendJournal(j, JournalRecord{Type: JournalRecordRemoved, MessageId: id, Updated: time.Now()})
+	}
 }
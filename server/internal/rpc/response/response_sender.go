@@ -3,12 +3,20 @@ package response
 import (
 	"log/slog"
 	"net"
-	"server/internal/network"
 	"server/internal/protocol"
 	"server/internal/protocol/proto_defs"
 )
 
-func SendResponse(c *net.UDPConn, a *net.UDPAddr, r *Response) {
+// SendResponse records r in h, and sends it to the given address. r is recorded first, so that a client never receives a
+// response that is lost from h if the server restarts.
+//
+// Changes made by the request have already been journalled separately (see bookings.Journal), so a crash before r is
+// recorded leaves the request executed but without a response. The request is then answered with an error when
+// retransmitted, rather than executed again (see History.Restore).
+func (h *History) SendResponse(c *net.UDPConn, a *net.UDPAddr, r *Response) {
+
+	// Set response in history
+	h.AddResponse(r)
 
 	// Create response message
 	message, err := protocol.NewMessage(
@@ -32,12 +40,8 @@ func SendResponse(c *net.UDPConn, a *net.UDPAddr, r *Response) {
 	}
 
 	for _, p := range packets {
-		if err := network.SendPacket(c, a, p); err != nil {
+		if err := h.sender.SendPacket(c, a, p); err != nil {
 			slog.Error("Unable to send response message packet", "err", err)
 		}
 	}
-
-	// Set response in history
-	GetResponseHistoryInstance().AddResponse(r)
-
 }
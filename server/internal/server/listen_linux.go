@@ -0,0 +1,34 @@
+//go:build linux
+
+package server
+
+import (
+	"context"
+	"net"
+	"syscall"
+
+	"golang.org/x/sys/unix"
+)
+
+// listenUDP binds a UDP socket to addr. With reusePort, SO_REUSEPORT is set on the socket, allowing multiple sockets to
+// bind to the same port; the kernel then distributes incoming packets between the sockets by their source address.
+func listenUDP(addr string, reusePort bool) (*net.UDPConn, error) {
+	lc := net.ListenConfig{}
+	if reusePort {
+		lc.Control = func(network, address string, rc syscall.RawConn) error {
+			var opErr error
+			if err := rc.Control(func(fd uintptr) {
+				opErr = unix.SetsockoptInt(int(fd), unix.SOL_SOCKET, unix.SO_REUSEPORT, 1)
+			}); err != nil {
+				return err
+			}
+			return opErr
+		}
+	}
+
+	conn, err := lc.ListenPacket(context.Background(), "udp", addr)
+	if err != nil {
+		return nil, err
+	}
+	return conn.(*net.UDPConn), nil
+}
@@ -0,0 +1,21 @@
+//go:build !linux
+
+package server
+
+import (
+	"errors"
+	"net"
+)
+
+// listenUDP binds a UDP socket to addr. SO_REUSEPORT is only supported on Linux, therefore a single socket per port.
+func listenUDP(addr string, reusePort bool) (*net.UDPConn, error) {
+	if reusePort {
+		return nil, errors.New("multiple sockets per port (SO_REUSEPORT) is only supported on linux")
+	}
+
+	udpAddr, err := net.ResolveUDPAddr("udp", addr)
+	if err != nil {
+		return nil, err
+	}
+	return net.ListenUDP("udp", udpAddr)
+}
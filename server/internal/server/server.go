@@ -1,79 +1,424 @@
 package server
 
 import (
+	"context"
+	"errors"
 	"fmt"
 	"log/slog"
 	"net"
+	"server/internal/bookings"
 	"server/internal/handle"
+	"server/internal/handle/handle_requests"
+	"server/internal/monitor"
+	"server/internal/network"
 	"server/internal/pools"
 	"server/internal/protocol/proto_defs"
+	"server/internal/rpc/response"
+	"server/internal/store"
+	"server/internal/store/replycache"
+	"server/internal/vars"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
 )
 
-func serveOnConn(conn *net.UDPConn) {
+// Server is a single instance of the booking server. Each Server owns its own facilities, bookings, monitor and
+// histories, therefore multiple Server s can run within the same process without sharing any state.
+type Server struct {
+	wg sync.WaitGroup
 
-	defer conn.Close()
-	slog.Info(fmt.Sprintf("UDP Server listening on %s\n", conn.LocalAddr().String()))
+	env              *vars.StaticEnvStruct
+	addresses        []string
+	port             int
+	sockets          int
+	dataDir          *string
+	persistResponses *bool
 
-	// Reading packets
-	readBuffer := make([]byte, proto_defs.PacketSizeLimit)
+	manager     *bookings.Manager
+	ownManager  bool // Whether the manager was created by the Server, rather than shared through WithManager
+	store       *store.Store
+	replies     *replycache.Cache
+	sendHistory *network.SendHistory
+	responses   *response.History
+	handler     *handle.Handler
+	workers     *pools.WorkerPool
 
-	for {
-		n, addr, err := conn.ReadFromUDP(readBuffer)
-		if err != nil {
-			slog.Error("Error reading into buffer: ", "err", err)
-			continue
-		}
+	conns []*net.UDPConn
 
-		dataBuf := pools.PacketBytesPool.Get().([]byte)
-		copy(dataBuf, readBuffer[:n])
+	ctx          context.Context
+	cancel       context.CancelFunc
+	closed       chan struct{}
+	closeOnce    sync.Once
+	shutdownOnce sync.Once
+}
 
-		// Each incoming packet is spun onto its own GoRoutine; therefore for each packet, no other
-		// GoRoutine needs to be initiated.
-		go handle.IncomingPacket(conn, addr, n, dataBuf)
+type Option func(*Server)
 
-		slog.Info(fmt.Sprintf("Received %d bytes from %v\n", n, addr))
+// WithEnv sets the configuration of the Server to a copy of env, isolating it from changes made to the shared
+// configuration (e.g. through the console). By default, the Server uses vars.GetStaticEnv().
+func WithEnv(env vars.StaticEnvStruct) Option {
+	return func(s *Server) {
+		s.env = &env
 	}
+}
 
+// WithBindAddresses sets the addresses the Server listens on, e.g. "0.0.0.0" for all IPv4 addresses, "::" for all
+// IPv4 and IPv6 addresses (dual-stack), or explicit addresses such as "127.0.0.1" and "::1".
+// By default, the addresses are taken from the Server's configuration.
+func WithBindAddresses(addresses ...string) Option {
+	return func(s *Server) {
+		s.addresses = addresses
+	}
 }
 
-func Serve(port int) {
+// WithPort sets the UDP port the Server listens on; 0 lets the OS assign a free port.
+// By default, the port is taken from the Server's configuration.
+func WithPort(port int) Option {
+	return func(s *Server) {
+		s.port = port
+	}
+}
 
-	// Determine server's address on given port
-	serverAddr, err := net.ResolveUDPAddr("udp", fmt.Sprintf("0.0.0.0:%d", port))
-	if err != nil {
-		slog.Error("Error resolving server address: ", "err", err)
-		return
+// WithSockets sets the number of UDP sockets bound to the Server's port, each with its own read loop. More than one
+// socket requires SO_REUSEPORT, which is only supported on Linux. By default, the number of sockets is taken from the
+// Server's configuration.
+func WithSockets(n int) Option {
+	return func(s *Server) {
+		s.sockets = n
 	}
+}
 
-	// Create UDP listener
-	conn, err := net.ListenUDP("udp", serverAddr)
-	if err != nil {
-		slog.Error("Error starting server: ", "err", err)
-		return
+// WithDataDir sets the directory that facilities and bookings are persisted in; an empty dir keeps them in memory only.
+// By default, the directory is taken from the Server's configuration.
+func WithDataDir(dir string) Option {
+	return func(s *Server) {
+		s.dataDir = &dir
 	}
-	serveOnConn(conn)
+}
 
+// WithPersistResponses sets whether the responses used to filter duplicate requests are persisted in the data
+// directory, so that requests retransmitted across a restart are not executed again. It has no effect without a data
+// directory. By default, this is taken from the Server's configuration.
+func WithPersistResponses(enabled bool) Option {
+	return func(s *Server) {
+		s.persistResponses = &enabled
+	}
 }
 
-func ServeRandomPort() (int, error) {
-	// Resolve a UDP address with port 0 (OS assigns a free port)
-	addr, err := net.ResolveUDPAddr("udp", "0.0.0.0:0")
-	if err != nil {
-		return 0, fmt.Errorf("failed to resolve UDP address: %w", err)
+// WithManager sets the bookings.Manager used by the Server, allowing booking state to be shared between Server s.
+// The Server does not close the manager, and cannot persist it in a data directory.
+func WithManager(m *bookings.Manager) Option {
+	return func(s *Server) {
+		s.manager = m
 	}
+}
 
-	// Listen on a random available port
-	conn, err := net.ListenUDP("udp", addr)
-	if err != nil {
-		return 0, fmt.Errorf("failed to listen on a UDP port: %w", err)
+// New creates a Server and binds it to its UDP port. The Server does not handle any packets until Serve is called.
+func New(opts ...Option) (*Server, error) {
+	s := &Server{
+		env:     vars.GetStaticEnv(),
+		port:    -1, // Unset, resolved from env after options are applied
+		sockets: -1, // Unset, resolved from env after options are applied
+	}
+	for _, o := range opts {
+		o(s)
+	}
+
+	if s.port < 0 {
+		s.port = s.env.ServerPort
+	}
+	if s.sockets < 0 {
+		s.sockets = s.env.ServerSockets
+	}
+	if s.addresses == nil {
+		s.addresses = s.env.ServerBindAddresses
+	}
+	if len(s.addresses) == 0 {
+		return nil, errors.New("server requires at least 1 bind address")
 	}
+	if s.sockets < 1 {
+		return nil, fmt.Errorf("server requires at least 1 socket, got %d", s.sockets)
+	}
+	if s.dataDir == nil {
+		s.dataDir = &s.env.DataDir
+	}
+	if s.manager == nil {
+		s.manager = bookings.NewManager()
+		s.ownManager = true
+	} else if *s.dataDir != "" {
+		// Closing the store would stop journaling for every other Server sharing the manager
+		return nil, errors.New("server cannot persist a shared manager in a data directory")
+	}
+	if s.persistResponses == nil {
+		s.persistResponses = &s.env.PersistResponses
+	}
+
+	s.sendHistory = network.NewSendHistory(s.env)
+	s.responses = response.NewHistory(s.env, s.sendHistory)
+	s.handler = handle.NewHandler(
+		s.env,
+		s.sendHistory,
+		s.responses,
+		handle_requests.NewHandler(s.env, s.manager, s.responses),
+	)
+
+	var workerOpts []pools.WorkerPoolOption
+	if s.env.WorkerFairQueuing {
+		workerOpts = append(workerOpts, pools.WorkerPoolWithFairQueuing(s.env.WorkerClientQueueSize))
+	}
+
+	// Restore persisted facilities and bookings before any requests are handled
+	if *s.dataDir != "" {
+		st, err := store.Open(*s.dataDir)
+		if err != nil {
+			return nil, err
+		}
+		if err := st.Restore(s.manager); err != nil {
+			_ = st.Close()
+			return nil, err
+		}
+		s.manager.SetJournal(st)
+		s.manager.SetAuditJournal(st)
+		s.store = st
+
+		if *s.persistResponses {
+			rc, err := replycache.Open(*s.dataDir)
+			if err == nil {
+				err = rc.Restore(s.responses)
+			}
+			if err != nil {
+				_ = st.Close()
+				return nil, err
+			}
+			s.responses.SetJournal(rc)
+			s.replies = rc
+		}
+	}
+
+	// Create UDP listeners on every address; the first socket determines the port of all sockets (e.g. when port 0
+	// is given)
+	reusePort := s.sockets > 1
+	for _, address := range s.addresses {
+		host := strings.TrimSuffix(strings.TrimPrefix(address, "["), "]")
+		for i := 0; i < s.sockets; i++ {
+			conn, err := listenUDP(net.JoinHostPort(host, strconv.Itoa(s.port)), reusePort)
+			if err != nil {
+				for _, c := range s.conns {
+					_ = c.Close()
+				}
+				if s.store != nil {
+					_ = s.store.Close()
+				}
+				if s.replies != nil {
+					_ = s.replies.Close()
+				}
+				return nil, fmt.Errorf("failed to listen on a UDP port at %s: %w", address, err)
+			}
+			s.conns = append(s.conns, conn)
+			s.port = conn.LocalAddr().(*net.UDPAddr).Port
+		}
+	}
+	s.workers = pools.NewWorkerPool(s.env.WorkerPoolSize, s.env.WorkerQueueSize, workerOpts...)
+
+	s.ctx, s.cancel = context.WithCancel(context.Background())
+	s.closed = make(chan struct{})
+
+	return s, nil
+}
+
+// Port returns the UDP port that the Server is bound to.
+func (s *Server) Port() int {
+	return s.port
+}
+
+// Manager returns the bookings.Manager holding the facilities and bookings of the Server.
+func (s *Server) Manager() *bookings.Manager {
+	return s.manager
+}
+
+// Store returns the store that facilities and bookings are persisted in, nil if they are kept in memory only.
+func (s *Server) Store() *store.Store {
+	return s.store
+}
 
-	// Extract the assigned port
-	port := conn.LocalAddr().(*net.UDPAddr).Port
-	slog.Info(fmt.Sprintf("Assigned random UDP port: %d", port))
+// Serve handles incoming packets until the Server is closed. Once ctx is cancelled, the Server is gracefully shut
+// down within the configured grace period (see Shutdown). Serve returns after the Server has been closed.
+func (s *Server) Serve(ctx context.Context) error {
 
-	// Start serving packets
-	go serveOnConn(conn)
+	// Gracefully shut down once the caller cancels
+	go func() {
+		select {
+		case <-ctx.Done():
+			gracePeriod := time.Duration(s.env.ShutdownGracePeriod) * time.Millisecond
+			shutdownCtx, cancel := context.WithTimeout(context.Background(), gracePeriod)
+			defer cancel()
+			if err := s.Shutdown(shutdownCtx); err != nil {
+				slog.Warn("Server did not shut down cleanly", "err", err)
+			}
+		case <-s.ctx.Done():
+		}
+	}()
+
+	s.wg.Add(3)
+	go func() {
+		defer s.wg.Done()
+		s.sendHistory.Run(s.ctx)
+	}()
+	go func() {
+		defer s.wg.Done()
+		s.responses.Run(s.ctx)
+	}()
+	go func() {
+		defer s.wg.Done()
+		s.handler.Run(s.ctx)
+	}()
+
+	if s.store != nil && s.env.SnapshotInterval > 0 {
+		s.wg.Add(1)
+		go func() {
+			defer s.wg.Done()
+			s.runSnapshots(s.ctx)
+		}()
+	}
+
+	// Each socket has its own read loop, the state behind them is shared
+	for _, conn := range s.conns {
+		s.wg.Add(1)
+		go func() {
+			defer s.wg.Done()
+			s.serveOnConn(conn)
+		}()
+	}
+
+	<-s.closed
+	return nil
+}
+
+// Shutdown gracefully stops the Server:
+//  1. New requests are no longer accepted
+//  2. Monitor subscribers are told that the server is going away
+//  3. In-flight requests are allowed to complete
+//  4. Pending responses and acknowledgements are flushed
+//  5. A snapshot is taken, if facilities and bookings are persisted
+//  6. The Server is closed
+//
+// If ctx is done before steps 3 or 4 complete, the Server is snapshot and closed regardless, and ctx's error is
+// returned.
+func (s *Server) Shutdown(ctx context.Context) error {
+	var err error
+
+	s.shutdownOnce.Do(func() {
+		defer s.Close()
+
+		// Snapshot is taken even if the grace period is exceeded, as no further changes can be made once drained
+		defer func() {
+			if s.store == nil {
+				return
+			}
+			if snapshotErr := s.manager.Snapshot(); snapshotErr != nil {
+				slog.Error("Unable to take snapshot on shutdown", "err", snapshotErr)
+				err = errors.Join(err, snapshotErr)
+			}
+		}()
+
+		slog.Warn("Shutting down server, no longer accepting requests", "port", s.port)
+		s.handler.Drain()
+
+		// Monitoring requests are in-flight until their TTL expires, therefore they must be terminated before waiting
+		s.manager.Monitor().Shutdown("Server is shutting down.")
+
+		if err = s.handler.Wait(ctx); err != nil {
+			slog.Error("Timed out waiting for in-flight requests to complete", "err", err)
+			return
+		}
+		slog.Info("All in-flight requests completed, flushing pending packets")
+
+		if err = s.sendHistory.Flush(ctx); err != nil {
+			slog.Error("Timed out flushing pending packets", "pending", s.sendHistory.Pending(), "err", err)
+			return
+		}
+		slog.Info("All pending packets flushed")
+	})
+
+	return err
+}
+
+// Close immediately stops the Server and its background routines. Use Shutdown to stop gracefully.
+func (s *Server) Close() {
+	s.closeOnce.Do(func() {
+		s.cancel()
+		for _, conn := range s.conns {
+			_ = conn.Close()
+		}
+		s.workers.Close()
+		s.wg.Wait()
+		if s.ownManager {
+			s.manager.Close()
+		}
+		if s.store != nil {
+			if err := s.store.Close(); err != nil {
+				slog.Error("Unable to close store", "err", err)
+			}
+		}
+		if s.replies != nil {
+			if err := s.replies.Close(); err != nil {
+				slog.Error("Unable to close reply cache", "err", err)
+			}
+		}
+		close(s.closed)
+		slog.Info("Server closed", "port", s.port)
+	})
+}
+
+// runSnapshots periodically snapshots the facilities and bookings until ctx is cancelled.
+func (s *Server) runSnapshots(ctx context.Context) {
+	t := time.NewTicker(time.Duration(s.env.SnapshotInterval) * time.Millisecond)
+	defer t.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-t.C:
+			if err := s.manager.Snapshot(); err != nil {
+				slog.Error("Unable to take snapshot", "err", err)
+			}
+		}
+	}
+}
+
+func (s *Server) serveOnConn(conn *net.UDPConn) {
+
+	slog.Info(fmt.Sprintf("UDP Server listening on %s\n", conn.LocalAddr().String()))
+
+	// Reading packets
+	readBuffer := make([]byte, proto_defs.PacketSizeLimit)
+
+	for {
+		n, addr, err := conn.ReadFromUDP(readBuffer)
+		if err != nil {
+			if errors.Is(err, net.ErrClosed) || s.ctx.Err() != nil {
+				return
+			}
+			slog.Error("Error reading into buffer: ", "err", err)
+			continue
+		}
+
+		dataBuf := pools.PacketBytesPool.Get().([]byte)
+		copy(dataBuf, readBuffer[:n])
+
+		slog.Info(fmt.Sprintf("Received %d bytes from %v\n", n, addr))
+
+		// Each incoming packet is handled by a worker; packets are dropped when the queue is full, the client is
+		// expected to retransmit them.
+		if !s.workers.Submit(network.AddrKey(addr), func() {
+			s.handler.IncomingPacket(conn, addr, n, dataBuf)
+		}) {
+			monitor.MarkPacketInOverflow()
+			pools.PacketBytesPool.Put(dataBuf)
+			slog.Warn(fmt.Sprintf("[IN:OVERFLOW] Worker queue full, dropping %d bytes from %v", n, addr))
+		}
+	}
 
-	return port, nil
 }
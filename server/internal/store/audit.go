@@ -0,0 +1,126 @@
+package store
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"server/internal/bookings"
+)
+
+const auditFileName = "audit.log"
+
+// restoreAudit opens the audit log, discarding a torn entry at its end. Must be called with the lock held.
+func (s *Store) restoreAudit() error {
+	path := filepath.Join(s.dir, auditFileName)
+
+	var end int64
+	if err := readAudit(path, -1, func(e bookings.AuditEntry, offset int64) bool {
+		s.auditSeq, end = e.Seq, offset
+		return true
+	}); err != nil {
+		return fmt.Errorf("unable to read audit log: %w", err)
+	}
+
+	audit, err := os.OpenFile(path, os.O_CREATE|os.O_RDWR, 0o644)
+	if err != nil {
+		return fmt.Errorf("unable to open audit log: %w", err)
+	}
+	s.audit = audit
+	if err := s.audit.Truncate(end); err != nil {
+		return fmt.Errorf("unable to truncate audit log: %w", err)
+	}
+	if _, err := s.audit.Seek(end, 0); err != nil {
+		return fmt.Errorf("unable to seek audit log: %w", err)
+	}
+	s.auditBytes = end
+	return nil
+}
+
+// AppendAudit writes e to the audit log, and syncs it to disk. The audit log is append-only, and is not truncated by
+// snapshots. As with Append, an entry that cannot be written in full is removed again, and no further entries are
+// appended if that fails too.
+func (s *Store) AppendAudit(e bookings.AuditEntry) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.audit == nil {
+		return fmt.Errorf("store is not open")
+	}
+	if s.auditFailed != nil {
+		return fmt.Errorf("audit log has failed: %w", s.auditFailed)
+	}
+
+	body, err := json.Marshal(e)
+	if err != nil {
+		return err
+	}
+	data := Frame(body)
+	if failed, err := appendSynced(s.audit, s.auditBytes, data); err != nil {
+		if failed {
+			slog.Error("Audit log may end in a torn entry, refusing further entries", "Dir", s.dir, "err", err)
+			s.auditFailed = err
+		}
+		return err
+	}
+
+	s.auditSeq = e.Seq
+	s.auditBytes += int64(len(data))
+	return nil
+}
+
+// ReadAudit calls fn with every entry of the audit log, oldest first, until fn returns false. Entries appended while
+// reading are not included.
+func (s *Store) ReadAudit(fn func(e bookings.AuditEntry) bool) error {
+	s.mu.Lock()
+	if s.audit == nil {
+		s.mu.Unlock()
+		return fmt.Errorf("store is not open")
+	}
+	size := s.auditBytes
+	s.mu.Unlock()
+
+	return readAudit(filepath.Join(s.dir, auditFileName), size, func(e bookings.AuditEntry, _ int64) bool {
+		return fn(e)
+	})
+}
+
+// AuditSeq returns the sequence of the last entry of the audit log, 0 if there is none.
+func (s *Store) AuditSeq() uint64 {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.auditSeq
+}
+
+// readAudit calls fn with each intact entry within the first size bytes of the audit log at path (all of it if size
+// is negative) and the offset of the end of the entry, until fn returns false.
+func readAudit(path string, size int64, fn func(e bookings.AuditEntry, offset int64) bool) error {
+	f, err := os.Open(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	var r io.Reader = f
+	if size >= 0 {
+		r = io.LimitReader(f, size)
+	}
+
+	var unmarshalErr error
+	var offset int64
+	scanFrames(r, func(body []byte) bool {
+		var e bookings.AuditEntry
+		if unmarshalErr = json.Unmarshal(body, &e); unmarshalErr != nil {
+			return false
+		}
+		offset += int64(frameHeaderSize) + int64(len(body))
+		return fn(e, offset)
+	})
+	return unmarshalErr
+}
@@ -0,0 +1,251 @@
+package replycache
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"server/internal/protocol/proto_defs"
+	"server/internal/rpc/response"
+	"server/internal/store"
+	"sync"
+	"time"
+)
+
+const fileName = "responses.log"
+
+// Cache persists the requests and responses of a response.History in a data directory, as a log of records that
+// is rewritten when compacted. Alongside a store.Store, it ensures that retransmitted requests are not executed
+// again after a restart, for as long as their responses would have been kept.
+//
+// Responses are not recorded together with the changes made by their request, which the store.Store syncs to disk
+// first. If the server crashes in between, only the processing record of the request is left: the retransmitted
+// request is answered with an error, and the client cannot tell whether it was executed.
+//
+// Cache implements response.Journal.
+type Cache struct {
+	mu      sync.Mutex
+	dir     string
+	file    *os.File
+	written uint64 // Number of records written to file
+
+	syncMu sync.Mutex // Held while file is synced to disk; must be locked before mu
+	synced uint64     // Number of records written to file that have been synced to disk
+}
+
+// Open opens the Cache in dir, creating dir if it does not exist. Restore must be called before any records are
+// appended.
+func Open(dir string) (*Cache, error) {
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return nil, fmt.Errorf("unable to create data directory: %w", err)
+	}
+	return &Cache{dir: dir}, nil
+}
+
+// Restore loads the logged records onto h. A torn record at the end of the log is discarded.
+func (rc *Cache) Restore(h *response.History) error {
+	rc.mu.Lock()
+	defer rc.mu.Unlock()
+
+	path := filepath.Join(rc.dir, fileName)
+	bodies, end, err := store.ReadFrames(path)
+	if err != nil {
+		return fmt.Errorf("unable to read reply cache: %w", err)
+	}
+
+	recs := make([]response.JournalRecord, 0, len(bodies))
+	for _, body := range bodies {
+		rec, err := unmarshalReplyRecord(body)
+		if err != nil {
+			return fmt.Errorf("unable to unmarshal reply cache record: %w", err)
+		}
+		recs = append(recs, rec)
+	}
+	h.Restore(recs)
+
+	rc.file, err = os.OpenFile(path, os.O_CREATE|os.O_RDWR, 0o644)
+	if err != nil {
+		return fmt.Errorf("unable to open reply cache: %w", err)
+	}
+	// Discard torn record, so that new records are appended after the last intact record
+	if err := rc.file.Truncate(end); err != nil {
+		return fmt.Errorf("unable to truncate reply cache: %w", err)
+	}
+	if _, err := rc.file.Seek(end, 0); err != nil {
+		return fmt.Errorf("unable to seek reply cache: %w", err)
+	}
+
+	slog.Info("Restored reply cache from data directory", "Dir", rc.dir, "Records", len(recs))
+	return nil
+}
+
+// Append writes rec to the log. Processing and response records are synced to disk before Append returns; records
+// appended concurrently are synced together, rather than one after the other.
+func (rc *Cache) Append(rec response.JournalRecord) error {
+	data, err := marshalReplyRecord(rec)
+	if err != nil {
+		return err
+	}
+
+	rc.mu.Lock()
+	if rc.file == nil {
+		rc.mu.Unlock()
+		return errors.New("reply cache is not open")
+	}
+	if _, err := rc.file.Write(store.Frame(data)); err != nil {
+		rc.mu.Unlock()
+		return err
+	}
+	rc.written++
+	n := rc.written
+	rc.mu.Unlock()
+
+	if rec.Type == response.JournalRecordRemoved {
+		return nil
+	}
+	return rc.sync(n)
+}
+
+// sync syncs the log to disk, unless its first n records have been synced already (e.g. along with the records of
+// another Append).
+func (rc *Cache) sync(n uint64) error {
+	rc.syncMu.Lock()
+	defer rc.syncMu.Unlock()
+
+	if rc.synced >= n {
+		return nil
+	}
+
+	// Records written from here on are synced too, and do not need to be synced again
+	rc.mu.Lock()
+	f, written := rc.file, rc.written
+	rc.mu.Unlock()
+	if f == nil {
+		return errors.New("reply cache is not open")
+	}
+	if err := f.Sync(); err != nil {
+		return err
+	}
+	rc.synced = written
+	return nil
+}
+
+// Compact atomically replaces the log with recs; a crash leaves either the previous or the compacted log in place.
+func (rc *Cache) Compact(recs []response.JournalRecord) error {
+	rc.syncMu.Lock()
+	defer rc.syncMu.Unlock()
+	rc.mu.Lock()
+	defer rc.mu.Unlock()
+
+	if rc.file == nil {
+		return errors.New("reply cache is not open")
+	}
+
+	path := filepath.Join(rc.dir, fileName)
+	tmp := path + ".tmp"
+	f, err := os.OpenFile(tmp, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, 0o644)
+	if err != nil {
+		return err
+	}
+	for _, rec := range recs {
+		data, err := marshalReplyRecord(rec)
+		if err == nil {
+			_, err = f.Write(store.Frame(data))
+		}
+		if err != nil {
+			_ = f.Close()
+			return err
+		}
+	}
+	if err := f.Sync(); err != nil {
+		_ = f.Close()
+		return err
+	}
+	if err := f.Close(); err != nil {
+		return err
+	}
+
+	if err := os.Rename(tmp, path); err != nil {
+		return err
+	}
+	if err := store.SyncDir(rc.dir); err != nil {
+		return err
+	}
+
+	// Records written but not yet synced are described by recs, which have been synced instead
+	rc.synced = rc.written
+
+	// Subsequent records are appended to the compacted log
+	_ = rc.file.Close()
+	rc.file, err = os.OpenFile(path, os.O_WRONLY|os.O_APPEND, 0o644)
+	if err != nil {
+		return fmt.Errorf("unable to reopen reply cache: %w", err)
+	}
+
+	slog.Info("Reply cache has been compacted", "Dir", rc.dir, "Records", len(recs))
+	return nil
+}
+
+// Close closes the log; no further records can be appended.
+func (rc *Cache) Close() error {
+	rc.syncMu.Lock()
+	defer rc.syncMu.Unlock()
+	rc.mu.Lock()
+	defer rc.mu.Unlock()
+
+	if rc.file == nil {
+		return nil
+	}
+	err := rc.file.Close()
+	rc.file = nil
+	return err
+}
+
+// Reply cache records have a body of:
+//
+//	[type uint8][updated unix nanoseconds int64][message id][response]
+//
+// where response is only present for response records, in the format of response.Response.MarshalBinary.
+const replyRecordHeaderSize = 1 + 8 + len(proto_defs.MessageId{})
+
+func marshalReplyRecord(rec response.JournalRecord) ([]byte, error) {
+	data := make([]byte, 0, replyRecordHeaderSize)
+	data = append(data, byte(rec.Type))
+	data = binary.BigEndian.AppendUint64(data, uint64(rec.Updated.UnixNano()))
+	data = append(data, rec.MessageId[:]...)
+
+	if rec.Type == response.JournalRecordResponse {
+		res, err := rec.Response.MarshalBinary()
+		if err != nil {
+			return nil, err
+		}
+		data = append(data, res...)
+	}
+	return data, nil
+}
+
+func unmarshalReplyRecord(data []byte) (response.JournalRecord, error) {
+	if len(data) < replyRecordHeaderSize {
+		return response.JournalRecord{}, errors.New("record is too short")
+	}
+
+	rec := response.JournalRecord{
+		Type:      response.JournalRecordType(data[0]),
+		Updated:   time.Unix(0, int64(binary.BigEndian.Uint64(data[1:9]))),
+		MessageId: proto_defs.MessageId(data[9:replyRecordHeaderSize]),
+	}
+
+	switch rec.Type {
+	case response.JournalRecordProcessing, response.JournalRecordRemoved:
+	case response.JournalRecordResponse:
+		rec.Response = &response.Response{}
+		if err := rec.Response.UnmarshalBinary(data[replyRecordHeaderSize:]); err != nil {
+			return rec, err
+		}
+	default:
+		return rec, fmt.Errorf("unknown record type %d", rec.Type)
+	}
+	return rec, nil
+}
@@ -0,0 +1,203 @@
+package replycache
+
+import (
+	"os"
+	"path/filepath"
+	"server/internal/protocol/proto_defs"
+	"server/internal/rpc/response"
+	"server/internal/vars"
+	"sync"
+	"testing"
+	"time"
+)
+
+// openHistory opens the Cache in dir, and restores it onto a new response.History that journals to the Cache.
+func openHistory(t *testing.T, dir string, env *vars.StaticEnvStruct) (*response.History, *Cache) {
+	t.Helper()
+
+	rc, err := Open(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { _ = rc.Close() })
+
+	h := response.NewHistory(env, nil)
+	if err := rc.Restore(h); err != nil {
+		t.Fatal(err)
+	}
+	h.SetJournal(rc)
+
+	return h, rc
+}
+
+func assertStatus(t *testing.T, h *response.History, id proto_defs.MessageId, status response.StatusCode) {
+	t.Helper()
+
+	r, err := h.GetResponse(id)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if r.StatusCode != status {
+		t.Errorf("E: %d, R: %d", status, r.StatusCode)
+	}
+}
+
+func TestCache_Restore(t *testing.T) {
+	dir := t.TempDir()
+	env := vars.GetStaticEnvCopy()
+
+	completed, interrupted, acknowledged := proto_defs.NewMessageId(), proto_defs.NewMessageId(), proto_defs.NewMessageId()
+
+	h, rc := openHistory(t, dir, &env)
+	h.SetProcessing(completed, true)
+	h.AddResponse(response.NewOkResponse(completed))
+	h.SetProcessing(interrupted, true)
+	h.SetProcessing(acknowledged, true)
+	h.AddResponse(response.NewOkResponse(acknowledged))
+	h.RemoveResponse(acknowledged)
+	_ = rc.Close()
+
+	restored, _ := openHistory(t, dir, &env)
+	assertStatus(t, restored, completed, response.StatusOk)
+	assertStatus(t, restored, interrupted, response.StatusInternalServerError)
+	if _, exists := restored.Check(acknowledged); exists {
+		t.Error("Expected acknowledged response not to be restored")
+	}
+}
+
+func TestCache_Restore_notJournaled(t *testing.T) {
+	dir := t.TempDir()
+	env := vars.GetStaticEnvCopy()
+
+	query, interrupted := proto_defs.NewMessageId(), proto_defs.NewMessageId()
+
+	// Requests that make no changes are not journaled, as executing them again is harmless
+	h, rc := openHistory(t, dir, &env)
+	h.SetProcessing(query, false)
+	h.AddResponse(response.NewOkResponse(query))
+	h.SetProcessing(interrupted, false)
+	_ = rc.Close()
+
+	restored, _ := openHistory(t, dir, &env)
+	for _, id := range []proto_defs.MessageId{query, interrupted} {
+		if _, exists := restored.Check(id); exists {
+			t.Errorf("Expected request %v that was not journaled not to be restored", id)
+		}
+	}
+}
+
+func TestCache_Restore_expired(t *testing.T) {
+	dir := t.TempDir()
+	env := vars.GetStaticEnvCopy()
+
+	id := proto_defs.NewMessageId()
+
+	h, rc := openHistory(t, dir, &env)
+	h.SetProcessing(id, true)
+	h.AddResponse(response.NewOkResponse(id))
+	_ = rc.Close()
+
+	env.ResponseTTL = 0
+	time.Sleep(time.Millisecond)
+	restored, _ := openHistory(t, dir, &env)
+	if _, exists := restored.Check(id); exists {
+		t.Error("Expected expired response not to be restored")
+	}
+}
+
+func TestCache_Restore_tornRecord(t *testing.T) {
+	dir := t.TempDir()
+	env := vars.GetStaticEnvCopy()
+
+	first, second := proto_defs.NewMessageId(), proto_defs.NewMessageId()
+
+	h, rc := openHistory(t, dir, &env)
+	h.SetProcessing(first, true)
+	h.AddResponse(response.NewOkResponse(first))
+	_ = rc.Close()
+
+	// Simulate a crash in the middle of appending a record
+	path := filepath.Join(dir, fileName)
+	stat, err := os.Stat(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	h, rc = openHistory(t, dir, &env)
+	h.SetProcessing(second, true)
+	h.AddResponse(response.NewOkResponse(second))
+	_ = rc.Close()
+	if err := os.Truncate(path, stat.Size()+5); err != nil {
+		t.Fatal(err)
+	}
+
+	restored, rc := openHistory(t, dir, &env)
+	assertStatus(t, restored, first, response.StatusOk)
+	if _, exists := restored.Check(second); exists {
+		t.Error("Expected torn record to be discarded")
+	}
+
+	// New records are appended after the last intact record
+	restored.SetProcessing(second, true)
+	restored.AddResponse(response.NewOkResponse(second))
+	_ = rc.Close()
+
+	again, _ := openHistory(t, dir, &env)
+	assertStatus(t, again, second, response.StatusOk)
+}
+
+func TestCache_Compact(t *testing.T) {
+	dir := t.TempDir()
+	env := vars.GetStaticEnvCopy()
+
+	kept, removed := proto_defs.NewMessageId(), proto_defs.NewMessageId()
+
+	h, rc := openHistory(t, dir, &env)
+	h.SetProcessing(removed, true)
+	h.AddResponse(response.NewOkResponse(removed))
+	if err := rc.Compact([]response.JournalRecord{
+		{Type: response.JournalRecordResponse, MessageId: kept, Response: response.NewOkResponse(kept), Updated: time.Now()},
+	}); err != nil {
+		t.Fatal(err)
+	}
+
+	// Records are appended to the compacted log
+	interrupted := proto_defs.NewMessageId()
+	h.SetProcessing(interrupted, true)
+	_ = rc.Close()
+
+	restored, _ := openHistory(t, dir, &env)
+	assertStatus(t, restored, kept, response.StatusOk)
+	assertStatus(t, restored, interrupted, response.StatusInternalServerError)
+	if _, exists := restored.Check(removed); exists {
+		t.Error("Expected record dropped by compaction not to be restored")
+	}
+}
+
+func TestCache_Append_concurrent(t *testing.T) {
+	dir := t.TempDir()
+	env := vars.GetStaticEnvCopy()
+
+	ids := make([]proto_defs.MessageId, 64)
+	for i := range ids {
+		ids[i] = proto_defs.NewMessageId()
+	}
+
+	// Requests journaled at the same time share syncs, but are each persisted before their call returns
+	h, rc := openHistory(t, dir, &env)
+	var wg sync.WaitGroup
+	for _, id := range ids {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			h.SetProcessing(id, true)
+			h.AddResponse(response.NewOkResponse(id))
+		}()
+	}
+	wg.Wait()
+	_ = rc.Close()
+
+	restored, _ := openHistory(t, dir, &env)
+	for _, id := range ids {
+		assertStatus(t, restored, id, response.StatusOk)
+	}
+}
@@ -0,0 +1,130 @@
+package store
+
+import (
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+	"server/internal/bookings"
+	"slices"
+	"strings"
+	"time"
+)
+
+type snapshotFacility struct {
+	Name     bookings.FacilityName `json:"name"`
+	Capacity int                   `json:"capacity,omitempty"`
+	Info     bookings.FacilityInfo `json:"info"`
+	Bookings []bookings.Booking    `json:"bookings"`
+	Series   []bookings.Series     `json:"series,omitempty"`
+	Waitlist []bookings.Booking    `json:"waitlist,omitempty"`
+}
+
+// snapshot is the complete state of a bookings.Manager, after the WAL record with sequence Seq has been applied.
+type snapshot struct {
+	Seq        uint64             `json:"seq"`
+	Created    time.Time          `json:"created"`
+	Facilities []snapshotFacility `json:"facilities"`
+}
+
+func newSnapshot(seq uint64, facilities map[bookings.FacilityName]*bookings.Facility) *snapshot {
+	s := &snapshot{
+		Seq:        seq,
+		Created:    time.Now(),
+		Facilities: make([]snapshotFacility, 0, len(facilities)),
+	}
+
+	for name, f := range facilities {
+		sf := snapshotFacility{Name: name, Capacity: f.Capacity, Info: f.Info, Bookings: make([]bookings.Booking, 0, len(f.Bookings))}
+		for _, b := range f.Bookings {
+			sf.Bookings = append(sf.Bookings, *b)
+		}
+		for _, series := range f.Series {
+			sf.Series = append(sf.Series, *series)
+		}
+		slices.SortFunc(sf.Series, func(a, b bookings.Series) int { return int(a.Id) - int(b.Id) })
+		for _, w := range f.Waitlist {
+			sf.Waitlist = append(sf.Waitlist, *w)
+		}
+		s.Facilities = append(s.Facilities, sf)
+	}
+	slices.SortFunc(s.Facilities, func(a, b snapshotFacility) int { return strings.Compare(string(a.Name), string(b.Name)) })
+
+	return s
+}
+
+// mutations returns the mutations that restore the snapshot onto an empty bookings.Manager.
+func (s *snapshot) mutations() []bookings.Mutation {
+	var res []bookings.Mutation
+	for _, f := range s.Facilities {
+		res = append(res, bookings.Mutation{Type: bookings.MutationFacilityCreate, Facility: f.Name, Capacity: f.Capacity, Info: &f.Info})
+		for _, s := range f.Series {
+			res = append(res, bookings.Mutation{Type: bookings.MutationSeriesMake, Facility: f.Name, Series: &s})
+		}
+		for _, b := range f.Bookings {
+			res = append(res, bookings.Mutation{Type: bookings.MutationBookingMake, Facility: f.Name, Booking: &b, BookingId: b.Id})
+		}
+		for _, w := range f.Waitlist {
+			res = append(res, bookings.Mutation{Type: bookings.MutationWaitlistJoin, Facility: f.Name, Booking: &w, BookingId: w.Id})
+		}
+	}
+	return res
+}
+
+// readSnapshot reads the snapshot at path, returns nil if there is no snapshot.
+func readSnapshot(path string) (*snapshot, error) {
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	var s snapshot
+	if err := json.Unmarshal(data, &s); err != nil {
+		return nil, err
+	}
+	return &s, nil
+}
+
+// writeSnapshot atomically replaces the snapshot at path with s; a crash leaves either the previous or the new
+// snapshot in place.
+func writeSnapshot(path string, s *snapshot) error {
+	data, err := json.MarshalIndent(s, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	tmp := path + ".tmp"
+	f, err := os.OpenFile(tmp, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, 0o644)
+	if err != nil {
+		return err
+	}
+	if _, err := f.Write(data); err != nil {
+		_ = f.Close()
+		return err
+	}
+	if err := f.Sync(); err != nil {
+		_ = f.Close()
+		return err
+	}
+	if err := f.Close(); err != nil {
+		return err
+	}
+
+	if err := os.Rename(tmp, path); err != nil {
+		return err
+	}
+	return SyncDir(filepath.Dir(path))
+}
+
+// SyncDir flushes the directory entries of dir, so that renames and newly created files survive a crash.
+func SyncDir(dir string) error {
+	d, err := os.Open(dir)
+	if err != nil {
+		return err
+	}
+	defer d.Close()
+	return d.Sync()
+}
@@ -0,0 +1,222 @@
+package store
+
+import (
+	"errors"
+	"fmt"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"server/internal/bookings"
+	"sync"
+	"time"
+)
+
+const (
+	walFileName      = "bookings.wal"
+	snapshotFileName = "bookings.snapshot.json"
+)
+
+// Store persists the facilities and bookings of a bookings.Manager in a data directory, as a write-ahead log (WAL) of
+// mutations and periodic snapshots. Every appended mutation is synced to disk before Append returns; taking a snapshot
+// truncates the WAL. The audit log of the bookings.Manager is kept in a separate append-only log.
+//
+// Store implements bookings.Journal and bookings.AuditJournal.
+type Store struct {
+	mu  sync.Mutex
+	dir string
+	wal logFile
+
+	seq          uint64    // Sequence of the last appended mutation
+	walRecords   int       // Number of mutations in the WAL, i.e. since the last snapshot
+	walBytes     int64     // Size of the WAL, up to the end of the last mutation
+	walFailed    error     // Set if the WAL may end in a torn record, as an append failed and could not be undone
+	snapshotSeq  uint64    // Sequence of the last mutation included in the snapshot
+	snapshotTime time.Time // Zero if there is no snapshot
+
+	audit       logFile
+	auditSeq    uint64 // Sequence of the last entry of the audit log
+	auditBytes  int64  // Size of the audit log, up to the end of the last entry
+	auditFailed error  // Set if the audit log may end in a torn entry, as an append failed and could not be undone
+}
+
+// Info describes the persisted state of a Store.
+type Info struct {
+	Dir          string
+	Seq          uint64
+	WalRecords   int
+	WalBytes     int64
+	SnapshotSeq  uint64
+	SnapshotTime time.Time
+}
+
+// Open opens the Store in dir, creating dir if it does not exist. Restore must be called before any mutations are
+// appended.
+func Open(dir string) (*Store, error) {
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return nil, fmt.Errorf("unable to create data directory: %w", err)
+	}
+	return &Store{dir: dir}, nil
+}
+
+// Restore loads the latest snapshot and replays the WAL onto m, which must be empty. A torn record at the end of the
+// WAL (i.e. from a crash during an append) is discarded.
+func (s *Store) Restore(m *bookings.Manager) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	snap, err := readSnapshot(filepath.Join(s.dir, snapshotFileName))
+	if err != nil {
+		return fmt.Errorf("unable to read snapshot: %w", err)
+	}
+	if snap != nil {
+		for _, mut := range snap.mutations() {
+			if err := m.Apply(mut); err != nil {
+				return fmt.Errorf("unable to restore snapshot: %w", err)
+			}
+		}
+		s.seq, s.snapshotSeq, s.snapshotTime = snap.Seq, snap.Seq, snap.Created
+	}
+
+	walPath := filepath.Join(s.dir, walFileName)
+	records, end, err := readWal(walPath)
+	if err != nil {
+		return fmt.Errorf("unable to read WAL: %w", err)
+	}
+	for _, r := range records {
+		// Records may already be included in the snapshot, if the server crashed before the WAL was truncated
+		if r.Seq <= s.snapshotSeq {
+			continue
+		}
+		if err := m.Apply(r.Mutation); err != nil {
+			slog.Warn("Skipping WAL record that cannot be applied", "Seq", r.Seq, "err", err)
+		}
+		s.seq = r.Seq
+		s.walRecords++
+	}
+
+	wal, err := os.OpenFile(walPath, os.O_CREATE|os.O_RDWR, 0o644)
+	if err != nil {
+		return fmt.Errorf("unable to open WAL: %w", err)
+	}
+	s.wal = wal
+	// Discard torn record, so that new records are appended after the last intact record
+	if err := s.wal.Truncate(end); err != nil {
+		return fmt.Errorf("unable to truncate WAL: %w", err)
+	}
+	if _, err := s.wal.Seek(end, 0); err != nil {
+		return fmt.Errorf("unable to seek WAL: %w", err)
+	}
+	s.walBytes = end
+	if err := s.restoreAudit(); err != nil {
+		return err
+	}
+
+	slog.Info(
+		"Restored bookings from data directory",
+		"Dir", s.dir, "SnapshotSeq", s.snapshotSeq, "WalRecords", s.walRecords, "Seq", s.seq,
+	)
+	return nil
+}
+
+// Append writes mut to the WAL, and syncs it to disk. If mut cannot be written in full, it is removed from the WAL
+// again; if that fails too, no further mutations are appended until the WAL is truncated by a snapshot, as they would
+// follow a torn record and be lost on restore.
+func (s *Store) Append(mut bookings.Mutation) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.wal == nil {
+		return fmt.Errorf("store is not open")
+	}
+	if s.walFailed != nil {
+		return fmt.Errorf("WAL has failed: %w", s.walFailed)
+	}
+
+	record := walRecord{Seq: s.seq + 1, Mutation: mut}
+	data, err := record.MarshalBinary()
+	if err != nil {
+		return err
+	}
+	if failed, err := appendSynced(s.wal, s.walBytes, data); err != nil {
+		if failed {
+			slog.Error("WAL may end in a torn record, refusing further mutations", "Dir", s.dir, "err", err)
+			s.walFailed = err
+		}
+		return err
+	}
+
+	s.seq = record.Seq
+	s.walRecords++
+	s.walBytes += int64(len(data))
+	return nil
+}
+
+// Snapshot writes facilities as the new snapshot, and truncates the WAL. facilities must include every mutation that
+// has been appended.
+func (s *Store) Snapshot(facilities map[bookings.FacilityName]*bookings.Facility) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.wal == nil {
+		return fmt.Errorf("store is not open")
+	}
+
+	snap := newSnapshot(s.seq, facilities)
+	if err := writeSnapshot(filepath.Join(s.dir, snapshotFileName), snap); err != nil {
+		return fmt.Errorf("unable to write snapshot: %w", err)
+	}
+	s.snapshotSeq, s.snapshotTime = snap.Seq, snap.Created
+
+	// Mutations in the WAL are now part of the snapshot; a crash before truncating is handled by Restore
+	if err := s.wal.Truncate(0); err != nil {
+		return fmt.Errorf("unable to truncate WAL: %w", err)
+	}
+	if _, err := s.wal.Seek(0, 0); err != nil {
+		return fmt.Errorf("unable to seek WAL: %w", err)
+	}
+	if err := s.wal.Sync(); err != nil {
+		return err
+	}
+	s.walRecords, s.walBytes = 0, 0
+	s.walFailed = nil // A torn record has been truncated along with the rest of the WAL
+
+	slog.Info("Snapshot has been taken", "Dir", s.dir, "Seq", snap.Seq, "Facilities", len(snap.Facilities))
+	return nil
+}
+
+// Info returns the current persisted state of s.
+func (s *Store) Info() Info {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	info := Info{
+		Dir:          s.dir,
+		Seq:          s.seq,
+		WalRecords:   s.walRecords,
+		SnapshotSeq:  s.snapshotSeq,
+		SnapshotTime: s.snapshotTime,
+	}
+	if s.wal != nil {
+		if stat, err := s.wal.Stat(); err == nil {
+			info.WalBytes = stat.Size()
+		}
+	}
+	return info
+}
+
+// Close closes the WAL and the audit log; no further mutations or audit entries can be appended.
+func (s *Store) Close() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	var err error
+	if s.audit != nil {
+		err = s.audit.Close()
+		s.audit = nil
+	}
+	if s.wal != nil {
+		err = errors.Join(s.wal.Close(), err)
+		s.wal = nil
+	}
+	return err
+}
@@ -0,0 +1,532 @@
+package store
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"server/internal/bookings"
+	"testing"
+	"time"
+)
+
+// openManager opens the Store in dir, and restores it onto a new bookings.Manager that journals its changes and audit
+// log to the Store.
+func openManager(t *testing.T, dir string) (*bookings.Manager, *Store) {
+	t.Helper()
+
+	s, err := Open(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { _ = s.Close() })
+
+	m := bookings.NewManager()
+	if err := s.Restore(m); err != nil {
+		t.Fatal(err)
+	}
+	m.SetJournal(s)
+	m.SetAuditJournal(s)
+
+	return m, s
+}
+
+func newTestBooking(t *testing.T, id uint64, startHours int) bookings.Booking {
+	t.Helper()
+
+	start := time.Now().Truncate(time.Hour).Add(time.Duration(startHours) * time.Hour)
+	b, err := bookings.NewBooking(
+		func(b *bookings.Booking) { b.Id = id },
+		bookings.BookingWithStartTime(start),
+		bookings.BookingWithEndTime(start.Add(time.Hour)),
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return b
+}
+
+// populate makes one of each kind of mutation.
+func populate(t *testing.T, m *bookings.Manager) {
+	t.Helper()
+
+	for _, err := range []error{
+		m.NewFacility("A"),
+		m.NewFacility("B"),
+		m.NewFacility("C"),
+		m.DeleteFacility("C"),
+		m.NewBooking("A", newTestBooking(t, 1, 24)),
+		m.NewBooking("A", newTestBooking(t, 2, 48)),
+		m.NewBooking("B", newTestBooking(t, 3, 24)),
+		m.UpdateBookingFromId(1, 2),
+		m.DeleteBookingFromId(2),
+	} {
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+}
+
+func assertPopulated(t *testing.T, m *bookings.Manager) {
+	t.Helper()
+
+	records := m.GetDeepCopyOfRecords()
+	if len(records) != 2 || records["A"] == nil || records["B"] == nil {
+		t.Fatalf("Expected facilities A and B, got %v", records)
+	}
+	if len(records["A"].Bookings) != 1 || len(records["B"].Bookings) != 1 {
+		t.Fatalf("Expected 1 booking per facility, got %d and %d", len(records["A"].Bookings), len(records["B"].Bookings))
+	}
+
+	expected := newTestBooking(t, 1, 26)
+	if b := records["A"].Bookings[0]; b.Id != 1 || !b.Start.Equal(expected.Start) || !b.End.Equal(expected.End) {
+		t.Errorf("E: %v, R: %v", expected, *b)
+	}
+
+	// Booking Ids are indexed across facilities
+	if err := m.NewBooking("A", newTestBooking(t, 3, 72)); err == nil {
+		t.Error("Expected booking with an Id in use by another facility to be rejected")
+	}
+}
+
+func TestStore_Restore_wal(t *testing.T) {
+	dir := t.TempDir()
+
+	m, s := openManager(t, dir)
+	populate(t, m)
+	_ = s.Close()
+
+	restored, rs := openManager(t, dir)
+	assertPopulated(t, restored)
+
+	if info := rs.Info(); info.Seq != 9 || info.WalRecords != 9 {
+		t.Errorf("Expected 9 WAL records, got %+v", info)
+	}
+}
+
+func TestStore_Restore_snapshot(t *testing.T) {
+	dir := t.TempDir()
+
+	m, s := openManager(t, dir)
+	populate(t, m)
+	if err := m.Snapshot(); err != nil {
+		t.Fatal(err)
+	}
+	if info := s.Info(); info.WalRecords != 0 || info.WalBytes != 0 || info.SnapshotSeq != 9 {
+		t.Errorf("Expected WAL to be truncated by snapshot, got %+v", info)
+	}
+
+	// Mutations after the snapshot are only in the WAL
+	if err := m.NewFacility("D"); err != nil {
+		t.Fatal(err)
+	}
+	if err := m.DeleteFacility("D"); err != nil {
+		t.Fatal(err)
+	}
+	_ = s.Close()
+
+	restored, rs := openManager(t, dir)
+	assertPopulated(t, restored)
+
+	if info := rs.Info(); info.Seq != 11 || info.SnapshotSeq != 9 || info.WalRecords != 2 {
+		t.Errorf("Unexpected info after restore: %+v", info)
+	}
+}
+
+func TestStore_Restore_snapshotBeforeTruncate(t *testing.T) {
+	dir := t.TempDir()
+
+	m, s := openManager(t, dir)
+	populate(t, m)
+
+	// Simulate a crash after the snapshot was written, but before the WAL was truncated
+	wal, err := os.ReadFile(filepath.Join(dir, walFileName))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := m.Snapshot(); err != nil {
+		t.Fatal(err)
+	}
+	_ = s.Close()
+	if err := os.WriteFile(filepath.Join(dir, walFileName), wal, 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	restored, rs := openManager(t, dir)
+	assertPopulated(t, restored)
+
+	if info := rs.Info(); info.Seq != 9 || info.WalRecords != 0 {
+		t.Errorf("Expected WAL records included in snapshot to be skipped, got %+v", info)
+	}
+}
+
+func TestStore_Restore_tornRecord(t *testing.T) {
+	dir := t.TempDir()
+
+	m, s := openManager(t, dir)
+	populate(t, m)
+	_ = s.Close()
+
+	// Simulate a crash in the middle of appending a record
+	walPath := filepath.Join(dir, walFileName)
+	f, err := os.OpenFile(walPath, os.O_APPEND|os.O_WRONLY, 0o644)
+	if err != nil {
+		t.Fatal(err)
+	}
+	record := walRecord{Seq: 10, Mutation: bookings.Mutation{Type: bookings.MutationFacilityCreate, Facility: "Torn"}}
+	data, _ := record.MarshalBinary()
+	_, _ = f.Write(data[:len(data)-3])
+	_ = f.Close()
+
+	restored, rs := openManager(t, dir)
+	assertPopulated(t, restored)
+
+	// New records are appended after the last intact record
+	if err := restored.NewFacility("E"); err != nil {
+		t.Fatal(err)
+	}
+	_ = rs.Close()
+
+	again, _ := openManager(t, dir)
+	if _, exists := again.GetDeepCopyOfRecords()["E"]; !exists {
+		t.Error("Expected facility appended after torn record to be restored")
+	}
+}
+
+func TestStore_Restore_audit(t *testing.T) {
+	dir := t.TempDir()
+
+	m, s := openManager(t, dir)
+	populate(t, m)
+
+	// Snapshots truncate the WAL, but not the audit log
+	if err := m.Snapshot(); err != nil {
+		t.Fatal(err)
+	}
+	expected, err := m.QueryAudit(bookings.AuditFilter{}, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(expected) != 9 {
+		t.Fatalf("Expected an audit entry per change, got %v", expected)
+	}
+	_ = s.Close()
+
+	// Simulate a crash in the middle of appending an entry
+	f, err := os.OpenFile(filepath.Join(dir, auditFileName), os.O_APPEND|os.O_WRONLY, 0o644)
+	if err != nil {
+		t.Fatal(err)
+	}
+	data := Frame([]byte(`{"seq":10,"type":1,"facility":"Torn"}`))
+	_, _ = f.Write(data[:len(data)-3])
+	_ = f.Close()
+
+	restored, _ := openManager(t, dir)
+	entries, err := restored.QueryAudit(bookings.AuditFilter{}, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(entries) != len(expected) || entries[0].Seq != expected[0].Seq || entries[len(entries)-1].Seq != expected[len(expected)-1].Seq {
+		t.Fatalf("E: %v, R: %v", expected, entries)
+	}
+
+	// New entries continue the sequence after the last intact entry
+	if err := restored.NewFacility("E"); err != nil {
+		t.Fatal(err)
+	}
+	last := expected[len(expected)-1].Seq
+	entries, err = restored.QueryAudit(bookings.AuditFilter{AfterSeq: last}, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(entries) != 1 || entries[0].Seq != last+1 || entries[0].Facility != "E" {
+		t.Errorf("Expected entry %d for facility E, got %v", last+1, entries)
+	}
+}
+
+func TestStore_Reset(t *testing.T) {
+	dir := t.TempDir()
+
+	m, s := openManager(t, dir)
+	populate(t, m)
+	m.Reset()
+	_ = s.Close()
+
+	restored, _ := openManager(t, dir)
+	if records := restored.GetDeepCopyOfRecords(); len(records) != 0 {
+		t.Errorf("Expected no facilities after reset, got %v", records)
+	}
+}
+
+func TestStore_Append_failureReverts(t *testing.T) {
+	m, s := openManager(t, t.TempDir())
+	_ = s.Close() // Appending to a closed Store fails
+
+	if err := m.NewFacility("A"); err == nil {
+		t.Fatal("Expected error when mutation cannot be persisted")
+	}
+	if records := m.GetDeepCopyOfRecords(); len(records) != 0 {
+		t.Errorf("Expected facility creation to be reverted, got %v", records)
+	}
+}
+
+// faultyFile fails to write the second half of the next frame, and to truncate the log if failTruncate is set.
+type faultyFile struct {
+	logFile
+	failWrite    bool
+	failTruncate bool
+}
+
+func (f *faultyFile) Write(p []byte) (int, error) {
+	if f.failWrite {
+		f.failWrite = false
+		n, _ := f.logFile.Write(p[:len(p)/2])
+		return n, errors.New("short write")
+	}
+	return f.logFile.Write(p)
+}
+
+func (f *faultyFile) Truncate(size int64) error {
+	if f.failTruncate {
+		return errors.New("truncate failed")
+	}
+	return f.logFile.Truncate(size)
+}
+
+func TestStore_Append_shortWrite(t *testing.T) {
+	dir := t.TempDir()
+
+	m, s := openManager(t, dir)
+	populate(t, m)
+
+	s.wal = &faultyFile{logFile: s.wal, failWrite: true}
+	if err := m.NewFacility("Torn"); err == nil {
+		t.Fatal("Expected error when mutation is only partially written")
+	}
+
+	// Records appended after the partially written record are restored
+	if err := m.NewFacility("E"); err != nil {
+		t.Fatal(err)
+	}
+
+	// Failing to write an audit entry does not fail the change it describes
+	s.audit = &faultyFile{logFile: s.audit, failWrite: true}
+	if err := m.NewFacility("F"); err != nil {
+		t.Fatal(err)
+	}
+	if err := m.NewFacility("G"); err != nil {
+		t.Fatal(err)
+	}
+	_ = s.Close()
+
+	restored, _ := openManager(t, dir)
+	records := restored.GetDeepCopyOfRecords()
+	if _, exists := records["Torn"]; exists {
+		t.Error("Expected partially written facility not to be restored")
+	}
+	if _, exists := records["E"]; !exists {
+		t.Error("Expected facility appended after partially written record to be restored")
+	}
+
+	entries, err := restored.QueryAudit(bookings.AuditFilter{Facility: "G"}, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(entries) != 1 {
+		t.Errorf("Expected audit entry appended after partially written entry to be restored, got %v", entries)
+	}
+}
+
+func TestStore_Append_failedTruncate(t *testing.T) {
+	m, s := openManager(t, t.TempDir())
+
+	s.wal = &faultyFile{logFile: s.wal, failWrite: true, failTruncate: true}
+	if err := m.NewFacility("Torn"); err == nil {
+		t.Fatal("Expected error when mutation is only partially written")
+	}
+
+	// Mutations would follow a torn record, and are refused until a snapshot truncates the WAL
+	if err := m.NewFacility("A"); err == nil {
+		t.Fatal("Expected error when WAL may end in a torn record")
+	}
+
+	s.wal.(*faultyFile).failTruncate = false
+	if err := m.Snapshot(); err != nil {
+		t.Fatal(err)
+	}
+	if err := m.NewFacility("A"); err != nil {
+		t.Errorf("Expected mutations to be appended after snapshot, got %v", err)
+	}
+}
+
+func TestStore_Restore_series(t *testing.T) {
+	dir := t.TempDir()
+
+	m, s := openManager(t, dir)
+	if err := m.NewFacility("A"); err != nil {
+		t.Fatal(err)
+	}
+	start := time.Now().Truncate(time.Hour).Add(time.Duration(24) * time.Hour)
+	series, err := bookings.NewSeries(
+		bookings.SeriesWithRandomId(),
+		bookings.SeriesWithFrequency(bookings.FrequencyDaily),
+		bookings.SeriesWithFirstOccurrence(start, start.Add(time.Hour)),
+		bookings.SeriesWithCount(3),
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+	ids, err := m.NewSeries("A", series)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := m.DeleteBookingFromId(ids[1]); err != nil {
+		t.Fatal(err)
+	}
+	if err := m.ShiftSeriesFromId(series.Id, time.Hour); err != nil {
+		t.Fatal(err)
+	}
+
+	assertSeries := func(m *bookings.Manager) {
+		t.Helper()
+		f := m.GetDeepCopyOfRecords()["A"]
+		if len(f.Series) != 1 || f.Series[series.Id] == nil || len(f.Bookings) != 2 {
+			t.Fatalf("Expected series with 2 occurrences, got %v and %v", f.Series, f.Bookings)
+		}
+		if b := f.Bookings[1]; b.Id != ids[2] || b.SeriesId != series.Id || !b.Start.Equal(start.AddDate(0, 0, 2).Add(time.Hour)) {
+			t.Errorf("Unexpected occurrence: %v", *b)
+		}
+	}
+
+	_ = s.Close()
+	restored, rs := openManager(t, dir)
+	assertSeries(restored)
+
+	if err := restored.Snapshot(); err != nil {
+		t.Fatal(err)
+	}
+	_ = rs.Close()
+	again, _ := openManager(t, dir)
+	assertSeries(again)
+}
+
+func TestStore_Restore_capacity(t *testing.T) {
+	dir := t.TempDir()
+
+	m, s := openManager(t, dir)
+	if err := m.NewFacility("A", bookings.FacilityWithCapacity(3)); err != nil {
+		t.Fatal(err)
+	}
+	if err := m.NewFacility("B"); err != nil {
+		t.Fatal(err)
+	}
+	info := bookings.FacilityInfo{Location: "Level 2", Timezone: "Asia/Singapore"}
+	if err := m.UpdateFacility("B", 2, info); err != nil {
+		t.Fatal(err)
+	}
+
+	assertCapacity := func(m *bookings.Manager) {
+		t.Helper()
+		records := m.GetDeepCopyOfRecords()
+		if records["A"].Capacity != 3 || records["B"].Capacity != 2 {
+			t.Errorf("E: capacities 3 and 2, R: %d and %d", records["A"].Capacity, records["B"].Capacity)
+		}
+		if records["B"].Info.Location != info.Location || records["B"].Info.Timezone != info.Timezone {
+			t.Errorf("E: %v, R: %v", info, records["B"].Info)
+		}
+	}
+
+	_ = s.Close()
+	restored, rs := openManager(t, dir)
+	assertCapacity(restored)
+
+	if err := restored.Snapshot(); err != nil {
+		t.Fatal(err)
+	}
+	_ = rs.Close()
+	again, _ := openManager(t, dir)
+	assertCapacity(again)
+}
+
+func TestStore_Restore_waitlist(t *testing.T) {
+	dir := t.TempDir()
+
+	m, s := openManager(t, dir)
+	if err := m.NewFacility("A"); err != nil {
+		t.Fatal(err)
+	}
+	if err := m.NewBooking("A", newTestBooking(t, 1, 24)); err != nil {
+		t.Fatal(err)
+	}
+
+	// The first waitlisted booking is made once the clashing booking is deleted, the second one keeps waiting
+	// (the Ids of waitlisted bookings are assigned by the Manager)
+	first, booked, err := m.JoinWaitlist("A", newTestBooking(t, 2, 24))
+	if err != nil || booked {
+		t.Fatalf("Expected booking to be waitlisted, got %v (%v)", booked, err)
+	}
+	second, _, err := m.JoinWaitlist("A", newTestBooking(t, 2, 24))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := m.DeleteBookingFromId(1); err != nil {
+		t.Fatal(err)
+	}
+
+	assertWaitlist := func(m *bookings.Manager) {
+		t.Helper()
+		f := m.GetDeepCopyOfRecords()["A"]
+		if len(f.Bookings) != 1 || f.Bookings[0].Id != first {
+			t.Fatalf("Expected waitlisted booking %d to be booked, got %v", first, f.Bookings)
+		}
+		if len(f.Waitlist) != 1 || f.Waitlist[0].Id != second {
+			t.Fatalf("Expected booking %d to be waitlisted, got %v", second, f.Waitlist)
+		}
+	}
+	assertWaitlist(m)
+
+	_ = s.Close()
+	restored, rs := openManager(t, dir)
+	assertWaitlist(restored)
+
+	if err := restored.Snapshot(); err != nil {
+		t.Fatal(err)
+	}
+	_ = rs.Close()
+	again, _ := openManager(t, dir)
+	assertWaitlist(again)
+
+	// Restored waitlisted bookings are found by their Id
+	if err := again.LeaveWaitlistAs(bookings.SystemCaller, second); err != nil {
+		t.Error(err)
+	}
+}
+
+func TestStore_Restore_batch(t *testing.T) {
+	dir := t.TempDir()
+
+	m, s := openManager(t, dir)
+	for _, n := range []bookings.FacilityName{"A", "B"} {
+		if err := m.NewFacility(n); err != nil {
+			t.Fatal(err)
+		}
+	}
+	ids, err := m.NewBookings([]bookings.BookingItem{
+		{Facility: "A", Booking: newTestBooking(t, 1, 24)},
+		{Facility: "B", Booking: newTestBooking(t, 1, 24)},
+	}, bookings.MaxLegacyBookingId)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if info := s.Info(); info.WalRecords != 3 {
+		t.Errorf("Expected bookings made together to be a single WAL record, got %+v", info)
+	}
+
+	_ = s.Close()
+	restored, _ := openManager(t, dir)
+	records := restored.GetDeepCopyOfRecords()
+	for i, n := range []bookings.FacilityName{"A", "B"} {
+		if _, exists := records[n].BookingMap[ids[i]]; !exists {
+			t.Errorf("Expected booking %d in facility %s, got %v", ids[i], n, records[n].Bookings)
+		}
+	}
+}
@@ -0,0 +1,147 @@
+package store
+
+import (
+	"bufio"
+	"encoding/binary"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"hash/crc32"
+	"io"
+	"os"
+	"server/internal/bookings"
+)
+
+// Records are framed as:
+//
+//	[length uint32][checksum uint32][body]
+//
+// where checksum is the CRC32 of the body. A frame that is incomplete or fails its checksum marks the end of the log;
+// it can only be the result of a crash while appending.
+const frameHeaderSize = 8
+
+// Frame returns body framed as a record, to be appended to a log.
+func Frame(body []byte) []byte {
+	data := make([]byte, frameHeaderSize+len(body))
+	binary.BigEndian.PutUint32(data[0:4], uint32(len(body)))
+	binary.BigEndian.PutUint32(data[4:8], crc32.ChecksumIEEE(body))
+	copy(data[frameHeaderSize:], body)
+	return data
+}
+
+// logFile is a log that frames are appended to, implemented by *os.File.
+type logFile interface {
+	io.WriteSeeker
+	Sync() error
+	Truncate(size int64) error
+	Stat() (os.FileInfo, error)
+	Close() error
+}
+
+// appendSynced writes data at end, the end of the last intact frame of f, and syncs it to disk. If either fails, f is
+// truncated back to end, so that a partially written frame does not hide the frames appended after it. Returns true
+// if f could not be truncated either, and may now end in a partially written frame.
+func appendSynced(f logFile, end int64, data []byte) (bool, error) {
+	_, err := f.Write(data)
+	if err == nil {
+		err = f.Sync()
+	}
+	if err == nil {
+		return false, nil
+	}
+
+	if terr := f.Truncate(end); terr != nil {
+		return true, errors.Join(err, terr)
+	}
+	if _, serr := f.Seek(end, io.SeekStart); serr != nil {
+		return true, errors.Join(err, serr)
+	}
+	return false, err
+}
+
+// ReadFrames reads the bodies of all intact frames from the log at path, and returns them along with the offset of the
+// end of the last intact frame.
+func ReadFrames(path string) ([][]byte, int64, error) {
+	f, err := os.Open(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, 0, nil
+	}
+	if err != nil {
+		return nil, 0, err
+	}
+	defer f.Close()
+
+	var bodies [][]byte
+	offset := scanFrames(f, func(body []byte) bool {
+		bodies = append(bodies, body)
+		return true
+	})
+	return bodies, offset, nil
+}
+
+// scanFrames calls fn with the body of each intact frame read from r, until fn returns false, and returns the offset
+// of the end of the last frame passed to fn.
+func scanFrames(r io.Reader, fn func(body []byte) bool) int64 {
+	var offset int64
+	br := bufio.NewReader(r)
+	header := make([]byte, frameHeaderSize)
+
+	for {
+		if _, err := io.ReadFull(br, header); err != nil {
+			return offset // End of log, or torn header
+		}
+
+		length := binary.BigEndian.Uint32(header[0:4])
+		checksum := binary.BigEndian.Uint32(header[4:8])
+
+		body := make([]byte, length)
+		if _, err := io.ReadFull(br, body); err != nil {
+			return offset // Torn body
+		}
+		if crc32.ChecksumIEEE(body) != checksum {
+			return offset
+		}
+
+		if !fn(body) {
+			return offset
+		}
+		offset += int64(frameHeaderSize) + int64(length)
+	}
+}
+
+// walRecord is a mutation in the WAL, with a body of [sequence uint64][mutation JSON].
+type walRecord struct {
+	Seq      uint64
+	Mutation bookings.Mutation
+}
+
+func (r *walRecord) MarshalBinary() ([]byte, error) {
+	body, err := json.Marshal(r.Mutation)
+	if err != nil {
+		return nil, err
+	}
+	return Frame(append(binary.BigEndian.AppendUint64(nil, r.Seq), body...)), nil
+}
+
+// readWal reads all intact records from the WAL at path, and returns them along with the offset of the end of the last
+// intact record.
+func readWal(path string) ([]walRecord, int64, error) {
+	bodies, end, err := ReadFrames(path)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	records := make([]walRecord, 0, len(bodies))
+	for _, body := range bodies {
+		if len(body) < 8 {
+			return nil, 0, errors.New("WAL record is too short")
+		}
+		record := walRecord{Seq: binary.BigEndian.Uint64(body[0:8])}
+		if err := json.Unmarshal(body[8:], &record.Mutation); err != nil {
+			return nil, 0, fmt.Errorf("unable to unmarshal WAL record %d: %w", record.Seq, err)
+		}
+		records = append(records, record)
+	}
+
+	return records, end, nil
+}
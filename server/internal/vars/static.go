@@ -4,12 +4,16 @@ import (
 	"fmt"
 	"github.com/caarlos0/env/v11"
 	"log/slog"
+	"maps"
 	"sync"
 )
 
 type StaticEnvStruct struct {
 	ServerPort    int `env:"SERVER_PORT" envDefault:"8765"`     // Port exposed for the actual booking application
 	ServerLogPort int `env:"SERVER_LOG_PORT" envDefault:"7777"` // Port exposed for logs to be viewed remotely
+	ServerSockets int `env:"SERVER_SOCKETS" envDefault:"1"`     // Number of UDP sockets bound to the port (SO_REUSEPORT, linux only)
+
+	ServerBindAddresses []string `env:"SERVER_BIND_ADDRESSES" envDefault:"0.0.0.0"` // Addresses the server listens on, "::" for dual-stack
 
 	EnableDuplicateFiltering  bool    `env:"ENABLE_DUPLICATE_FILTERING" envDefault:"true"`
 	PacketDropRate            float32 `env:"PACKET_DROP_RATE" envDefault:"0.20"`         // Rate of which packets are dropped (in and out)
@@ -18,6 +22,26 @@ type StaticEnvStruct struct {
 	MessageAssemblerIntervals int     `env:"MESSAGE_ASSEMBLER_INTERVAL" envDefault:"50"` // Time between runs to request missing packets
 	ResponseTTL               int     `env:"RESPONSE_TTL" envDefault:"5000000"`          // Maximum time to keep messages in history
 	ResponseIntervals         int     `env:"RESPONSE_INTERVAL" envDefault:"500"`         // Time between runs to check for expired responses
+	ShutdownGracePeriod       int     `env:"SHUTDOWN_GRACE_PERIOD" envDefault:"5000"`    // Maximum time to drain requests and flush responses on shutdown
+
+	WorkerPoolSize        int  `env:"WORKER_POOL_SIZE" envDefault:"64"`         // Number of workers handling incoming packets
+	WorkerQueueSize       int  `env:"WORKER_QUEUE_SIZE" envDefault:"1024"`      // Maximum number of incoming packets waiting for a worker
+	WorkerFairQueuing     bool `env:"WORKER_FAIR_QUEUING" envDefault:"false"`   // Queue incoming packets per client, and serve clients round-robin
+	WorkerClientQueueSize int  `env:"WORKER_CLIENT_QUEUE_SIZE" envDefault:"64"` // Maximum number of incoming packets waiting per client (with fair queuing)
+
+	RateLimitRate         float64            `env:"RATE_LIMIT_RATE" envDefault:"50"`                 // Requests per second allowed per client, 0 disables the limit
+	RateLimitBurst        int                `env:"RATE_LIMIT_BURST" envDefault:"100"`               // Requests a client may send at once, before being limited to the rate
+	RateLimitMethodRates  map[string]float64 `env:"RATE_LIMIT_METHOD_RATES" envKeyValSeparator:"="`  // Requests per second allowed per client for a method, e.g. "BookingMake=5"
+	RateLimitMethodBursts map[string]int     `env:"RATE_LIMIT_METHOD_BURSTS" envKeyValSeparator:"="` // Requests a client may send at once for a method, defaults to RateLimitBurst
+
+	DataDir          string `env:"DATA_DIR" envDefault:""`               // Directory to persist facilities and bookings in, empty to keep them in memory only
+	SnapshotInterval int    `env:"SNAPSHOT_INTERVAL" envDefault:"60000"` // Time between snapshots of facilities and bookings in milliseconds, 0 disables
+	PersistResponses bool   `env:"PERSIST_RESPONSES" envDefault:"false"` // Persist responses in DataDir, so that duplicate requests are still filtered after a restart
+
+	BookingSlotMinutes int    `env:"BOOKING_SLOT_MINUTES" envDefault:"1"`   // Granularity of minute-based bookings; start, end and shifts must be multiples of it
+	AdminPrincipal     string `env:"ADMIN_PRINCIPAL" envDefault:""`         // Principal that may change any booking, empty for none
+	HoldTTL            int    `env:"HOLD_TTL" envDefault:"300000"`          // Time a held booking is kept until it must be confirmed in milliseconds
+	IdempotencyTTL     int    `env:"IDEMPOTENCY_TTL" envDefault:"86400000"` // Time the outcome of a request with an idempotency key is remembered in milliseconds
 
 	MatterMostWebhook string `env:"MATTERMOST_WEBHOOK" envDefault:""`
 }
@@ -43,6 +67,8 @@ func GetStaticEnv() *StaticEnvStruct {
 }
 
 func GetStaticEnvCopy() StaticEnvStruct {
+	rateLimitMethodsMu.RLock()
+	defer rateLimitMethodsMu.RUnlock()
 	return *GetStaticEnv()
 }
 
@@ -111,3 +137,151 @@ func SetResponseIntervals(val int) error {
 	slog.Info("[ENV] ResponseIntervals has been updated", "val", val)
 	return nil
 }
+
+func SetShutdownGracePeriod(val int) error {
+	if val < 0 {
+		return fmt.Errorf("val must be a possitive number")
+	}
+
+	GetStaticEnv().ShutdownGracePeriod = val
+	slog.Info("[ENV] ShutdownGracePeriod has been updated", "val", val)
+	return nil
+}
+
+func SetRateLimitRate(val float64) error {
+	if val < 0 {
+		return fmt.Errorf("val must be a possitive number")
+	}
+
+	GetStaticEnv().RateLimitRate = val
+	slog.Info("[ENV] RateLimitRate has been updated", "val", val)
+	return nil
+}
+
+func SetRateLimitBurst(val int) error {
+	if val < 1 {
+		return fmt.Errorf("val must be at least 1")
+	}
+
+	GetStaticEnv().RateLimitBurst = val
+	slog.Info("[ENV] RateLimitBurst has been updated", "val", val)
+	return nil
+}
+
+// rateLimitMethodsMu guards RateLimitMethodRates and RateLimitMethodBursts, which are changed through the console while
+// requests are rate limited (see StaticEnvStruct.RateLimitMethod).
+var rateLimitMethodsMu sync.RWMutex
+
+// RateLimitMethod returns the rate and burst of a single method, and whether the method has a rate of its own. The
+// burst defaults to RateLimitBurst.
+func (e *StaticEnvStruct) RateLimitMethod(method string) (float64, int, bool) {
+	rateLimitMethodsMu.RLock()
+	defer rateLimitMethodsMu.RUnlock()
+
+	rate, ok := e.RateLimitMethodRates[method]
+	burst, hasBurst := e.RateLimitMethodBursts[method]
+	if !hasBurst {
+		burst = e.RateLimitBurst
+	}
+	return rate, burst, ok
+}
+
+// RateLimitMethods returns copies of the rates and bursts of all methods with limits of their own.
+func (e *StaticEnvStruct) RateLimitMethods() (map[string]float64, map[string]int) {
+	rateLimitMethodsMu.RLock()
+	defer rateLimitMethodsMu.RUnlock()
+	return maps.Clone(e.RateLimitMethodRates), maps.Clone(e.RateLimitMethodBursts)
+}
+
+// SetRateLimitMethod sets the rate and burst of a single method. The maps are replaced rather than modified, as copies
+// of the configuration (see GetStaticEnvCopy) share them.
+func SetRateLimitMethod(method string, rate float64, burst int) error {
+	if rate < 0 {
+		return fmt.Errorf("rate must be a possitive number")
+	}
+	if burst < 1 {
+		return fmt.Errorf("burst must be at least 1")
+	}
+
+	rateLimitMethodsMu.Lock()
+	defer rateLimitMethodsMu.Unlock()
+
+	e := GetStaticEnv()
+	rates := maps.Clone(e.RateLimitMethodRates)
+	if rates == nil {
+		rates = make(map[string]float64)
+	}
+	bursts := maps.Clone(e.RateLimitMethodBursts)
+	if bursts == nil {
+		bursts = make(map[string]int)
+	}
+	rates[method] = rate
+	bursts[method] = burst
+	e.RateLimitMethodRates = rates
+	e.RateLimitMethodBursts = bursts
+
+	slog.Info("[ENV] RateLimitMethod has been updated", "method", method, "rate", rate, "burst", burst)
+	return nil
+}
+
+// ClearRateLimitMethod removes the limits of a single method, which is then only limited globally.
+func ClearRateLimitMethod(method string) error {
+	rateLimitMethodsMu.Lock()
+	defer rateLimitMethodsMu.Unlock()
+
+	e := GetStaticEnv()
+	rates := maps.Clone(e.RateLimitMethodRates)
+	bursts := maps.Clone(e.RateLimitMethodBursts)
+	delete(rates, method)
+	delete(bursts, method)
+	e.RateLimitMethodRates = rates
+	e.RateLimitMethodBursts = bursts
+
+	slog.Info("[ENV] RateLimitMethod has been cleared", "method", method)
+	return nil
+}
+
+func SetSnapshotInterval(val int) error {
+	if val < 0 {
+		return fmt.Errorf("val must be a possitive number")
+	}
+
+	GetStaticEnv().SnapshotInterval = val
+	slog.Info("[ENV] SnapshotInterval has been updated", "val", val)
+	return nil
+}
+
+func SetAdminPrincipal(val string) {
+	GetStaticEnv().AdminPrincipal = val
+	slog.Info("[ENV] AdminPrincipal has been updated")
+}
+
+func SetBookingSlotMinutes(val int) error {
+	if val < 1 || (24*60)%val != 0 {
+		return fmt.Errorf("val must divide a day (1440 minutes)")
+	}
+
+	GetStaticEnv().BookingSlotMinutes = val
+	slog.Info("[ENV] BookingSlotMinutes has been updated", "val", val)
+	return nil
+}
+
+func SetHoldTTL(val int) error {
+	if val < 1 {
+		return fmt.Errorf("val must be at least 1")
+	}
+
+	GetStaticEnv().HoldTTL = val
+	slog.Info("[ENV] HoldTTL has been updated", "val", val)
+	return nil
+}
+
+func SetIdempotencyTTL(val int) error {
+	if val < 1 {
+		return fmt.Errorf("val must be at least 1")
+	}
+
+	GetStaticEnv().IdempotencyTTL = val
+	slog.Info("[ENV] IdempotencyTTL has been updated", "val", val)
+	return nil
+}
@@ -0,0 +1,103 @@
+package integration_suite
+
+import (
+	"server/internal/bookings"
+	"server/internal/client"
+	"server/internal/interfaces"
+	"server/internal/rpc/request/request_constructor"
+	"server/internal/rpc/response"
+	"server/internal/server"
+	"server/internal/vars"
+	"server/tests/test_response"
+	"server/tests/test_server"
+	"testing"
+	"time"
+)
+
+func TestAuditQuery_changesOfBooking(t *testing.T) {
+
+	name := "TestAuditQuery_changesOfBooking"
+
+	env := vars.GetStaticEnvCopy()
+	env.AdminPrincipal = "admin"
+	serverPort := test_server.ServeRandomPort(t, server.WithEnv(env))
+
+	c, err := client.NewClient(
+		client.WithClientName(name),
+		client.WithTargetAsIpV4("127.0.0.1", serverPort),
+		client.WithTimeout(time.Duration(15)*time.Second),
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer c.Close()
+
+	now := time.Now()
+	tmr := time.Date(now.Year(), now.Month(), now.Day()+1, 0, 0, 0, 0, time.Local)
+	start := tmr.Add(time.Duration(10) * time.Hour)
+
+	bidChan := make(chan uint64, 1)
+
+	c.SendSyncWithValidator(
+		t,
+		[]interfaces.RpcRequestConstructor{
+			request_constructor.NewFacilityCreatePacket(name),
+			request_constructor.AsPrincipal("alice", request_constructor.NewBookingMakeV3Packet(name, start, start.Add(time.Hour))),
+		},
+		[]test_response.ResponseValidator{
+			test_response.BeStatus(response.StatusOk),
+			test_response.PacketMustPassAll(
+				test_response.BeStatus(response.StatusOk),
+				test_response.ExtractWideBookingId(bidChan),
+			),
+		},
+	)
+
+	bid := <-bidChan
+	entriesChan := make(chan []bookings.AuditEntry, 2)
+
+	c.SendSyncWithValidator(
+		t,
+		[]interfaces.RpcRequestConstructor{
+			request_constructor.AsPrincipal("alice", request_constructor.NewBookingModifyV3Packet(bid, time.Duration(30)*time.Minute)),
+			request_constructor.AsPrincipal("alice", request_constructor.NewFacilityUpdatePacket(name, 1, bookings.FacilityInfo{Timezone: "Asia/Singapore"})),
+
+			// Only the admin may query the audit log
+			request_constructor.NewAuditQueryPacket(bookings.AuditFilter{BookingId: bid}),
+			request_constructor.AsPrincipal("alice", request_constructor.NewAuditQueryPacket(bookings.AuditFilter{BookingId: bid})),
+			request_constructor.AsPrincipal("admin", request_constructor.NewAuditQueryPacket(bookings.AuditFilter{BookingId: bid})),
+			request_constructor.AsPrincipal("admin", request_constructor.NewAuditQueryPacket(bookings.AuditFilter{Facility: bookings.FacilityName(name)})),
+		},
+		[]test_response.ResponseValidator{
+			test_response.BeStatus(response.StatusOk),
+			test_response.BeStatus(response.StatusOk),
+			test_response.BeStatus(response.StatusForbidden),
+			test_response.BeStatus(response.StatusForbidden),
+			test_response.PacketMustPassAll(
+				test_response.BeStatus(response.StatusOk),
+				test_response.ExtractAudit(2, entriesChan),
+			),
+			test_response.PacketMustPassAll(
+				test_response.BeStatus(response.StatusOk),
+				test_response.ExtractAudit(4, entriesChan),
+			),
+		},
+	)
+
+	entries := <-entriesChan
+	made, shifted := entries[0], entries[1]
+	if made.Type != bookings.MutationBookingMake || made.Actor != "alice" || made.Address == "" || made.RequestId == "" {
+		t.Errorf("Expected booking to be made by alice from the client, got %+v", made)
+	}
+	if shifted.Type != bookings.MutationBookingUpdate || !shifted.Before.Start.Equal(start) || !shifted.After.Start.Equal(start.Add(time.Duration(30)*time.Minute)) {
+		t.Errorf("Expected booking to be shifted by 30 minutes, got %+v", shifted)
+	}
+
+	facility := <-entriesChan
+	if facility[0].Type != bookings.MutationFacilityCreate || facility[0].After.Capacity != 1 {
+		t.Errorf("Expected facility to be created with a capacity of 1, got %+v", facility[0])
+	}
+	if updated := facility[3]; updated.Type != bookings.MutationFacilityUpdate || updated.Actor != "alice" || updated.Before.Info == nil || updated.Before.Info.Timezone != "" || updated.After.Info == nil || updated.After.Info.Timezone != "Asia/Singapore" {
+		t.Errorf("Expected facility to be updated by alice to the timezone Asia/Singapore, got %+v", updated)
+	}
+}
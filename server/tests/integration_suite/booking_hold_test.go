@@ -0,0 +1,148 @@
+package integration_suite
+
+import (
+	"server/internal/bookings"
+	"server/internal/client"
+	"server/internal/interfaces"
+	"server/internal/rpc/request/request_constructor"
+	"server/internal/rpc/response"
+	"server/internal/server"
+	"server/internal/vars"
+	"server/tests/test_response"
+	"server/tests/test_server"
+	"testing"
+	"time"
+)
+
+func TestBookingHold_confirmAndExpire(t *testing.T) {
+
+	name := "TestBookingHold_confirmAndExpire"
+
+	env := vars.GetStaticEnvCopy()
+	env.HoldTTL = 1000
+	serverPort := test_server.ServeRandomPort(t, server.WithEnv(env))
+
+	c, err := client.NewClient(
+		client.WithClientName(name),
+		client.WithTargetAsIpV4("127.0.0.1", serverPort),
+		client.WithTimeout(time.Duration(15)*time.Second),
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer c.Close()
+
+	now := time.Now()
+	tmr := time.Date(now.Year(), now.Month(), now.Day()+1, 0, 0, 0, 0, time.Local)
+	start := tmr.Add(time.Duration(10) * time.Hour)
+
+	bidChan := make(chan uint64, 2)
+
+	c.SendSyncWithValidator(
+		t,
+		[]interfaces.RpcRequestConstructor{
+			request_constructor.NewFacilityCreatePacket(name),
+			request_constructor.NewBookingHoldPacket(name, start, start.Add(time.Hour)),
+			request_constructor.NewBookingHoldPacket(name, start.Add(time.Hour), start.Add(time.Duration(2)*time.Hour)),
+
+			// Held bookings take up the facility
+			request_constructor.NewBookingMakeV3Packet(name, start, start.Add(time.Hour)),
+		},
+		[]test_response.ResponseValidator{
+			test_response.BeStatus(response.StatusOk),
+			test_response.PacketMustPassAll(
+				test_response.BeStatus(response.StatusOk),
+				test_response.ExtractHold(bidChan),
+			),
+			test_response.PacketMustPassAll(
+				test_response.BeStatus(response.StatusOk),
+				test_response.ExtractHold(bidChan),
+			),
+			test_response.BeStatus(response.StatusBadRequest),
+		},
+	)
+
+	confirmed, expired := <-bidChan, <-bidChan
+
+	c.SendSyncWithValidator(
+		t,
+		[]interfaces.RpcRequestConstructor{
+			request_constructor.NewBookingConfirmPacket(confirmed),
+			request_constructor.NewBookingConfirmPacket(confirmed),
+		},
+		[]test_response.ResponseValidator{
+			test_response.BeStatus(response.StatusOk),
+			test_response.BeStatus(response.StatusBadRequest),
+		},
+	)
+
+	// The unconfirmed booking is released once its hold expires, freeing up its time
+	time.Sleep(time.Duration(env.HoldTTL+500) * time.Millisecond)
+
+	c.SendSyncWithValidator(
+		t,
+		[]interfaces.RpcRequestConstructor{
+			request_constructor.NewBookingConfirmPacket(expired),
+			request_constructor.NewBookingMakeV3Packet(name, start.Add(time.Hour), start.Add(time.Duration(2)*time.Hour)),
+		},
+		[]test_response.ResponseValidator{
+			test_response.BeStatus(response.StatusBadRequest),
+			test_response.BeStatus(response.StatusOk),
+		},
+	)
+}
+
+func TestBookingHold_sharedManager(t *testing.T) {
+
+	name := "TestBookingHold_sharedManager"
+
+	env := vars.GetStaticEnvCopy()
+	env.HoldTTL = 1000
+	env.DataDir = ""
+	manager := bookings.NewManager()
+	first := test_server.StartRandomPort(t, server.WithEnv(env), server.WithManager(manager))
+	second := test_server.StartRandomPort(t, server.WithEnv(env), server.WithManager(manager))
+
+	newClient := func(port int) *client.Client {
+		c, err := client.NewClient(
+			client.WithClientName(name),
+			client.WithTargetAsIpV4("127.0.0.1", port),
+			client.WithTimeout(time.Duration(15)*time.Second),
+		)
+		if err != nil {
+			t.Fatal(err)
+		}
+		t.Cleanup(c.Close)
+		return c
+	}
+
+	now := time.Now()
+	tmr := time.Date(now.Year(), now.Month(), now.Day()+1, 0, 0, 0, 0, time.Local)
+	start := tmr.Add(time.Duration(10) * time.Hour)
+
+	newClient(first.Port()).SendSyncWithValidator(
+		t,
+		[]interfaces.RpcRequestConstructor{
+			request_constructor.NewFacilityCreatePacket(name),
+			request_constructor.NewBookingHoldPacket(name, start, start.Add(time.Hour)),
+		},
+		[]test_response.ResponseValidator{
+			test_response.BeStatus(response.StatusOk),
+			test_response.BeStatus(response.StatusOk),
+		},
+	)
+
+	// Closing one Server leaves the manager it shares with the other Server running, which still releases the hold
+	first.Close()
+	time.Sleep(time.Duration(env.HoldTTL+500) * time.Millisecond)
+
+	newClient(second.Port()).SendSyncWithValidator(
+		t,
+		[]interfaces.RpcRequestConstructor{
+			request_constructor.NewBookingMakeV3Packet(name, start, start.Add(time.Hour)),
+		},
+		[]test_response.ResponseValidator{
+			test_response.BeStatus(response.StatusOk),
+		},
+	)
+}
@@ -0,0 +1,96 @@
+package integration_suite
+
+import (
+	"server/internal/bookings"
+	"server/internal/client"
+	"server/internal/interfaces"
+	"server/internal/rpc/request/request_constructor"
+	"server/internal/rpc/response"
+	"server/tests/test_response"
+	"server/tests/test_server"
+	"testing"
+	"time"
+)
+
+func TestBookingList_pagesAndOwners(t *testing.T) {
+
+	name := "TestBookingList_pagesAndOwners"
+	serverPort := test_server.ServeRandomPort(t)
+
+	c, err := client.NewClient(
+		client.WithClientName(name),
+		client.WithTargetAsIpV4("127.0.0.1", serverPort),
+		client.WithTimeout(time.Duration(15)*time.Second),
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer c.Close()
+
+	now := time.Now()
+	tmr := time.Date(now.Year(), now.Month(), now.Day()+1, 0, 0, 0, 0, time.Local)
+	start := tmr.Add(time.Duration(10) * time.Hour)
+
+	aliceChan := make(chan uint64, 1)
+	bobChan := make(chan uint64, 1)
+	firstPage := make(chan []bookings.Booking, 1)
+
+	c.SendSyncWithValidator(
+		t,
+		[]interfaces.RpcRequestConstructor{
+			request_constructor.NewFacilityCreatePacket(name),
+			request_constructor.AsPrincipal("alice", request_constructor.NewBookingMakeV3Packet(name, start, start.Add(time.Hour))),
+			request_constructor.AsPrincipal("bob", request_constructor.NewBookingMakeV3Packet(name, start.Add(time.Hour), start.Add(time.Duration(2)*time.Hour))),
+			request_constructor.NewBookingMakeV3Packet(name, start.Add(time.Duration(5)*time.Hour), start.Add(time.Duration(6)*time.Hour)),
+			request_constructor.AsPrincipal("alice", request_constructor.NewBookingListPacket(name, tmr, tmr.Add(time.Duration(24)*time.Hour), bookings.BookingCursor{}, 2)),
+			request_constructor.NewBookingListPacket("Unknown", tmr, tmr.Add(time.Duration(24)*time.Hour), bookings.BookingCursor{}, 0),
+		},
+		[]test_response.ResponseValidator{
+			test_response.BeStatus(response.StatusOk),
+			test_response.PacketMustPassAll(
+				test_response.BeStatus(response.StatusOk),
+				test_response.ExtractWideBookingId(aliceChan),
+			),
+			test_response.PacketMustPassAll(
+				test_response.BeStatus(response.StatusOk),
+				test_response.ExtractWideBookingId(bobChan),
+			),
+			test_response.BeStatus(response.StatusOk),
+			test_response.PacketMustPassAll(
+				test_response.BeStatus(response.StatusOk),
+				test_response.ExtractBookings(true, firstPage),
+			),
+			test_response.BeStatus(response.StatusBadRequest),
+		},
+	)
+
+	// Bookings are listed by start, with the owner only where the caller may change the booking
+	page := <-firstPage
+	if len(page) != 2 {
+		t.Fatalf("Expected 2 bookings, got %+v", page)
+	}
+	if aliceId := <-aliceChan; page[0].Id != aliceId || !page[0].Start.Equal(start) || !page[0].End.Equal(start.Add(time.Hour)) || page[0].Owner != "alice" {
+		t.Errorf("Unexpected booking of alice %+v", page[0])
+	}
+	if bobId := <-bobChan; page[1].Id != bobId || page[1].Owner != "" {
+		t.Errorf("Expected booking of bob without its owner, got %+v", page[1])
+	}
+
+	// The next page follows the last booking of the previous page
+	lastPage := make(chan []bookings.Booking, 1)
+	c.SendSyncWithValidator(
+		t,
+		[]interfaces.RpcRequestConstructor{
+			request_constructor.NewBookingListPacket(name, tmr, tmr.Add(time.Duration(24)*time.Hour), page[1].Cursor(), 2),
+		},
+		[]test_response.ResponseValidator{
+			test_response.PacketMustPassAll(
+				test_response.BeStatus(response.StatusOk),
+				test_response.ExtractBookings(false, lastPage),
+			),
+		},
+	)
+	if page := <-lastPage; len(page) != 1 || !page[0].Start.Equal(start.Add(time.Duration(5)*time.Hour)) {
+		t.Errorf("Unexpected last page %+v", page)
+	}
+}
@@ -0,0 +1,134 @@
+package integration_suite
+
+import (
+	"server/internal/client"
+	"server/internal/interfaces"
+	"server/internal/rpc/request/request_constructor"
+	"server/internal/rpc/response"
+	"server/internal/server"
+	"server/internal/vars"
+	"server/tests/test_response"
+	"server/tests/test_server"
+	"testing"
+	"time"
+)
+
+// availability returns the expected availability of days at the given resolution, with the slots of each booking
+// (given as start and end times) set.
+func availability(days int, resolution time.Duration, booked ...time.Time) []byte {
+	now := time.Now()
+	firstDate := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, time.Local)
+
+	schedule := make([]byte, (days*int(24*time.Hour/resolution)+7)/8)
+	for i := 0; i+1 < len(booked); i += 2 {
+		for slot := int(booked[i].Sub(firstDate) / resolution); slot < int(booked[i+1].Sub(firstDate)/resolution); slot++ {
+			schedule[slot/8] |= 1 << (7 - slot%8)
+		}
+	}
+	return schedule
+}
+
+func TestBookingMinutes_makeQueryUpdate(t *testing.T) {
+
+	name := "TestBookingMinutes_makeQueryUpdate"
+
+	env := vars.GetStaticEnvCopy()
+	env.BookingSlotMinutes = 15
+	serverPort := test_server.ServeRandomPort(t, server.WithEnv(env))
+
+	c, err := client.NewClient(
+		client.WithClientName(name),
+		client.WithTargetAsIpV4("127.0.0.1", serverPort),
+		client.WithTimeout(time.Duration(15)*time.Second),
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer c.Close()
+
+	now := time.Now()
+	tmr := time.Date(now.Year(), now.Month(), now.Day()+1, 0, 0, 0, 0, time.Local)
+	start := tmr.Add(time.Duration(10)*time.Hour + time.Duration(15)*time.Minute)
+	end := start.Add(time.Duration(30) * time.Minute)
+	shift := time.Duration(15) * time.Minute
+
+	bidChan := make(chan uint16, 1)
+
+	c.SendSyncWithValidator(
+		t,
+		[]interfaces.RpcRequestConstructor{
+			request_constructor.NewFacilityCreatePacket(name),
+			request_constructor.NewBookingMakeV2Packet(name, start, end),
+			request_constructor.NewFacilityQueryV2Packet(name, 2, 0), // Defaults to the booking slot
+		},
+		[]test_response.ResponseValidator{
+			test_response.BeStatus(response.StatusOk),
+			test_response.PacketMustPassAll(
+				test_response.BeStatus(response.StatusOk),
+				test_response.ExtractBookingId(bidChan),
+			),
+			test_response.PacketMustPassAll(
+				test_response.BeStatus(response.StatusOk),
+				test_response.HavePayload(availability(2, shift, start, end)),
+			),
+		},
+	)
+
+	c.SendSyncWithValidator(
+		t,
+		[]interfaces.RpcRequestConstructor{
+			request_constructor.NewBookingModifyV2Packet(<-bidChan, shift),
+			request_constructor.NewFacilityQueryV2Packet(name, 2, shift),
+			request_constructor.NewFacilityQueryV2Packet(name, 2, time.Hour),
+		},
+		[]test_response.ResponseValidator{
+			test_response.BeStatus(response.StatusOk),
+			test_response.PacketMustPassAll(
+				test_response.BeStatus(response.StatusOk),
+				test_response.HavePayload(availability(2, shift, start.Add(shift), end.Add(shift))),
+			),
+			test_response.PacketMustPassAll(
+				test_response.BeStatus(response.StatusOk),
+				test_response.HavePayload(availability(2, time.Hour, start.Truncate(time.Hour), end.Add(time.Hour).Truncate(time.Hour))),
+			),
+		},
+	)
+}
+
+func TestBookingMinutes_misalignedSlot(t *testing.T) {
+
+	name := "TestBookingMinutes_misalignedSlot"
+
+	env := vars.GetStaticEnvCopy()
+	env.BookingSlotMinutes = 15
+	serverPort := test_server.ServeRandomPort(t, server.WithEnv(env))
+
+	c, err := client.NewClient(
+		client.WithClientName(name),
+		client.WithTargetAsIpV4("127.0.0.1", serverPort),
+		client.WithTimeout(time.Duration(15)*time.Second),
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer c.Close()
+
+	start := time.Now().Add(time.Duration(24) * time.Hour).Truncate(time.Hour).Add(time.Duration(7) * time.Minute)
+
+	c.SendSyncWithValidator(
+		t,
+		[]interfaces.RpcRequestConstructor{
+			request_constructor.NewFacilityCreatePacket(name),
+			request_constructor.NewBookingMakeV2Packet(name, start, start.Add(time.Hour)),
+			// Hour-based bookings remain supported alongside minute-based bookings
+			request_constructor.NewBookingMakePacket(name, start.Add(time.Duration(2)*time.Hour), start.Add(time.Duration(3)*time.Hour)),
+			request_constructor.NewFacilityQueryV2Packet(name, 7, time.Minute),
+		},
+		[]test_response.ResponseValidator{
+			test_response.BeStatus(response.StatusOk),
+			test_response.BeStatus(response.StatusBadRequest),
+			test_response.BeStatus(response.StatusOk),
+			test_response.BeStatus(response.StatusBadRequest), // Exceeds a single response packet
+		},
+	)
+}
@@ -0,0 +1,82 @@
+package integration_suite
+
+import (
+	"server/internal/client"
+	"server/internal/interfaces"
+	"server/internal/rpc/request"
+	"server/internal/rpc/request/request_constructor"
+	"server/internal/rpc/response"
+	"server/tests/test_response"
+	"server/tests/test_server"
+	"testing"
+	"time"
+)
+
+func TestBookingMakeMulti_allOrNothing(t *testing.T) {
+
+	name := "TestBookingMakeMulti_allOrNothing"
+	hall, projector := name+"Hall", name+"Projector"
+	serverPort := test_server.ServeRandomPort(t)
+
+	c, err := client.NewClient(
+		client.WithClientName(name),
+		client.WithTargetAsIpV4("127.0.0.1", serverPort),
+		client.WithTimeout(time.Duration(15)*time.Second),
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer c.Close()
+
+	now := time.Now()
+	tmr := time.Date(now.Year(), now.Month(), now.Day()+1, 0, 0, 0, 0, time.Local)
+	start := tmr.Add(time.Duration(10) * time.Hour)
+
+	idsChan := make(chan []uint64, 1)
+
+	c.SendSyncWithValidator(
+		t,
+		[]interfaces.RpcRequestConstructor{
+			request_constructor.NewFacilityCreatePacket(hall),
+			request_constructor.NewFacilityCreatePacket(projector),
+			request_constructor.NewBookingMakeV3Packet(projector, start, start.Add(time.Hour)),
+
+			// The projector is taken, so the hall is not booked either
+			request_constructor.NewBookingMakeMultiPacket(
+				request.NewBookingMakePayloadV3(hall, start, start.Add(time.Hour)),
+				request.NewBookingMakePayloadV3(projector, start, start.Add(time.Hour)),
+			),
+			request_constructor.NewBookingMakeMultiPacket(
+				request.NewBookingMakePayloadV3(hall, start, start.Add(time.Hour)),
+				request.NewBookingMakePayloadV3(projector, start.Add(time.Hour), start.Add(time.Duration(2)*time.Hour)),
+			),
+		},
+		[]test_response.ResponseValidator{
+			test_response.BeStatus(response.StatusOk),
+			test_response.BeStatus(response.StatusOk),
+			test_response.BeStatus(response.StatusOk),
+			test_response.PacketMustPassAll(
+				test_response.BeStatus(response.StatusConflict),
+				test_response.HaveConflictAt(1),
+			),
+			test_response.PacketMustPassAll(
+				test_response.BeStatus(response.StatusOk),
+				test_response.ExtractMulti(2, idsChan),
+			),
+		},
+	)
+
+	ids := <-idsChan
+
+	c.SendSyncWithValidator(
+		t,
+		[]interfaces.RpcRequestConstructor{
+			request_constructor.NewBookingDeleteV2Packet(ids[0]),
+			request_constructor.NewBookingDeleteV2Packet(ids[1]),
+		},
+		[]test_response.ResponseValidator{
+			test_response.BeStatus(response.StatusOk),
+			test_response.BeStatus(response.StatusOk),
+		},
+	)
+}
@@ -0,0 +1,84 @@
+package integration_suite
+
+import (
+	"server/internal/client"
+	"server/internal/interfaces"
+	"server/internal/rpc/request/request_constructor"
+	"server/internal/rpc/response"
+	"server/internal/server"
+	"server/internal/vars"
+	"server/tests/test_response"
+	"server/tests/test_server"
+	"testing"
+	"time"
+)
+
+func TestBookingOwner_onlyOwnerOrAdminMayChange(t *testing.T) {
+
+	name := "TestBookingOwner_onlyOwnerOrAdminMayChange"
+
+	env := vars.GetStaticEnvCopy()
+	env.AdminPrincipal = "admin"
+	serverPort := test_server.ServeRandomPort(t, server.WithEnv(env))
+
+	c, err := client.NewClient(
+		client.WithClientName(name),
+		client.WithTargetAsIpV4("127.0.0.1", serverPort),
+		client.WithTimeout(time.Duration(15)*time.Second),
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer c.Close()
+
+	now := time.Now()
+	tmr := time.Date(now.Year(), now.Month(), now.Day()+1, 0, 0, 0, 0, time.Local)
+	start := tmr.Add(time.Duration(10) * time.Hour)
+
+	bidChan := make(chan uint64, 2)
+
+	c.SendSyncWithValidator(
+		t,
+		[]interfaces.RpcRequestConstructor{
+			request_constructor.NewFacilityCreatePacket(name),
+			request_constructor.AsPrincipal("alice", request_constructor.NewBookingMakeV3Packet(name, start, start.Add(time.Hour))),
+			request_constructor.AsPrincipal("alice", request_constructor.NewBookingMakeV3Packet(name, start.Add(time.Duration(2)*time.Hour), start.Add(time.Duration(3)*time.Hour))),
+		},
+		[]test_response.ResponseValidator{
+			test_response.BeStatus(response.StatusOk),
+			test_response.PacketMustPassAll(
+				test_response.BeStatus(response.StatusOk),
+				test_response.ExtractWideBookingId(bidChan),
+			),
+			test_response.PacketMustPassAll(
+				test_response.BeStatus(response.StatusOk),
+				test_response.ExtractWideBookingId(bidChan),
+			),
+		},
+	)
+
+	bid1, bid2 := <-bidChan, <-bidChan
+
+	c.SendSyncWithValidator(
+		t,
+		[]interfaces.RpcRequestConstructor{
+			// Other principals and anonymous callers may not change the bookings
+			request_constructor.AsPrincipal("bob", request_constructor.NewBookingModifyV3Packet(bid1, time.Duration(30)*time.Minute)),
+			request_constructor.AsPrincipal("bob", request_constructor.NewBookingDeleteV2Packet(bid1)),
+			request_constructor.NewBookingDeleteV2Packet(bid1),
+
+			// The owner and the admin may
+			request_constructor.AsPrincipal("alice", request_constructor.NewBookingModifyV3Packet(bid1, time.Duration(30)*time.Minute)),
+			request_constructor.AsPrincipal("admin", request_constructor.NewBookingDeleteV2Packet(bid2)),
+			request_constructor.AsPrincipal("alice", request_constructor.NewBookingDeleteV2Packet(bid1)),
+		},
+		[]test_response.ResponseValidator{
+			test_response.BeStatus(response.StatusForbidden),
+			test_response.BeStatus(response.StatusForbidden),
+			test_response.BeStatus(response.StatusForbidden),
+			test_response.BeStatus(response.StatusOk),
+			test_response.BeStatus(response.StatusOk),
+			test_response.BeStatus(response.StatusOk),
+		},
+	)
+}
@@ -0,0 +1,75 @@
+package integration_suite
+
+import (
+	"server/internal/client"
+	"server/internal/interfaces"
+	"server/internal/rpc/request/request_constructor"
+	"server/internal/rpc/response"
+	"server/tests/test_response"
+	"server/tests/test_server"
+	"testing"
+	"time"
+)
+
+func TestBookingResize_extendAndShorten(t *testing.T) {
+
+	name := "TestBookingResize_extendAndShorten"
+	serverPort := test_server.ServeRandomPort(t)
+
+	c, err := client.NewClient(
+		client.WithClientName(name),
+		client.WithTargetAsIpV4("127.0.0.1", serverPort),
+		client.WithTimeout(time.Duration(15)*time.Second),
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer c.Close()
+
+	now := time.Now()
+	tmr := time.Date(now.Year(), now.Month(), now.Day()+1, 0, 0, 0, 0, time.Local)
+	start := tmr.Add(time.Duration(10) * time.Hour)
+
+	bidChan := make(chan uint64, 1)
+
+	c.SendSyncWithValidator(
+		t,
+		[]interfaces.RpcRequestConstructor{
+			request_constructor.NewFacilityCreatePacket(name),
+			request_constructor.NewBookingMakeV3Packet(name, start, start.Add(time.Hour)),
+			request_constructor.NewBookingMakeV3Packet(name, start.Add(time.Duration(3)*time.Hour), start.Add(time.Duration(4)*time.Hour)),
+		},
+		[]test_response.ResponseValidator{
+			test_response.BeStatus(response.StatusOk),
+			test_response.PacketMustPassAll(
+				test_response.BeStatus(response.StatusOk),
+				test_response.ExtractWideBookingId(bidChan),
+			),
+			test_response.BeStatus(response.StatusOk),
+		},
+	)
+
+	bid := <-bidChan
+
+	c.SendSyncWithValidator(
+		t,
+		[]interfaces.RpcRequestConstructor{
+			// Extend by an hour, overlapping the booking's own time
+			request_constructor.NewBookingResizePacket(bid, time.Time{}, start.Add(time.Duration(2)*time.Hour)),
+			// Clashes with the other booking
+			request_constructor.NewBookingResizePacket(bid, time.Time{}, start.Add(time.Duration(4)*time.Hour)),
+			// Ends before it starts
+			request_constructor.NewBookingResizePacket(bid, start.Add(time.Duration(3)*time.Hour), time.Time{}),
+			// Start earlier and end earlier
+			request_constructor.NewBookingResizePacket(bid, start.Add(-time.Hour), start.Add(time.Duration(30)*time.Minute)),
+			request_constructor.NewBookingResizePacket(bid+1, time.Time{}, start.Add(time.Hour)),
+		},
+		[]test_response.ResponseValidator{
+			test_response.BeStatus(response.StatusOk),
+			test_response.BeStatus(response.StatusBadRequest),
+			test_response.BeStatus(response.StatusBadRequest),
+			test_response.BeStatus(response.StatusOk),
+			test_response.BeStatus(response.StatusBadRequest),
+		},
+	)
+}
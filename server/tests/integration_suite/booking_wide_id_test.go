@@ -0,0 +1,65 @@
+package integration_suite
+
+import (
+	"server/internal/client"
+	"server/internal/interfaces"
+	"server/internal/rpc/request/request_constructor"
+	"server/internal/rpc/response"
+	"server/tests/test_response"
+	"server/tests/test_server"
+	"testing"
+	"time"
+)
+
+func TestBookingWideId_makeUpdateDelete(t *testing.T) {
+
+	name := "TestBookingWideId_makeUpdateDelete"
+	serverPort := test_server.ServeRandomPort(t)
+
+	c, err := client.NewClient(
+		client.WithClientName(name),
+		client.WithTargetAsIpV4("127.0.0.1", serverPort),
+		client.WithTimeout(time.Duration(15)*time.Second),
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer c.Close()
+
+	now := time.Now()
+	tmr := time.Date(now.Year(), now.Month(), now.Day()+1, 0, 0, 0, 0, time.Local)
+	start := tmr.Add(time.Duration(10) * time.Hour)
+
+	bidChan := make(chan uint64, 1)
+
+	c.SendSyncWithValidator(
+		t,
+		[]interfaces.RpcRequestConstructor{
+			request_constructor.NewFacilityCreatePacket(name),
+			request_constructor.NewBookingMakeV3Packet(name, start, start.Add(time.Hour)),
+		},
+		[]test_response.ResponseValidator{
+			test_response.BeStatus(response.StatusOk),
+			test_response.PacketMustPassAll(
+				test_response.BeStatus(response.StatusOk),
+				test_response.ExtractWideBookingId(bidChan),
+			),
+		},
+	)
+
+	bid := <-bidChan
+
+	c.SendSyncWithValidator(
+		t,
+		[]interfaces.RpcRequestConstructor{
+			request_constructor.NewBookingModifyV3Packet(bid, time.Duration(30)*time.Minute),
+			request_constructor.NewBookingDeleteV2Packet(bid),
+			request_constructor.NewBookingDeleteV2Packet(bid),
+		},
+		[]test_response.ResponseValidator{
+			test_response.BeStatus(response.StatusOk),
+			test_response.BeStatus(response.StatusOk),
+			test_response.BeStatus(response.StatusBadRequest),
+		},
+	)
+}
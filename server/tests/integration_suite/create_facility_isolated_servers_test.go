@@ -0,0 +1,40 @@
+package integration_suite
+
+import (
+	"server/internal/client"
+	"server/internal/interfaces"
+	"server/internal/rpc/request/request_constructor"
+	"server/internal/rpc/response"
+	"server/tests/test_response"
+	"server/tests/test_server"
+	"testing"
+	"time"
+)
+
+func TestCreateFacility_isolated_servers(t *testing.T) {
+
+	// The same facility is created on both servers; this should only succeed if they do not share state
+	for _, serverPort := range []int{test_server.ServeRandomPort(t), test_server.ServeRandomPort(t)} {
+		c, err := client.NewClient(
+			client.WithClientName("TestCreateFacility_isolated_servers"),
+			client.WithTargetAsIpV4("127.0.0.1", serverPort),
+			client.WithTimeout(time.Duration(15)*time.Second),
+		)
+		if err != nil {
+			t.Error(err)
+		}
+
+		c.SendSyncWithValidator(
+			t,
+			[]interfaces.RpcRequestConstructor{
+				request_constructor.NewFacilityCreatePacket("TestCreateFacility_isolated_servers"),
+			},
+			[]test_response.ResponseValidator{
+				test_response.BeStatus(response.StatusOk),
+			},
+		)
+
+		c.Close()
+	}
+
+}
@@ -5,18 +5,15 @@ import (
 	"server/internal/interfaces"
 	"server/internal/rpc/request/request_constructor"
 	"server/internal/rpc/response"
-	"server/internal/server"
 	"server/tests/test_response"
+	"server/tests/test_server"
 	"testing"
 	"time"
 )
 
 func TestDeleteBooking_successful(t *testing.T) {
 
-	serverPort, err := server.ServeRandomPort()
-	if err != nil {
-		t.Error(err)
-	}
+	serverPort := test_server.ServeRandomPort(t)
 
 	c, err := client.NewClient(
 		client.WithClientName("TestDeleteBooking_successful"),
@@ -0,0 +1,67 @@
+package integration_suite
+
+import (
+	"server/internal/client"
+	"server/internal/interfaces"
+	"server/internal/rpc/request/request_constructor"
+	"server/internal/rpc/response"
+	"server/tests/test_response"
+	"server/tests/test_server"
+	"testing"
+	"time"
+)
+
+func TestFacilityCapacity_bookUntilFull(t *testing.T) {
+
+	name := "TestFacilityCapacity_bookUntilFull"
+	serverPort := test_server.ServeRandomPort(t)
+
+	c, err := client.NewClient(
+		client.WithClientName(name),
+		client.WithTargetAsIpV4("127.0.0.1", serverPort),
+		client.WithTimeout(time.Duration(15)*time.Second),
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer c.Close()
+
+	now := time.Now()
+	tmr := time.Date(now.Year(), now.Month(), now.Day()+1, 0, 0, 0, 0, time.Local)
+	start := tmr.Add(time.Duration(10) * time.Hour)
+	end := start.Add(time.Hour)
+
+	expected := make([]byte, 48)
+	for i := range expected {
+		expected[i] = 2
+	}
+	expected[34] = 0
+
+	c.SendSyncWithValidator(
+		t,
+		[]interfaces.RpcRequestConstructor{
+			request_constructor.NewFacilityCreateV2Packet(name, 2),
+			request_constructor.NewBookingMakeV2Packet(name, start, end),
+			request_constructor.NewBookingMakeV2Packet(name, start, end),
+			request_constructor.NewBookingMakeV2Packet(name, start, end),
+			request_constructor.NewFacilityQueryCapacityPacket(name, 2, time.Hour),
+			request_constructor.NewFacilityQueryV2Packet(name, 2, time.Hour),
+			request_constructor.NewFacilityCreateV2Packet(name+"Empty", 0),
+		},
+		[]test_response.ResponseValidator{
+			test_response.BeStatus(response.StatusOk),
+			test_response.BeStatus(response.StatusOk),
+			test_response.BeStatus(response.StatusOk),
+			test_response.BeStatus(response.StatusBadRequest),
+			test_response.PacketMustPassAll(
+				test_response.BeStatus(response.StatusOk),
+				test_response.HavePayload(expected),
+			),
+			test_response.PacketMustPassAll(
+				test_response.BeStatus(response.StatusOk),
+				test_response.HavePayload(availability(2, time.Hour, start, end)),
+			),
+			test_response.BeStatus(response.StatusBadRequest),
+		},
+	)
+}
@@ -0,0 +1,65 @@
+package integration_suite
+
+import (
+	"server/internal/bookings"
+	"server/internal/client"
+	"server/internal/interfaces"
+	"server/internal/rpc/request/request_constructor"
+	"server/internal/rpc/response"
+	"server/tests/test_response"
+	"server/tests/test_server"
+	"testing"
+	"time"
+)
+
+func TestFacilityCommonFree_acrossFacilities(t *testing.T) {
+
+	name := "TestFacilityCommonFree_acrossFacilities"
+	serverPort := test_server.ServeRandomPort(t)
+
+	c, err := client.NewClient(
+		client.WithClientName(name),
+		client.WithTargetAsIpV4("127.0.0.1", serverPort),
+		client.WithTimeout(time.Duration(15)*time.Second),
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer c.Close()
+
+	now := time.Now()
+	tmr := time.Date(now.Year(), now.Month(), now.Day()+1, 0, 0, 0, 0, time.Local)
+	start := tmr.Add(time.Duration(9) * time.Hour)
+	hall, projector := name+"Hall", name+"Projector"
+
+	freeChan := make(chan []bookings.Interval, 1)
+
+	c.SendSyncWithValidator(
+		t,
+		[]interfaces.RpcRequestConstructor{
+			request_constructor.NewFacilityCreatePacket(hall),
+			request_constructor.NewFacilityCreatePacket(projector),
+			request_constructor.NewBookingMakeV3Packet(hall, start, start.Add(time.Hour)),
+			request_constructor.NewBookingMakeV3Packet(projector, start.Add(time.Duration(2)*time.Hour), start.Add(time.Duration(3)*time.Hour)),
+			request_constructor.NewFacilityCommonFreePacket([]string{hall, projector}, start, start.Add(time.Duration(4)*time.Hour), time.Hour),
+			request_constructor.NewFacilityCommonFreePacket([]string{hall, name + "Missing"}, start, start.Add(time.Duration(4)*time.Hour), time.Hour),
+		},
+		[]test_response.ResponseValidator{
+			test_response.BeStatus(response.StatusOk),
+			test_response.BeStatus(response.StatusOk),
+			test_response.BeStatus(response.StatusOk),
+			test_response.BeStatus(response.StatusOk),
+			test_response.PacketMustPassAll(
+				test_response.BeStatus(response.StatusOk),
+				test_response.ExtractSlots(2, freeChan),
+			),
+			test_response.BeStatus(response.StatusBadRequest),
+		},
+	)
+
+	// Both facilities are free from 10 to 11 and from 12 to 13
+	free := <-freeChan
+	if !free[0].Start.Equal(start.Add(time.Hour)) || !free[1].Start.Equal(start.Add(time.Duration(3)*time.Hour)) {
+		t.Errorf("Expected common free time after each booking, got %v", free)
+	}
+}
@@ -0,0 +1,63 @@
+package integration_suite
+
+import (
+	"server/internal/bookings"
+	"server/internal/client"
+	"server/internal/interfaces"
+	"server/internal/rpc/request/request_constructor"
+	"server/internal/rpc/response"
+	"server/tests/test_response"
+	"server/tests/test_server"
+	"testing"
+	"time"
+)
+
+func TestFacilityFreeSlots_afterBookings(t *testing.T) {
+
+	name := "TestFacilityFreeSlots_afterBookings"
+	serverPort := test_server.ServeRandomPort(t)
+
+	c, err := client.NewClient(
+		client.WithClientName(name),
+		client.WithTargetAsIpV4("127.0.0.1", serverPort),
+		client.WithTimeout(time.Duration(15)*time.Second),
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer c.Close()
+
+	now := time.Now()
+	tmr := time.Date(now.Year(), now.Month(), now.Day()+1, 0, 0, 0, 0, time.Local)
+	start := tmr.Add(time.Duration(10) * time.Hour)
+
+	slotsChan := make(chan []bookings.Interval, 1)
+
+	c.SendSyncWithValidator(
+		t,
+		[]interfaces.RpcRequestConstructor{
+			request_constructor.NewFacilityCreatePacket(name),
+			request_constructor.NewBookingMakeV3Packet(name, start, start.Add(time.Hour)),
+			request_constructor.NewFacilityFreeSlotsPacket(name, start, start.Add(time.Duration(4)*time.Hour), time.Duration(90)*time.Minute, 3, false),
+
+			// Searches that cannot be answered within a single packet, or for a facility that does not exist
+			request_constructor.NewFacilityFreeSlotsPacket(name, start, start.Add(time.Duration(4)*time.Hour), time.Hour, 0xFF, false),
+			request_constructor.NewFacilityFreeSlotsPacket(name+"Missing", start, start.Add(time.Duration(4)*time.Hour), time.Hour, 1, false),
+		},
+		[]test_response.ResponseValidator{
+			test_response.BeStatus(response.StatusOk),
+			test_response.BeStatus(response.StatusOk),
+			test_response.PacketMustPassAll(
+				test_response.BeStatus(response.StatusOk),
+				test_response.ExtractSlots(2, slotsChan),
+			),
+			test_response.BeStatus(response.StatusBadRequest),
+			test_response.BeStatus(response.StatusBadRequest),
+		},
+	)
+
+	slots := <-slotsChan
+	if !slots[0].Start.Equal(start.Add(time.Hour)) || !slots[1].Start.Equal(slots[0].End) {
+		t.Errorf("Expected free periods to start once the booking ends, got %v", slots)
+	}
+}
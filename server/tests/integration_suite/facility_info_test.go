@@ -0,0 +1,59 @@
+package integration_suite
+
+import (
+	"server/internal/bookings"
+	"server/internal/client"
+	"server/internal/interfaces"
+	"server/internal/rpc/request/request_constructor"
+	"server/internal/rpc/response"
+	"server/tests/test_response"
+	"server/tests/test_server"
+	"testing"
+	"time"
+)
+
+func TestFacilityInfo_openingHours(t *testing.T) {
+
+	name := "TestFacilityInfo_openingHours"
+	serverPort := test_server.ServeRandomPort(t)
+
+	c, err := client.NewClient(
+		client.WithClientName(name),
+		client.WithTargetAsIpV4("127.0.0.1", serverPort),
+		client.WithTimeout(time.Duration(15)*time.Second),
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer c.Close()
+
+	hours := make([]bookings.OpeningHours, 7)
+	for i := range hours {
+		hours[i] = bookings.OpeningHours{Open: time.Duration(9) * time.Hour, Close: time.Duration(17) * time.Hour}
+	}
+	info := bookings.FacilityInfo{Description: "Seminar room", Location: "Level 2", Hours: hours}
+
+	now := time.Now()
+	tmr := time.Date(now.Year(), now.Month(), now.Day()+1, 0, 0, 0, 0, time.Local)
+	evening := tmr.Add(time.Duration(18) * time.Hour)
+
+	c.SendSyncWithValidator(
+		t,
+		[]interfaces.RpcRequestConstructor{
+			request_constructor.NewFacilityCreateV3Packet(name, 1, info),
+			request_constructor.NewBookingMakeV2Packet(name, tmr.Add(time.Duration(10)*time.Hour), tmr.Add(time.Duration(11)*time.Hour)),
+			request_constructor.NewBookingMakeV2Packet(name, evening, evening.Add(time.Hour)),
+			request_constructor.NewFacilityUpdatePacket(name, 1, bookings.FacilityInfo{}), // Open all day
+			request_constructor.NewBookingMakeV2Packet(name, evening, evening.Add(time.Hour)),
+			request_constructor.NewFacilityUpdatePacket(name, 1, bookings.FacilityInfo{Timezone: "Nowhere/Nowhere"}),
+		},
+		[]test_response.ResponseValidator{
+			test_response.BeStatus(response.StatusOk),
+			test_response.BeStatus(response.StatusOk),
+			test_response.BeStatus(response.StatusBadRequest),
+			test_response.BeStatus(response.StatusOk),
+			test_response.BeStatus(response.StatusOk),
+			test_response.BeStatus(response.StatusBadRequest),
+		},
+	)
+}
@@ -0,0 +1,90 @@
+package integration_suite
+
+import (
+	"server/internal/bookings"
+	"server/internal/client"
+	"server/internal/interfaces"
+	"server/internal/rpc/request/request_constructor"
+	"server/internal/rpc/response"
+	"server/tests/test_response"
+	"server/tests/test_server"
+	"testing"
+	"time"
+)
+
+func TestFacilityList_pages(t *testing.T) {
+
+	name := "TestFacilityList_pages"
+	serverPort := test_server.ServeRandomPort(t)
+
+	c, err := client.NewClient(
+		client.WithClientName(name),
+		client.WithTargetAsIpV4("127.0.0.1", serverPort),
+		client.WithTimeout(time.Duration(15)*time.Second),
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer c.Close()
+
+	now := time.Now()
+	tmr := time.Date(now.Year(), now.Month(), now.Day()+1, 0, 0, 0, 0, time.Local)
+	start := tmr.Add(time.Duration(10) * time.Hour)
+
+	firstPage := make(chan []bookings.FacilitySummary, 1)
+	byBookings := make(chan []bookings.FacilitySummary, 1)
+
+	c.SendSyncWithValidator(
+		t,
+		[]interfaces.RpcRequestConstructor{
+			request_constructor.NewFacilityCreatePacket(name + " C"),
+			request_constructor.NewFacilityCreatePacket(name + " A"),
+			request_constructor.NewFacilityCreatePacket(name + " B"),
+			request_constructor.NewFacilityCreatePacket("Other"),
+			request_constructor.NewBookingMakeV3Packet(name+" B", start, start.Add(time.Hour)),
+			request_constructor.NewFacilityListPacket(bookings.FacilityListFilter{Prefix: name}, 2),
+			request_constructor.NewFacilityListPacket(bookings.FacilityListFilter{Contains: "list", Sort: bookings.FacilitySortBookings}, 1),
+		},
+		[]test_response.ResponseValidator{
+			test_response.BeStatus(response.StatusOk),
+			test_response.BeStatus(response.StatusOk),
+			test_response.BeStatus(response.StatusOk),
+			test_response.BeStatus(response.StatusOk),
+			test_response.BeStatus(response.StatusOk),
+			test_response.PacketMustPassAll(
+				test_response.BeStatus(response.StatusOk),
+				test_response.ExtractFacilities(true, firstPage),
+			),
+			test_response.PacketMustPassAll(
+				test_response.BeStatus(response.StatusOk),
+				test_response.ExtractFacilities(true, byBookings),
+			),
+		},
+	)
+
+	page := <-firstPage
+	if len(page) != 2 || page[0].Name != bookings.FacilityName(name+" A") || page[1].Name != bookings.FacilityName(name+" B") {
+		t.Fatalf("Unexpected first page %+v", page)
+	}
+	if busiest := <-byBookings; len(busiest) != 1 || busiest[0].Name != bookings.FacilityName(name+" B") || busiest[0].Bookings != 1 {
+		t.Errorf("Expected facility with the most bookings first, got %+v", busiest)
+	}
+
+	// The next page follows the last facility of the previous page
+	lastPage := make(chan []bookings.FacilitySummary, 1)
+	c.SendSyncWithValidator(
+		t,
+		[]interfaces.RpcRequestConstructor{
+			request_constructor.NewFacilityListPacket(bookings.FacilityListFilter{Prefix: name, After: page[1].Cursor()}, 2),
+		},
+		[]test_response.ResponseValidator{
+			test_response.PacketMustPassAll(
+				test_response.BeStatus(response.StatusOk),
+				test_response.ExtractFacilities(false, lastPage),
+			),
+		},
+	)
+	if page := <-lastPage; len(page) != 1 || page[0].Name != bookings.FacilityName(name+" C") {
+		t.Errorf("Unexpected last page %+v", page)
+	}
+}
@@ -0,0 +1,65 @@
+package integration_suite
+
+import (
+	"server/internal/client"
+	"server/internal/interfaces"
+	"server/internal/rpc/request/request_constructor"
+	"server/internal/rpc/response"
+	"server/tests/test_response"
+	"server/tests/test_server"
+	"testing"
+	"time"
+)
+
+func TestFacilityQuery_fromDateInTimezone(t *testing.T) {
+
+	name := "TestFacilityQuery_fromDateInTimezone"
+	serverPort := test_server.ServeRandomPort(t)
+
+	c, err := client.NewClient(
+		client.WithClientName(name),
+		client.WithTargetAsIpV4("127.0.0.1", serverPort),
+		client.WithTimeout(time.Duration(15)*time.Second),
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer c.Close()
+
+	// A booking from 10 to 11 a week from now, for a client 14 hours ahead of UTC
+	loc := time.FixedZone("UTC+14", 14*60*60)
+	now := time.Now().In(loc)
+	date := time.Date(now.Year(), now.Month(), now.Day()+7, 0, 0, 0, 0, loc)
+	start := date.Add(time.Duration(10) * time.Hour)
+	end := start.Add(time.Hour)
+
+	capacity := make([]byte, 24)
+	for i := range capacity {
+		capacity[i] = 1
+	}
+	capacity[10] = 0
+
+	c.SendSyncWithValidator(
+		t,
+		[]interfaces.RpcRequestConstructor{
+			request_constructor.NewFacilityCreatePacket(name),
+			request_constructor.NewBookingMakeV2Packet(name, start, end),
+			request_constructor.NewFacilityQueryCapacityV5Packet(name, date, "UTC+14", 1, time.Hour),
+			request_constructor.NewFacilityQueryV5Packet(name, date, "UTC+14", 1, time.Hour),
+			request_constructor.NewFacilityQueryV5Packet(name, date, "Nowhere/Special", 1, time.Hour),
+		},
+		[]test_response.ResponseValidator{
+			test_response.BeStatus(response.StatusOk),
+			test_response.BeStatus(response.StatusOk),
+			test_response.PacketMustPassAll(
+				test_response.BeStatus(response.StatusOk),
+				test_response.HavePayload(capacity),
+			),
+			test_response.PacketMustPassAll(
+				test_response.BeStatus(response.StatusOk),
+				test_response.HavePayload([]byte{0x00, 0x20, 0x00}),
+			),
+			test_response.BeStatus(response.StatusBadRequest),
+		},
+	)
+}
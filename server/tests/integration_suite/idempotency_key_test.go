@@ -0,0 +1,96 @@
+package integration_suite
+
+import (
+	"server/internal/bookings"
+	"server/internal/client"
+	"server/internal/interfaces"
+	"server/internal/rpc/request/request_constructor"
+	"server/internal/rpc/response"
+	"server/tests/test_response"
+	"server/tests/test_server"
+	"testing"
+	"time"
+)
+
+func TestIdempotencyKey_repeatedMutations(t *testing.T) {
+
+	name := "TestIdempotencyKey_repeatedMutations"
+	serverPort := test_server.ServeRandomPort(t)
+
+	c, err := client.NewClient(
+		client.WithClientName(name),
+		client.WithTargetAsIpV4("127.0.0.1", serverPort),
+		client.WithTimeout(time.Duration(15)*time.Second),
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer c.Close()
+
+	now := time.Now()
+	tmr := time.Date(now.Year(), now.Month(), now.Day()+1, 0, 0, 0, 0, time.Local)
+	start := tmr.Add(time.Duration(10) * time.Hour)
+
+	firstChan := make(chan uint64, 1)
+	repeatChan := make(chan uint64, 1)
+
+	// Each request is sent with a new message Id, as if the client had restarted, but the same idempotency key
+	c.SendSyncWithValidator(
+		t,
+		[]interfaces.RpcRequestConstructor{
+			request_constructor.WithIdempotencyKey("create", request_constructor.AsPrincipal("alice", request_constructor.NewFacilityCreateV2Packet(name, 1))),
+			request_constructor.WithIdempotencyKey("create", request_constructor.AsPrincipal("alice", request_constructor.NewFacilityCreateV2Packet(name, 1))),
+			request_constructor.NewFacilityCreateV2Packet(name, 1),
+			request_constructor.WithIdempotencyKey("anonymous", request_constructor.NewFacilityCreateV2Packet(name+"Anonymous", 1)),
+			request_constructor.WithIdempotencyKey("make", request_constructor.AsPrincipal("alice", request_constructor.NewBookingMakeV3Packet(name, start, start.Add(time.Hour)))),
+			request_constructor.WithIdempotencyKey("make", request_constructor.AsPrincipal("alice", request_constructor.NewBookingMakeV3Packet(name, start, start.Add(time.Hour)))),
+			request_constructor.WithIdempotencyKey("make", request_constructor.AsPrincipal("alice", request_constructor.NewBookingMakeV3Packet(name, start.Add(time.Hour), start.Add(time.Duration(2)*time.Hour)))),
+		},
+		[]test_response.ResponseValidator{
+			test_response.BeStatus(response.StatusOk),
+			test_response.BeStatus(response.StatusOk),
+			test_response.BeStatus(response.StatusBadRequest),
+			test_response.BeStatus(response.StatusBadRequest),
+			test_response.PacketMustPassAll(
+				test_response.BeStatus(response.StatusOk),
+				test_response.ExtractWideBookingId(firstChan),
+			),
+			test_response.PacketMustPassAll(
+				test_response.BeStatus(response.StatusOk),
+				test_response.ExtractWideBookingId(repeatChan),
+			),
+			test_response.BeStatus(response.StatusBadRequest),
+		},
+	)
+
+	bid := <-firstChan
+	if repeat := <-repeatChan; repeat != bid {
+		t.Fatalf("Expected repeated booking to be answered with booking %d, got %d", bid, repeat)
+	}
+
+	c.SendSyncWithValidator(
+		t,
+		[]interfaces.RpcRequestConstructor{
+			request_constructor.WithIdempotencyKey("delete", request_constructor.AsPrincipal("alice", request_constructor.NewBookingDeleteV2Packet(bid))),
+			request_constructor.WithIdempotencyKey("delete", request_constructor.AsPrincipal("alice", request_constructor.NewBookingDeleteV2Packet(bid))),
+			request_constructor.NewBookingDeleteV2Packet(bid),
+			request_constructor.WithIdempotencyKey("update", request_constructor.AsPrincipal("alice", request_constructor.NewFacilityUpdatePacket(name, 2, bookings.FacilityInfo{}))),
+			request_constructor.WithIdempotencyKey("update", request_constructor.AsPrincipal("alice", request_constructor.NewFacilityUpdatePacket(name, 2, bookings.FacilityInfo{}))),
+			request_constructor.WithIdempotencyKey("update", request_constructor.AsPrincipal("alice", request_constructor.NewFacilityUpdatePacket(name, 3, bookings.FacilityInfo{}))),
+			request_constructor.WithIdempotencyKey("delete-facility", request_constructor.AsPrincipal("alice", request_constructor.NewFacilityDeleteV2Packet(name))),
+			request_constructor.WithIdempotencyKey("delete-facility", request_constructor.AsPrincipal("alice", request_constructor.NewFacilityDeleteV2Packet(name))),
+			request_constructor.NewFacilityDeleteV2Packet(name),
+		},
+		[]test_response.ResponseValidator{
+			test_response.BeStatus(response.StatusOk),
+			test_response.BeStatus(response.StatusOk),
+			test_response.BeStatus(response.StatusBadRequest),
+			test_response.BeStatus(response.StatusOk),
+			test_response.BeStatus(response.StatusOk),
+			test_response.BeStatus(response.StatusBadRequest),
+			test_response.BeStatus(response.StatusOk),
+			test_response.BeStatus(response.StatusOk),
+			test_response.BeStatus(response.StatusBadRequest),
+		},
+	)
+}
@@ -0,0 +1,111 @@
+package integration_suite
+
+import (
+	"net"
+	"server/internal/client"
+	"server/internal/interfaces"
+	"server/internal/rpc/request/request_constructor"
+	"server/internal/rpc/response"
+	"server/internal/server"
+	"server/tests/test_response"
+	"server/tests/test_server"
+	"testing"
+	"time"
+)
+
+func skipWithoutIpV6Loopback(t *testing.T) {
+	t.Helper()
+	conn, err := net.ListenUDP("udp6", &net.UDPAddr{IP: net.IPv6loopback})
+	if err != nil {
+		t.Skip("IPv6 loopback is not available")
+	}
+	_ = conn.Close()
+}
+
+func TestIpV6_createFacility(t *testing.T) {
+	skipWithoutIpV6Loopback(t)
+
+	serverPort := test_server.ServeRandomPort(t, server.WithBindAddresses("::1"))
+
+	c, err := client.NewClient(
+		client.WithClientName("TestIpV6_createFacility"),
+		client.WithTargetAsIpV6("::1", serverPort),
+		client.WithTimeout(time.Duration(15)*time.Second),
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer c.Close()
+
+	c.SendSyncWithValidator(
+		t,
+		[]interfaces.RpcRequestConstructor{
+			request_constructor.NewFacilityCreatePacket("TestIpV6_createFacility"),
+			request_constructor.NewFacilityCreatePacket("TestIpV6_createFacility"),
+		},
+		[]test_response.ResponseValidator{
+			test_response.BeStatus(response.StatusOk),
+			test_response.BeStatus(response.StatusBadRequest),
+		},
+	)
+}
+
+// createFacilityFromEachTarget creates the same facility from a new client for each target. Only the first creation
+// must succeed, which shows that all targets are served by the same server.
+func createFacilityFromEachTarget(t *testing.T, name string, targets ...client.NewClientOpt) {
+	t.Helper()
+
+	for i, target := range targets {
+		c, err := client.NewClient(
+			client.WithClientName(name),
+			target,
+			client.WithTimeout(time.Duration(15)*time.Second),
+		)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		status := response.StatusBadRequest
+		if i == 0 {
+			status = response.StatusOk
+		}
+
+		c.SendSyncWithValidator(
+			t,
+			[]interfaces.RpcRequestConstructor{
+				request_constructor.NewFacilityCreatePacket(name),
+			},
+			[]test_response.ResponseValidator{
+				test_response.BeStatus(status),
+			},
+		)
+
+		c.Close()
+	}
+}
+
+func TestIpV6_dualStack(t *testing.T) {
+	skipWithoutIpV6Loopback(t)
+
+	serverPort := test_server.ServeRandomPort(t, server.WithBindAddresses("[::]"))
+
+	createFacilityFromEachTarget(
+		t,
+		"TestIpV6_dualStack",
+		client.WithTargetAsIpV6("::1", serverPort),
+		client.WithTargetAsIpV4("127.0.0.1", serverPort),
+	)
+}
+
+func TestIpV6_multipleAddresses(t *testing.T) {
+	skipWithoutIpV6Loopback(t)
+
+	serverPort := test_server.ServeRandomPort(t, server.WithBindAddresses("127.0.0.1", "::1"))
+
+	createFacilityFromEachTarget(
+		t,
+		"TestIpV6_multipleAddresses",
+		client.WithTargetAsIpV4("127.0.0.1", serverPort),
+		client.WithTargetAsIpV6("::1", serverPort),
+	)
+}
@@ -0,0 +1,159 @@
+package integration_suite
+
+import (
+	"net"
+	"os"
+	"path/filepath"
+	"server/internal/protocol"
+	"server/internal/protocol/proto_defs"
+	"server/internal/rpc/request/request_constructor"
+	"server/internal/rpc/response"
+	"server/internal/server"
+	"server/internal/store"
+	"server/internal/vars"
+	"server/tests/test_server"
+	"testing"
+	"time"
+)
+
+// sendWithoutAck sends packets to port from conn, and returns the first response received. The response is never
+// acknowledged, leaving the exchange incomplete as if the client had not received it.
+func sendWithoutAck(t *testing.T, conn *net.UDPConn, port int, packets []*protocol.Packet) *response.Response {
+	t.Helper()
+
+	target := &net.UDPAddr{IP: net.IPv4(127, 0, 0, 1), Port: port}
+	for _, p := range packets {
+		data, err := p.MarshalBinary()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if _, err := conn.WriteToUDP(data, target); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	buf := make([]byte, proto_defs.PacketSizeLimit)
+	_ = conn.SetReadDeadline(time.Now().Add(time.Duration(5) * time.Second))
+	for {
+		n, _, err := conn.ReadFromUDP(buf)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		var p protocol.Packet
+		if err := p.UnmarshalBinary(buf[:n]); err != nil || p.Header.MessageType != proto_defs.MessageTypeResponse {
+			continue // Acknowledgement of the request
+		}
+
+		var r response.Response
+		if err := r.UnmarshalBinary(p.Payload); err != nil {
+			t.Fatal(err)
+		}
+		return &r
+	}
+}
+
+// killAndRetransmit creates a facility, kills the server before the response is acknowledged, then retransmits the
+// identical request to a server restarted on the same data directory. Returns the response to the retransmission.
+func killAndRetransmit(t *testing.T, name string, persistResponses bool) *response.Response {
+	t.Helper()
+
+	// Packets must not be dropped, as the exchange is driven without retransmissions
+	env := vars.GetStaticEnvCopy()
+	env.PacketDropRate = 0
+	opts := []server.Option{
+		server.WithEnv(env),
+		server.WithDataDir(t.TempDir()),
+		server.WithPersistResponses(persistResponses),
+	}
+
+	conn, err := net.ListenUDP("udp4", &net.UDPAddr{IP: net.IPv4(127, 0, 0, 1)})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer conn.Close()
+
+	packets, err := request_constructor.NewFacilityCreatePacket(name)()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	s := test_server.StartRandomPort(t, opts...)
+	if r := sendWithoutAck(t, conn, s.Port(), packets); r.StatusCode != response.StatusOk {
+		t.Fatalf("E: %d, R: %d", response.StatusOk, r.StatusCode)
+	}
+	s.Close()
+
+	restarted := test_server.StartRandomPort(t, opts...)
+	return sendWithoutAck(t, conn, restarted.Port(), packets)
+}
+
+func TestPersistence_responsesAcrossRestart(t *testing.T) {
+	r := killAndRetransmit(t, "TestPersistence_responsesAcrossRestart", true)
+
+	// The original response is replayed, rather than the facility being created again
+	if r.StatusCode != response.StatusOk {
+		t.Errorf("E: %d, R: %d (%s)", response.StatusOk, r.StatusCode, r.Payload)
+	}
+}
+
+func TestPersistence_responsesNotPersisted(t *testing.T) {
+	r := killAndRetransmit(t, "TestPersistence_responsesNotPersisted", false)
+
+	// Without the reply cache, the retransmission is executed again
+	if r.StatusCode != response.StatusBadRequest {
+		t.Errorf("E: %d, R: %d (%s)", response.StatusBadRequest, r.StatusCode, r.Payload)
+	}
+}
+
+func TestPersistence_responseLostInCrash(t *testing.T) {
+	name := "TestPersistence_responseLostInCrash"
+	dir := t.TempDir()
+
+	env := vars.GetStaticEnvCopy()
+	env.PacketDropRate = 0
+	opts := []server.Option{
+		server.WithEnv(env),
+		server.WithDataDir(dir),
+		server.WithPersistResponses(true),
+	}
+
+	conn, err := net.ListenUDP("udp4", &net.UDPAddr{IP: net.IPv4(127, 0, 0, 1)})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer conn.Close()
+
+	packets, err := request_constructor.NewFacilityCreatePacket(name)()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	s := test_server.StartRandomPort(t, opts...)
+	if r := sendWithoutAck(t, conn, s.Port(), packets); r.StatusCode != response.StatusOk {
+		t.Fatalf("E: %d, R: %d", response.StatusOk, r.StatusCode)
+	}
+	s.Close()
+
+	// Simulate a crash after the facility was created, but before its response was recorded in the reply cache
+	path := filepath.Join(dir, "responses.log")
+	bodies, _, err := store.ReadFrames(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var kept []byte
+	for _, body := range bodies {
+		if response.JournalRecordType(body[0]) != response.JournalRecordResponse {
+			kept = append(kept, store.Frame(body)...)
+		}
+	}
+	if err := os.WriteFile(path, kept, 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	// The retransmission is answered with an error, rather than being executed again
+	restarted := test_server.StartRandomPort(t, opts...)
+	if r := sendWithoutAck(t, conn, restarted.Port(), packets); r.StatusCode != response.StatusInternalServerError {
+		t.Errorf("E: %d, R: %d (%s)", response.StatusInternalServerError, r.StatusCode, r.Payload)
+	}
+}
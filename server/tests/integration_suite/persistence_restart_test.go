@@ -0,0 +1,76 @@
+package integration_suite
+
+import (
+	"context"
+	"server/internal/client"
+	"server/internal/interfaces"
+	"server/internal/rpc/request/request_constructor"
+	"server/internal/rpc/response"
+	"server/internal/server"
+	"server/tests/test_response"
+	"server/tests/test_server"
+	"testing"
+	"time"
+)
+
+// createFacility creates a facility on the server at port, expecting the given status.
+func createFacility(t *testing.T, port int, name string, status response.StatusCode) {
+	t.Helper()
+
+	c, err := client.NewClient(
+		client.WithClientName(name),
+		client.WithTargetAsIpV4("127.0.0.1", port),
+		client.WithTimeout(time.Duration(15)*time.Second),
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer c.Close()
+
+	c.SendSyncWithValidator(
+		t,
+		[]interfaces.RpcRequestConstructor{
+			request_constructor.NewFacilityCreatePacket(name),
+		},
+		[]test_response.ResponseValidator{
+			test_response.BeStatus(status),
+		},
+	)
+}
+
+func TestPersistence_restartAfterKill(t *testing.T) {
+
+	dataDir := t.TempDir()
+
+	s := test_server.StartRandomPort(t, server.WithDataDir(dataDir))
+	createFacility(t, s.Port(), "TestPersistence_restartAfterKill", response.StatusOk)
+
+	// Closing without shutting down leaves no snapshot, the facility must be restored from the WAL
+	s.Close()
+	if s.Store() != nil && s.Store().Info().SnapshotSeq != 0 {
+		t.Error("Expected no snapshot to be taken when killed")
+	}
+
+	restarted := test_server.StartRandomPort(t, server.WithDataDir(dataDir))
+	createFacility(t, restarted.Port(), "TestPersistence_restartAfterKill", response.StatusBadRequest)
+}
+
+func TestPersistence_restartAfterShutdown(t *testing.T) {
+
+	dataDir := t.TempDir()
+
+	s := test_server.StartRandomPort(t, server.WithDataDir(dataDir))
+	createFacility(t, s.Port(), "TestPersistence_restartAfterShutdown", response.StatusOk)
+
+	// The client may have closed before acknowledging the response, which cannot be flushed; the snapshot is
+	// taken regardless
+	ctx, cancel := context.WithTimeout(context.Background(), time.Duration(2)*time.Second)
+	defer cancel()
+	_ = s.Shutdown(ctx)
+
+	restarted := test_server.StartRandomPort(t, server.WithDataDir(dataDir))
+	if info := restarted.Store().Info(); info.SnapshotSeq != 1 || info.WalRecords != 0 {
+		t.Errorf("Expected facility to be restored from the snapshot taken on shutdown, got %+v", info)
+	}
+	createFacility(t, restarted.Port(), "TestPersistence_restartAfterShutdown", response.StatusBadRequest)
+}
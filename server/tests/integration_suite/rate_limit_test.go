@@ -0,0 +1,148 @@
+package integration_suite
+
+import (
+	"server/internal/client"
+	"server/internal/interfaces"
+	"server/internal/rpc/request/request_constructor"
+	"server/internal/rpc/response"
+	"server/internal/server"
+	"server/internal/vars"
+	"server/tests/test_response"
+	"server/tests/test_server"
+	"testing"
+	"time"
+)
+
+func TestRateLimit_global(t *testing.T) {
+
+	env := vars.GetStaticEnvCopy()
+	env.RateLimitRate = 0.1
+	env.RateLimitBurst = 2
+
+	serverPort := test_server.ServeRandomPort(t, server.WithEnv(env))
+
+	c, err := client.NewClient(
+		client.WithClientName("TestRateLimit_global"),
+		client.WithTargetAsIpV4("127.0.0.1", serverPort),
+		client.WithTimeout(time.Duration(15)*time.Second),
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer c.Close()
+
+	c.SendSyncWithValidator(
+		t,
+		[]interfaces.RpcRequestConstructor{
+			request_constructor.NewFacilityCreatePacket("TestRateLimit_global_1"),
+			request_constructor.NewFacilityCreatePacket("TestRateLimit_global_2"),
+			request_constructor.NewFacilityCreatePacket("TestRateLimit_global_3"),
+		},
+		[]test_response.ResponseValidator{
+			test_response.BeStatus(response.StatusOk),
+			test_response.BeStatus(response.StatusOk),
+			test_response.PacketMustPassAll(
+				test_response.BeStatus(response.StatusTooManyRequests),
+				test_response.HaveRetryAfter(),
+			),
+		},
+	)
+
+}
+
+func TestRateLimit_perMethod(t *testing.T) {
+
+	env := vars.GetStaticEnvCopy()
+	env.RateLimitMethodRates = map[string]float64{"FacilityCreate": 0.1}
+	env.RateLimitMethodBursts = map[string]int{"FacilityCreate": 1}
+
+	serverPort := test_server.ServeRandomPort(t, server.WithEnv(env))
+
+	c, err := client.NewClient(
+		client.WithClientName("TestRateLimit_perMethod"),
+		client.WithTargetAsIpV4("127.0.0.1", serverPort),
+		client.WithTimeout(time.Duration(15)*time.Second),
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer c.Close()
+
+	// Only FacilityCreate is limited, other methods are unaffected
+	c.SendSyncWithValidator(
+		t,
+		[]interfaces.RpcRequestConstructor{
+			request_constructor.NewFacilityCreatePacket("TestRateLimit_perMethod"),
+			request_constructor.NewFacilityCreatePacket("TestRateLimit_perMethod_limited"),
+			request_constructor.NewFacilityDeletePacket("TestRateLimit_perMethod"),
+		},
+		[]test_response.ResponseValidator{
+			test_response.BeStatus(response.StatusOk),
+			test_response.PacketMustPassAll(
+				test_response.BeStatus(response.StatusTooManyRequests),
+				test_response.HaveRetryAfter(),
+			),
+			test_response.BeStatus(response.StatusOk),
+		},
+	)
+
+}
+
+func TestRateLimit_perPrincipal(t *testing.T) {
+
+	env := vars.GetStaticEnvCopy()
+	env.RateLimitRate = 0.1
+	env.RateLimitBurst = 2
+
+	serverPort := test_server.ServeRandomPort(t, server.WithEnv(env))
+
+	newClient := func(name string) *client.Client {
+		c, err := client.NewClient(
+			client.WithClientName(name),
+			client.WithTargetAsIpV4("127.0.0.1", serverPort),
+			client.WithTimeout(time.Duration(15)*time.Second),
+		)
+		if err != nil {
+			t.Fatal(err)
+		}
+		t.Cleanup(c.Close)
+		return c
+	}
+	first := newClient("TestRateLimit_perPrincipal_1")
+	second := newClient("TestRateLimit_perPrincipal_2")
+
+	// Rotating principals does not evade the limit of the address
+	first.SendSyncWithValidator(
+		t,
+		[]interfaces.RpcRequestConstructor{
+			request_constructor.AsPrincipal("alice", request_constructor.NewFacilityCreateV2Packet("TestRateLimit_perPrincipal_1", 1)),
+			request_constructor.AsPrincipal("alice", request_constructor.NewFacilityCreateV2Packet("TestRateLimit_perPrincipal_2", 1)),
+			request_constructor.AsPrincipal("bob", request_constructor.NewFacilityCreateV2Packet("TestRateLimit_perPrincipal_3", 1)),
+		},
+		[]test_response.ResponseValidator{
+			test_response.BeStatus(response.StatusOk),
+			test_response.BeStatus(response.StatusOk),
+			test_response.PacketMustPassAll(
+				test_response.BeStatus(response.StatusTooManyRequests),
+				test_response.HaveRetryAfter(),
+			),
+		},
+	)
+
+	// The principal shares its limit across addresses, while other principals at another address are unaffected
+	second.SendSyncWithValidator(
+		t,
+		[]interfaces.RpcRequestConstructor{
+			request_constructor.AsPrincipal("alice", request_constructor.NewFacilityCreateV2Packet("TestRateLimit_perPrincipal_4", 1)),
+			request_constructor.AsPrincipal("bob", request_constructor.NewFacilityCreateV2Packet("TestRateLimit_perPrincipal_5", 1)),
+		},
+		[]test_response.ResponseValidator{
+			test_response.PacketMustPassAll(
+				test_response.BeStatus(response.StatusTooManyRequests),
+				test_response.HaveRetryAfter(),
+			),
+			test_response.BeStatus(response.StatusOk),
+		},
+	)
+
+}
@@ -0,0 +1,96 @@
+package integration_suite
+
+import (
+	"server/internal/bookings"
+	"server/internal/client"
+	"server/internal/interfaces"
+	"server/internal/rpc/request"
+	"server/internal/rpc/request/request_constructor"
+	"server/internal/rpc/response"
+	"server/tests/test_response"
+	"server/tests/test_server"
+	"testing"
+	"time"
+)
+
+func TestSeriesBooking_weekly(t *testing.T) {
+
+	name := "TestSeriesBooking_weekly"
+	serverPort := test_server.ServeRandomPort(t)
+
+	c, err := client.NewClient(
+		client.WithClientName(name),
+		client.WithTargetAsIpV4("127.0.0.1", serverPort),
+		client.WithTimeout(time.Duration(15)*time.Second),
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer c.Close()
+
+	// Every Tuesday 18:00 to 20:00, for 4 weeks except the second
+	now := time.Now()
+	start := time.Date(now.Year(), now.Month(), now.Day()+1, 18, 0, 0, 0, time.Local)
+	for start.Weekday() != time.Tuesday {
+		start = start.AddDate(0, 0, 1)
+	}
+	series := &request.SeriesMakePayload{
+		Name:       bookings.FacilityName(name),
+		Frequency:  bookings.FrequencyWeekly,
+		Start:      start,
+		End:        start.Add(time.Duration(2) * time.Hour),
+		Count:      4,
+		Exceptions: []time.Time{start.AddDate(0, 0, 7)},
+	}
+
+	seriesId := make(chan uint16, 1)
+	occurrenceIds := make(chan []uint16, 1)
+
+	c.SendSyncWithValidator(
+		t,
+		[]interfaces.RpcRequestConstructor{
+			request_constructor.NewFacilityCreatePacket(name),
+			request_constructor.NewSeriesMakePacket(series),
+			// Clashes with the third occurrence, so none of its occurrences are booked
+			request_constructor.NewSeriesMakePacket(&request.SeriesMakePayload{
+				Name:      bookings.FacilityName(name),
+				Frequency: bookings.FrequencyDaily,
+				Start:     start.AddDate(0, 0, 13),
+				End:       start.AddDate(0, 0, 13).Add(time.Hour),
+				Until:     start.AddDate(0, 0, 15),
+			}),
+			request_constructor.NewBookingMakePacket(name, start.AddDate(0, 0, 13), start.AddDate(0, 0, 13).Add(time.Hour)),
+		},
+		[]test_response.ResponseValidator{
+			test_response.BeStatus(response.StatusOk),
+			test_response.PacketMustPassAll(
+				test_response.BeStatus(response.StatusOk),
+				test_response.ExtractSeries(3, seriesId, occurrenceIds),
+			),
+			test_response.BeStatus(response.StatusBadRequest),
+			test_response.BeStatus(response.StatusOk),
+		},
+	)
+
+	id, ids := <-seriesId, <-occurrenceIds
+
+	c.SendSyncWithValidator(
+		t,
+		[]interfaces.RpcRequestConstructor{
+			// A single occurrence is shifted and cancelled with the booking methods
+			request_constructor.NewBookingModifyV2Packet(ids[0], time.Duration(30)*time.Minute),
+			request_constructor.NewBookingDeletePacket(ids[1]),
+			// The whole series is shifted and cancelled with the series methods
+			request_constructor.NewSeriesModifyPacket(id, time.Duration(-1)*time.Hour),
+			request_constructor.NewSeriesDeletePacket(id),
+			request_constructor.NewSeriesDeletePacket(id),
+		},
+		[]test_response.ResponseValidator{
+			test_response.BeStatus(response.StatusOk),
+			test_response.BeStatus(response.StatusOk),
+			test_response.BeStatus(response.StatusOk),
+			test_response.BeStatus(response.StatusOk),
+			test_response.BeStatus(response.StatusBadRequest),
+		},
+	)
+}
@@ -0,0 +1,84 @@
+package integration_suite
+
+import (
+	"fmt"
+	"io"
+	"log/slog"
+	"runtime"
+	"server/internal/client"
+	"server/internal/interfaces"
+	"server/internal/rpc/request/request_constructor"
+	"server/internal/rpc/response"
+	"server/internal/server"
+	"server/internal/vars"
+	"server/tests/test_load"
+	"server/tests/test_response"
+	"server/tests/test_server"
+	"testing"
+	"time"
+)
+
+func TestServe_multipleSockets(t *testing.T) {
+	if runtime.GOOS != "linux" {
+		t.Skip("SO_REUSEPORT is only supported on linux")
+	}
+
+	serverPort := test_server.ServeRandomPort(t, server.WithSockets(4))
+
+	// Clients are bound to different source ports, and are therefore likely to be served by different sockets;
+	// the duplicate is only detected if the sockets share the booking state
+	statuses := []response.StatusCode{response.StatusOk, response.StatusBadRequest}
+	for i, status := range statuses {
+		c, err := client.NewClient(
+			client.WithClientName(fmt.Sprintf("TestServe_multipleSockets_%d", i)),
+			client.WithTargetAsIpV4("127.0.0.1", serverPort),
+			client.WithTimeout(time.Duration(15)*time.Second),
+		)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		c.SendSyncWithValidator(
+			t,
+			[]interfaces.RpcRequestConstructor{
+				request_constructor.NewFacilityCreatePacket("TestServe_multipleSockets"),
+			},
+			[]test_response.ResponseValidator{
+				test_response.BeStatus(status),
+			},
+		)
+
+		c.Close()
+	}
+
+	test_load.Generate(t, serverPort, 8, 4)
+}
+
+// BenchmarkServe_sockets measures request throughput with an increasing number of sockets bound to the same port.
+// Run with e.g. `go test ./tests/integration_suite -run ^$ -bench BenchmarkServe_sockets -cpu 1,4,8`.
+func BenchmarkServe_sockets(b *testing.B) {
+	if runtime.GOOS != "linux" {
+		b.Skip("SO_REUSEPORT is only supported on linux")
+	}
+
+	// Server logs every packet, which would otherwise dominate the measurements
+	defaultLogger := slog.Default()
+	slog.SetDefault(slog.New(slog.NewTextHandler(io.Discard, nil)))
+	b.Cleanup(func() { slog.SetDefault(defaultLogger) })
+
+	// Dropped packets are resent after a timeout, which would otherwise dominate the measurements
+	env := vars.GetStaticEnvCopy()
+	env.PacketDropRate = 0
+	env.RateLimitRate = 0
+
+	const clients = 32
+
+	for _, sockets := range []int{1, 2, 4, 8} {
+		b.Run(fmt.Sprintf("sockets=%d", sockets), func(b *testing.B) {
+			serverPort := test_server.ServeRandomPort(b, server.WithEnv(env), server.WithSockets(sockets))
+
+			b.ResetTimer()
+			test_load.Generate(b, serverPort, clients, max(1, b.N/clients))
+		})
+	}
+}
@@ -0,0 +1,60 @@
+package integration_suite
+
+import (
+	"context"
+	"server/internal/client"
+	"server/internal/interfaces"
+	"server/internal/rpc/request/request_constructor"
+	"server/internal/rpc/response"
+	"server/internal/server"
+	"server/tests/test_response"
+	"testing"
+	"time"
+)
+
+func TestShutdownServer_graceful(t *testing.T) {
+
+	s, err := server.New(server.WithPort(0))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	served := make(chan struct{})
+	go func() {
+		defer close(served)
+		if err := s.Serve(ctx); err != nil {
+			t.Error(err)
+		}
+	}()
+
+	c, err := client.NewClient(
+		client.WithClientName("TestShutdownServer_graceful"),
+		client.WithTargetAsIpV4("127.0.0.1", s.Port()),
+		client.WithTimeout(time.Duration(15)*time.Second),
+	)
+	if err != nil {
+		t.Error(err)
+	}
+	defer c.Close()
+
+	c.SendSyncWithValidator(
+		t,
+		[]interfaces.RpcRequestConstructor{
+			request_constructor.NewFacilityCreatePacket("TestShutdownServer_graceful"),
+		},
+		[]test_response.ResponseValidator{
+			test_response.BeStatus(response.StatusOk),
+		},
+	)
+
+	// Cancelling the context should shut the server down within the grace period
+	cancel()
+
+	select {
+	case <-served:
+	case <-time.After(time.Duration(15) * time.Second):
+		t.Error("Server did not shut down within the grace period")
+	}
+
+}
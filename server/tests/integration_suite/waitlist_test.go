@@ -0,0 +1,82 @@
+package integration_suite
+
+import (
+	"server/internal/client"
+	"server/internal/interfaces"
+	"server/internal/rpc/request/request_constructor"
+	"server/internal/rpc/response"
+	"server/tests/test_response"
+	"server/tests/test_server"
+	"testing"
+	"time"
+)
+
+func TestWaitlist_bookedOnceClashIsDeleted(t *testing.T) {
+
+	name := "TestWaitlist_bookedOnceClashIsDeleted"
+	serverPort := test_server.ServeRandomPort(t)
+
+	c, err := client.NewClient(
+		client.WithClientName(name),
+		client.WithTargetAsIpV4("127.0.0.1", serverPort),
+		client.WithTimeout(time.Duration(15)*time.Second),
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer c.Close()
+
+	now := time.Now()
+	tmr := time.Date(now.Year(), now.Month(), now.Day()+1, 0, 0, 0, 0, time.Local)
+	start := tmr.Add(time.Duration(10) * time.Hour)
+
+	bidChan := make(chan uint64, 3)
+
+	c.SendSyncWithValidator(
+		t,
+		[]interfaces.RpcRequestConstructor{
+			request_constructor.NewFacilityCreatePacket(name),
+			request_constructor.NewBookingMakeV3Packet(name, start, start.Add(time.Hour)),
+			request_constructor.NewWaitlistJoinPacket(name, start, start.Add(time.Hour)),
+			request_constructor.NewWaitlistJoinPacket(name, start, start.Add(time.Hour)),
+		},
+		[]test_response.ResponseValidator{
+			test_response.BeStatus(response.StatusOk),
+			test_response.PacketMustPassAll(
+				test_response.BeStatus(response.StatusOk),
+				test_response.ExtractWideBookingId(bidChan),
+			),
+			test_response.PacketMustPassAll(
+				test_response.BeStatus(response.StatusAccepted),
+				test_response.ExtractWideBookingId(bidChan),
+			),
+			test_response.PacketMustPassAll(
+				test_response.BeStatus(response.StatusAccepted),
+				test_response.ExtractWideBookingId(bidChan),
+			),
+		},
+	)
+
+	bid, first, second := <-bidChan, <-bidChan, <-bidChan
+
+	c.SendSyncWithValidator(
+		t,
+		[]interfaces.RpcRequestConstructor{
+			// The second waitlisted booking gives up, the first one is booked once the booking is deleted
+			request_constructor.NewWaitlistLeavePacket(second),
+			request_constructor.NewBookingDeleteV2Packet(bid),
+			request_constructor.NewWaitlistLeavePacket(first),
+			request_constructor.NewBookingModifyV3Packet(first, time.Duration(30)*time.Minute),
+
+			// Waitlisted bookings that do not clash are booked right away
+			request_constructor.NewWaitlistJoinPacket(name, start.Add(time.Duration(2)*time.Hour), start.Add(time.Duration(3)*time.Hour)),
+		},
+		[]test_response.ResponseValidator{
+			test_response.BeStatus(response.StatusOk),
+			test_response.BeStatus(response.StatusOk),
+			test_response.BeStatus(response.StatusBadRequest),
+			test_response.BeStatus(response.StatusOk),
+			test_response.BeStatus(response.StatusOk),
+		},
+	)
+}
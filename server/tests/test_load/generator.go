@@ -0,0 +1,65 @@
+package test_load
+
+import (
+	"fmt"
+	"io"
+	"log/slog"
+	"server/internal/client"
+	"server/internal/rpc/request/request_constructor"
+	"server/internal/rpc/response"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+var facilityCounter atomic.Uint64
+
+// Generate drives load against the server listening on port. Each of the clients runs concurrently from its own
+// socket, sending requests one after another and waiting for each response before sending the next.
+// Every request creates a new facility, so that each request results in a successful write to the booking state.
+func Generate(tb testing.TB, port int, clients int, requests int) {
+	tb.Helper()
+
+	logger := slog.New(slog.NewTextHandler(io.Discard, nil))
+
+	wg := sync.WaitGroup{}
+	for i := 0; i < clients; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+
+			c, err := client.NewClient(
+				client.WithClientName(fmt.Sprintf("LOAD-%d", i)),
+				client.WithTargetAsIpV4("127.0.0.1", port),
+				client.WithTimeout(time.Duration(60)*time.Second),
+				client.WithLogger(logger),
+			)
+			if err != nil {
+				tb.Error(err)
+				return
+			}
+			defer c.Close()
+
+			for j := 0; j < requests; j++ {
+				name := fmt.Sprintf("LOAD-%d", facilityCounter.Add(1))
+				if err := c.SendRpcRequestConstructors(request_constructor.NewFacilityCreatePacket(name)); err != nil {
+					tb.Error(err)
+					return
+				}
+
+				select {
+				case <-c.Ctx.Done():
+					tb.Errorf("Client %d timed out after %d responses", i, j)
+					return
+				case r := <-c.Responses:
+					if r.StatusCode != response.StatusOk {
+						tb.Errorf("Expected status %d, got %d", response.StatusOk, r.StatusCode)
+					}
+				}
+			}
+		}()
+	}
+
+	wg.Wait()
+}
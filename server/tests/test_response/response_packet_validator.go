@@ -1,12 +1,15 @@
 package test_response
 
 import (
+	"bytes"
 	"encoding/binary"
 	"errors"
 	"fmt"
 	"reflect"
 	"runtime"
+	"server/internal/bookings"
 	"server/internal/rpc/response"
+	"time"
 )
 
 type ResponseValidator func(response *response.Response) error
@@ -72,3 +75,176 @@ func ExtractBookingId(c chan uint16) ResponseValidator {
 		return nil
 	}
 }
+
+// ExtractWideBookingId validates that the response carries a 64 bit booking Id, and sends it to c
+func ExtractWideBookingId(c chan uint64) ResponseValidator {
+	return func(r *response.Response) error {
+		if len(r.Payload) != 8 {
+			return fmt.Errorf("expected 8 bytes payload, received %v bytes", len(r.Payload))
+		}
+
+		c <- binary.BigEndian.Uint64(r.Payload)
+
+		return nil
+	}
+}
+
+// HaveRetryAfter validates that the response tells the client to retry after a non-zero duration
+func HaveRetryAfter() ResponseValidator {
+	return func(r *response.Response) error {
+		retryAfter, err := r.RetryAfter()
+		if err != nil {
+			return err
+		}
+		if retryAfter <= 0 {
+			return fmt.Errorf("expected positive retry after, received %v", retryAfter)
+		}
+		return nil
+	}
+}
+
+// HavePayload validates that the response payload matches expected exactly
+func HavePayload(expected []byte) ResponseValidator {
+	return func(r *response.Response) error {
+		if !bytes.Equal(r.Payload, expected) {
+			return fmt.Errorf("payload does not match, E: % X, R: % X", expected, r.Payload)
+		}
+		return nil
+	}
+}
+
+// ExtractSeries validates that the response describes a series with n occurrences, and sends the Id of the series
+// and the Ids of its occurrences to the given channels
+func ExtractSeries(n int, seriesId chan uint16, occurrenceIds chan []uint16) ResponseValidator {
+	return func(r *response.Response) error {
+		id, ids, err := r.Series()
+		if err != nil {
+			return err
+		}
+		if len(ids) != n {
+			return fmt.Errorf("expected %d occurrences, received %d", n, len(ids))
+		}
+
+		seriesId <- id
+		occurrenceIds <- ids
+
+		return nil
+	}
+}
+
+// ExtractHold validates that the response describes a held booking that is released in the future, and sends the Id
+// of the booking to c
+func ExtractHold(c chan uint64) ResponseValidator {
+	return func(r *response.Response) error {
+		id, heldUntil, err := r.Hold()
+		if err != nil {
+			return err
+		}
+		if !heldUntil.After(time.Now()) {
+			return fmt.Errorf("expected booking to be held in the future, held until %v", heldUntil)
+		}
+
+		c <- id
+
+		return nil
+	}
+}
+
+// ExtractMulti validates that the response describes n bookings made together, and sends their Ids to c
+func ExtractMulti(n int, c chan []uint64) ResponseValidator {
+	return func(r *response.Response) error {
+		ids, err := r.Multi()
+		if err != nil {
+			return err
+		}
+		if len(ids) != n {
+			return fmt.Errorf("expected %d bookings, received %d", n, len(ids))
+		}
+
+		c <- ids
+
+		return nil
+	}
+}
+
+// HaveConflictAt validates that the response is a conflict with the booking at the given index
+func HaveConflictAt(index int) ResponseValidator {
+	return func(r *response.Response) error {
+		i, _, err := r.Conflict()
+		if err != nil {
+			return err
+		}
+		if i != index {
+			return fmt.Errorf("expected conflict with booking %d, received %d", index, i)
+		}
+		return nil
+	}
+}
+
+// ExtractSlots validates that the response holds n free periods, and sends them to c
+func ExtractSlots(n int, c chan []bookings.Interval) ResponseValidator {
+	return func(r *response.Response) error {
+		slots, err := r.Slots()
+		if err != nil {
+			return err
+		}
+		if len(slots) != n {
+			return fmt.Errorf("expected %d free periods, received %d", n, len(slots))
+		}
+
+		c <- slots
+
+		return nil
+	}
+}
+
+// ExtractAudit validates that the response holds n entries of the audit log, and sends them to c
+func ExtractAudit(n int, c chan []bookings.AuditEntry) ResponseValidator {
+	return func(r *response.Response) error {
+		entries, _, err := r.Audit()
+		if err != nil {
+			return err
+		}
+		if len(entries) != n {
+			return fmt.Errorf("expected %d audit entries, received %d", n, len(entries))
+		}
+
+		c <- entries
+
+		return nil
+	}
+}
+
+// ExtractFacilities validates whether more facilities follow the listed ones, and sends the listed facilities to c
+func ExtractFacilities(more bool, c chan []bookings.FacilitySummary) ResponseValidator {
+	return func(r *response.Response) error {
+		facilities, m, err := r.Facilities()
+		if err != nil {
+			return err
+		}
+		if m != more {
+			return fmt.Errorf("expected more facilities to follow: %v, received %v", more, m)
+		}
+
+		c <- facilities
+
+		return nil
+	}
+}
+
+// ExtractBookings validates whether more bookings follow the listed ones, and sends the listed bookings to c
+func ExtractBookings(more bool, c chan []bookings.Booking) ResponseValidator {
+	return func(r *response.Response) error {
+		listed, m, err := r.Bookings()
+		if err != nil {
+			return err
+		}
+		if m != more {
+			return fmt.Errorf("expected more bookings to follow: %v, received %v", more, m)
+		}
+
+		c <- listed
+
+		return nil
+	}
+}
@@ -0,0 +1,32 @@
+package test_server
+
+import (
+	"context"
+	"server/internal/server"
+	"testing"
+)
+
+// StartRandomPort starts an isolated server on a random port, which is closed once the test completes.
+// Returns the server, so that tests can stop it before then (e.g. to restart it).
+func StartRandomPort(t testing.TB, opts ...server.Option) *server.Server {
+	t.Helper()
+
+	s, err := server.New(append([]server.Option{server.WithPort(0)}, opts...)...)
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(s.Close)
+
+	go func() {
+		_ = s.Serve(context.Background())
+	}()
+
+	return s
+}
+
+// ServeRandomPort starts an isolated server on a random port, which is closed once the test completes.
+// Returns the port that the server is listening on.
+func ServeRandomPort(t testing.TB, opts ...server.Option) int {
+	t.Helper()
+	return StartRandomPort(t, opts...).Port()
+}